@@ -4,75 +4,101 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/jackc/pgx/v5"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/config"
+	"gitlab.com/fluxx1on_group/event_message_service/migrations"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/migrate"
 )
 
+const usage = `Usage: migrate <command> [arg]
+
+Commands:
+  up         apply all the pending migrations
+  down [N]   revert N last migrations (default 1)
+  status     print current version and pending migrations
+  goto V     migrate up or down to version V, 0 is empty schema
+  force V    set version V without applying migrations
+`
+
 func main() {
-	migrate := flag.Bool("m", false, "Run database migration")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
 	flag.Parse()
 
-	if *migrate {
-		Migrate()
-	} else {
-		Drop()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
 	}
-}
 
-func Migrate() {
-	filename := "migrations/db.sql"
-
-	conn, err := pgx.Connect(context.Background(), config.NewDB().URL)
+	ms, err := migrate.Load(migrations.Postgres, migrations.PostgresDir)
 	if err != nil {
-		log.Fatalf("Unable to connect to database: %v", err)
+		log.Fatalf("Unable to load migrations: %v", err)
 	}
-	defer conn.Close(context.Background())
 
-	dbFile, err := os.Open(filename)
-	if err != nil {
-		log.Fatalf("Unable to open %v file %v", filename, err)
-	}
+	ctx := context.Background()
 
-	migrationSQL, err := io.ReadAll(dbFile)
+	conn, err := pgx.Connect(ctx, config.NewDB().URL)
 	if err != nil {
-		log.Fatalf("Unable to read %v file: %v", filename, err)
+		log.Fatalf("Unable to connect to database: %v", err)
+	}
+	defer conn.Close(ctx)
+
+	m := migrate.New(conn, ms)
+
+	switch cmd := flag.Arg(0); cmd {
+	case "up":
+		err = m.Up(ctx)
+	case "down":
+		err = m.Down(ctx, argInt(1))
+	case "goto":
+		err = m.Goto(ctx, argInt(-1))
+	case "force":
+		err = m.Force(ctx, argInt(-1))
+	case "status":
+		err = printStatus(ctx, m)
+	default:
+		flag.Usage()
+		log.Fatalf("Unknown command %q", cmd)
 	}
-
-	_, err = conn.Exec(context.Background(), string(migrationSQL))
 	if err != nil {
-		log.Fatalf("Migration failed: %v", err)
+		log.Fatalf("Migration %s failed: %v", flag.Arg(0), err)
 	}
 
-	fmt.Println("Migrations successfully applied.")
+	if flag.Arg(0) != "status" {
+		fmt.Printf("Migration %s successfully applied.\n", flag.Arg(0))
+	}
 }
 
-func Drop() {
-	filename := "migrations/drop_db.sql"
-
-	conn, err := pgx.Connect(context.Background(), config.NewDB().URL)
-	if err != nil {
-		log.Fatalf("Unable to connect to database: %v", err)
+// argInt parses the command argument, def < 0 means it's required
+func argInt(def int) int {
+	if flag.NArg() < 2 {
+		if def < 0 {
+			log.Fatalf("Command %s requires version", flag.Arg(0))
+		}
+		return def
 	}
-	defer conn.Close(context.Background())
 
-	dbFile, err := os.Open(filename)
-	if err != nil {
-		log.Fatalf("Unable to open %v file %v", filename, err)
+	n, err := strconv.Atoi(flag.Arg(1))
+	if err != nil || n < 0 {
+		log.Fatalf("Invalid argument %q: must be non-negative number", flag.Arg(1))
 	}
 
-	migrationSQL, err := io.ReadAll(dbFile)
+	return n
+}
+
+func printStatus(ctx context.Context, m *migrate.Migrator) error {
+	s, err := m.Status(ctx)
 	if err != nil {
-		log.Fatalf("Unable to read %v file: %v", filename, err)
+		return err
 	}
 
-	_, err = conn.Exec(context.Background(), string(migrationSQL))
-	if err != nil {
-		log.Fatalf("Tables dropping failed: %v", err)
+	fmt.Printf("Version: %d\nLatest:  %d\n", s.Version, s.Latest)
+	for _, mig := range s.Pending {
+		fmt.Printf("Pending: %04d_%s\n", mig.Version, mig.Name)
 	}
 
-	fmt.Println("Tables successfully dropped.")
+	return nil
 }
@@ -14,6 +14,8 @@ import (
 	"gitlab.com/fluxx1on_group/event_message_service/pkg/logger"
 )
 
+const _forceTimeout = 5 * time.Second
+
 func main() {
 	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -47,17 +49,19 @@ func main() {
 	log.Info("Server shutting down. All connection will be terminated")
 
 	finished := make(chan struct{}, 1)
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
 	go func() {
-		server.Stop()
+		server.Stop(shutdownCtx)
 		finished <- struct{}{}
 	}()
 
+	// Stop() has its own deadline, so forced exit is only a guard
+	// against hanging on connection closing
 	select {
-	case <-shutdownCtx.Done():
-		log.Error("Server shutdown", signalCtx.Err(), shutdownCtx.Err())
+	case <-time.After(cfg.ShutdownTimeout + _forceTimeout):
+		log.Error("Server shutdown", slog.String("ErrorMsg", shutdownCtx.Err().Error()))
 	case <-finished:
 		log.Info("Successfully finished")
 	}
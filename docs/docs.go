@@ -19,6 +19,111 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/audit": {
+            "get": {
+                "description": "Get page of changes of clients and mailings. Next page is requested with next_cursor of the previous one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Get audit log",
+                "operationId": "getAudit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "created_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "client",
+                            "mailing",
+                            "import"
+                        ],
+                        "type": "string",
+                        "description": "Changed entity",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of changed entity",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor of change, X-Actor header of its request",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Changes made from, RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Changes made before, RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Audit records received",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuditPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to receive audit records",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/client": {
             "put": {
                 "description": "Create new client entity.",
@@ -57,7 +162,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete client from db.",
+                "description": "Delete client, deleted client isn't included into audiences.",
                 "consumes": [
                     "application/json"
                 ],
@@ -71,13 +176,19 @@ const docTemplate = `{
                 "operationId": "deleteClient",
                 "parameters": [
                     {
-                        "description": "Client object to delete",
+                        "description": "Client object to delete, version is required without If-Match",
                         "name": "client",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "$ref": "#/definitions/entity.Client"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of client, it overrides version of body",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -87,6 +198,18 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request, invalid JSON data"
                     },
+                    "404": {
+                        "description": "Client doesn't exist"
+                    },
+                    "409": {
+                        "description": "Client is changed since version, current client is returned",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Client"
+                        }
+                    },
+                    "428": {
+                        "description": "Version is required"
+                    },
                     "500": {
                         "description": "Internal server error, failed to delete client"
                     }
@@ -107,31 +230,55 @@ const docTemplate = `{
                 "operationId": "updateClient",
                 "parameters": [
                     {
-                        "description": "Client object to update",
+                        "description": "Client object to update, version is required without If-Match",
                         "name": "client",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "$ref": "#/definitions/entity.Client"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of client, it overrides version of body",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Client updated successfully"
+                        "description": "Client updated successfully",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of client"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad request, invalid JSON data"
                     },
+                    "404": {
+                        "description": "Client doesn't exist"
+                    },
+                    "409": {
+                        "description": "Client is changed since version, current client is returned",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Client"
+                        }
+                    },
+                    "428": {
+                        "description": "Version is required"
+                    },
                     "500": {
                         "description": "Internal server error, failed to update client"
                     }
                 }
             }
         },
-        "/mailing": {
-            "put": {
-                "description": "Create a new mailing.",
+        "/client/restore": {
+            "post": {
+                "description": "Restore deleted client.",
                 "consumes": [
                     "application/json"
                 ],
@@ -139,35 +286,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "mailings"
+                    "clients"
                 ],
-                "summary": "Create a mailing",
-                "operationId": "createMailing",
+                "summary": "Restore deleted client",
+                "operationId": "restoreClient",
                 "parameters": [
                     {
-                        "description": "Mailing object to create",
-                        "name": "mailing",
+                        "description": "Client object to restore",
+                        "name": "client",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Mailing"
+                            "$ref": "#/definitions/entity.Client"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Mailing created successfully"
+                    "204": {
+                        "description": "Client restored successfully"
                     },
                     "400": {
                         "description": "Bad request, invalid JSON data"
                     },
+                    "404": {
+                        "description": "Client isn't deleted"
+                    },
                     "500": {
-                        "description": "Internal server error, failed to create mailing"
+                        "description": "Internal server error, failed to restore client"
                     }
                 }
-            },
-            "post": {
-                "description": "Get Messages by existing mailing.",
+            }
+        },
+        "/client/{id}": {
+            "get": {
+                "description": "Get client that isn't deleted. ETag is its version, it's passed in If-Match of changes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -175,47 +327,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "mailings"
+                    "clients"
                 ],
-                "summary": "Post with Mailing",
-                "operationId": "getMessagesByMailing",
+                "summary": "Get client",
+                "operationId": "getClient",
                 "parameters": [
                     {
-                        "description": "Mailing object to select Messages by mailing's filters",
-                        "name": "mailing",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.Mailing"
-                        }
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Messages catched successfully",
+                        "description": "Client received",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.Message"
+                            "$ref": "#/definitions/entity.Client"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of client"
                             }
                         }
                     },
                     "400": {
-                        "description": "Bad request, invalid JSON data",
+                        "description": "Bad request, invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Client doesn't exist",
                         "schema": {
                             "$ref": "#/definitions/v1.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error, failed to catch messages",
+                        "description": "Internal server error, failed to receive client",
                         "schema": {
                             "$ref": "#/definitions/v1.errorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete mailing from db.",
+            }
+        },
+        "/exports": {
+            "post": {
+                "description": "Export clients or messages to file in background. Request has fields of query of streaming export and kind of exported rows.\nFile is available by ID of export when it's done.",
                 "consumes": [
                     "application/json"
                 ],
@@ -223,73 +384,219 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "mailings"
+                    "exports"
                 ],
-                "summary": "Delete existing mailing",
-                "operationId": "deleteMailing",
+                "summary": "Start export",
+                "operationId": "startExport",
                 "parameters": [
                     {
-                        "description": "Mailing object to delete",
-                        "name": "mailing",
+                        "description": "Export request",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Mailing"
+                            "$ref": "#/definitions/entity.ExportRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Mailing deleted successfully"
+                    "202": {
+                        "description": "Export started",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ExportJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Path of export"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Bad request, invalid JSON data"
+                        "description": "Bad request, invalid export",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal server error, failed to delete mailing"
+                        "description": "Internal server error, failed to start export",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Export queue is full",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
                     }
                 }
-            },
-            "patch": {
-                "description": "Update mailing in db.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/exports/clients": {
+            "get": {
+                "description": "Stream clients of audience filter as CSV, NDJSON or Parquet. Deleted clients aren't exported.\nRows are written while they're read from database, so response is cut if export fails midway.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.apache.parquet",
+                    "application/gzip"
                 ],
                 "tags": [
-                    "mailings"
+                    "exports"
                 ],
-                "summary": "Update existing mailing",
-                "operationId": "updateMailing",
+                "summary": "Export clients",
+                "operationId": "exportClients",
                 "parameters": [
                     {
-                        "description": "Mailing object to update",
-                        "name": "mailing",
-                        "in": "body",
-                        "required": true,
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "description": "Format of file, csv by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "gzip"
+                        ],
+                        "type": "string",
+                        "description": "Compression of file",
+                        "name": "compression",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "tag",
+                            "code"
+                        ],
+                        "type": "string",
+                        "description": "Audience filter like filter of mailing",
+                        "name": "filter_choice",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "silver",
+                            "gold",
+                            "vip"
+                        ],
+                        "type": "string",
+                        "description": "Tag of clients of tag filter",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator code of clients of code filter",
+                        "name": "mobile_operator_code",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported clients",
                         "schema": {
-                            "$ref": "#/definitions/entity.Mailing"
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid export",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/exports/messages": {
+            "get": {
+                "description": "Stream messages of mailing as CSV, NDJSON or Parquet.\nRows are written while they're read from database, so response is cut if export fails midway.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.apache.parquet",
+                    "application/gzip"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Export messages",
+                "operationId": "exportMessages",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mailing ID",
+                        "name": "mailing_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "description": "Format of file, csv by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "gzip"
+                        ],
+                        "type": "string",
+                        "description": "Compression of file",
+                        "name": "compression",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Messages created from, RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Messages created before, RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "delivered",
+                            "failed",
+                            "deferred"
+                        ],
+                        "type": "string",
+                        "description": "Message status",
+                        "name": "status",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Mailing updated successfully"
+                    "200": {
+                        "description": "Exported messages",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
                     "400": {
-                        "description": "Bad request, invalid JSON data"
-                    },
-                    "500": {
-                        "description": "Internal server error, failed to update mailing"
+                        "description": "Bad request, invalid export",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
                     }
                 }
             }
         },
-        "/mailing/stats": {
+        "/exports/{id}": {
             "get": {
-                "description": "Get MailingStats about all Mailings.",
+                "description": "Get status of export",
                 "consumes": [
                     "application/json"
                 ],
@@ -297,65 +604,1180 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "mailings"
+                    "exports"
+                ],
+                "summary": "Get export",
+                "operationId": "getExport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get MailingStats",
-                "operationId": "getStats",
                 "responses": {
                     "200": {
-                        "description": "MailingStats received",
+                        "description": "Export received",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.MailingStats"
-                            }
+                            "$ref": "#/definitions/entity.ExportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Export doesn't exist",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error, failed to receive stats",
+                        "description": "Internal server error, failed to receive export",
                         "schema": {
                             "$ref": "#/definitions/v1.errorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entity.Client": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "mobile_operator_code": {
-                    "type": "integer"
-                },
-                "phone_number": {
-                    "type": "integer"
-                },
-                "tag": {
-                    "type": "string"
-                },
-                "time_zone": {
-                    "type": "integer"
-                }
-            }
         },
-        "entity.Mailing": {
-            "type": "object",
-            "properties": {
-                "datetime_end": {
-                    "type": "string"
-                },
-                "datetime_start": {
-                    "type": "string"
-                },
-                "filter_choice": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
+        "/exports/{id}/file": {
+            "get": {
+                "description": "Download file of finished export",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.apache.parquet",
+                    "application/gzip"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Get export file",
+                "operationId": "getExportFile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Export doesn't exist",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Export isn't finished or failed",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to receive export",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports": {
+            "post": {
+                "description": "Upload CSV or NDJSON file of clients. Rows are validated and upserted by phone number in background, progress is available by ID of import.\nCSV has header with columns phone_number, mobile_operator_code, tag and time_zone.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Import clients",
+                "operationId": "startImport",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or NDJSON file of clients",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Format of file, it's taken from file extension by default",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Import started",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ImportJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Path of import"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid file or format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to start import",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Import queue is full",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{id}": {
+            "get": {
+                "description": "Get status and progress of import",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Get import",
+                "operationId": "getImport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import received",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ImportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Import doesn't exist",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to receive import",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{id}/errors": {
+            "get": {
+                "description": "Download CSV of invalid rows of import with columns line, field, value and message.\nField is empty if row isn't parsed at all.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Get import error report",
+                "operationId": "getImportErrors",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Error report",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Import doesn't exist",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to receive import",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/livez": {
+            "get": {
+                "description": "Process is alive and serves HTTP, dependencies aren't checked",
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "operationId": "livez",
+                "responses": {
+                    "200": {
+                        "description": "Alive"
+                    }
+                }
+            }
+        },
+        "/mailing": {
+            "put": {
+                "description": "Create a new mailing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mailings"
+                ],
+                "summary": "Create a mailing",
+                "operationId": "createMailing",
+                "parameters": [
+                    {
+                        "description": "Mailing object to create",
+                        "name": "mailing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Mailing"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Mailing created successfully"
+                    },
+                    "400": {
+                        "description": "Bad request, invalid JSON data"
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to create mailing"
+                    }
+                }
+            },
+            "post": {
+                "description": "Get page of Messages by existing mailing. Next page is requested with next_cursor of the previous one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mailings"
+                ],
+                "summary": "Post with Mailing",
+                "operationId": "getMessagesByMailing",
+                "parameters": [
+                    {
+                        "description": "Mailing object to select Messages by mailing's filters",
+                        "name": "mailing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Mailing"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "date_time_creation"
+                        ],
+                        "type": "string",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Messages created from, RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Messages created before, RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "delivered",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Delivery status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Messages catched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.MessagesPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid JSON data or filter",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to catch messages",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete mailing, its sending is stopped. Messages of mailing are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mailings"
+                ],
+                "summary": "Delete existing mailing",
+                "operationId": "deleteMailing",
+                "parameters": [
+                    {
+                        "description": "Mailing object to delete, version is required without If-Match",
+                        "name": "mailing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Mailing"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of mailing, it overrides version of body",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Mailing deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request, invalid JSON data"
+                    },
+                    "404": {
+                        "description": "Mailing doesn't exist"
+                    },
+                    "409": {
+                        "description": "Mailing is changed since version, current mailing is returned",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Mailing"
+                        }
+                    },
+                    "428": {
+                        "description": "Version is required"
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to delete mailing"
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update mailing in db.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mailings"
+                ],
+                "summary": "Update existing mailing",
+                "operationId": "updateMailing",
+                "parameters": [
+                    {
+                        "description": "Mailing object to update, version is required without If-Match",
+                        "name": "mailing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Mailing"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of mailing, it overrides version of body",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Mailing updated successfully",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of mailing"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid JSON data"
+                    },
+                    "404": {
+                        "description": "Mailing doesn't exist"
+                    },
+                    "409": {
+                        "description": "Mailing is changed since version, current mailing is returned",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Mailing"
+                        }
+                    },
+                    "428": {
+                        "description": "Version is required"
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to update mailing"
+                    }
+                }
+            }
+        },
+        "/mailing/": {
+            "get": {
+                "description": "Get page of mailings. Next page is requested with next_cursor of the previous one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mailings"
+                ],
+                "summary": "List mailings",
+                "operationId": "listMailings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "datetime_start",
+                            "datetime_end"
+                        ],
+                        "type": "string",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mailings started from, RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mailings started before, RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "scheduled",
+                            "active",
+                            "finished"
+                        ],
+                        "type": "string",
+                        "description": "Mailing status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Deleted mailings are listed too",
+                        "name": "with_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Mailings received",
+                        "schema": {
+                            "$ref": "#/definitions/entity.MailingsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to receive mailings",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mailing/archive": {
+            "post": {
+                "description": "Move mailings finished before time with their messages to archive.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mailings"
+                ],
+                "summary": "Archive finished mailings",
+                "operationId": "archiveMailings",
+                "parameters": [
+                    {
+                        "description": "Bound of mailings end, now by default",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v1.archiveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Mailings archived",
+                        "schema": {
+                            "$ref": "#/definitions/v1.archiveResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid JSON data",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to archive mailings",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mailing/restore": {
+            "post": {
+                "description": "Restore deleted or archived mailing with its messages. Stopped sending isn't resumed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mailings"
+                ],
+                "summary": "Restore mailing",
+                "operationId": "restoreMailing",
+                "parameters": [
+                    {
+                        "description": "Mailing object to restore",
+                        "name": "mailing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Mailing"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Mailing restored successfully"
+                    },
+                    "400": {
+                        "description": "Bad request, invalid JSON data"
+                    },
+                    "404": {
+                        "description": "Mailing is neither deleted nor archived"
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to restore mailing"
+                    }
+                }
+            }
+        },
+        "/mailing/series": {
+            "get": {
+                "description": "Get counters of sent, failed and deferred messages by time buckets\nof mailing or of all the mailings. Empty buckets are zero.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mailings"
+                ],
+                "summary": "Get delivery time series",
+                "operationId": "getSeries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mailing ID, all the mailings by default",
+                        "name": "mailing_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Messages created from, RFC3339",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Messages created before, RFC3339",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "hour",
+                            "day"
+                        ],
+                        "type": "string",
+                        "description": "Bucket size, hour by default",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of buckets, UTC by default",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Series received",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Series"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to receive series",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mailing/stats": {
+            "get": {
+                "description": "Get page of MailingStats. Next page is requested with next_cursor of the previous one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mailings"
+                ],
+                "summary": "Get MailingStats",
+                "operationId": "getStats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "datetime_start",
+                            "datetime_end"
+                        ],
+                        "type": "string",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mailings started from, RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mailings started before, RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "scheduled",
+                            "active",
+                            "finished"
+                        ],
+                        "type": "string",
+                        "description": "Mailing status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "MailingStats received",
+                        "schema": {
+                            "$ref": "#/definitions/entity.MailingStatsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to receive stats",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mailing/{id}": {
+            "get": {
+                "description": "Get mailing that isn't deleted. ETag is its version, it's passed in If-Match of changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mailings"
+                ],
+                "summary": "Get mailing",
+                "operationId": "getMailing",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mailing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Mailing received",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Mailing"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of mailing"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Mailing doesn't exist",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, failed to receive mailing",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Check database, NATS connection, JetStream stream and scheduler of delayed messages.\nEvery check reports its latency, optional checks don't affect readiness.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "operationId": "readyz",
+                "responses": {
+                    "200": {
+                        "description": "Ready",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready, some of critical checks failed",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.AuditChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                }
+            }
+        },
+        "entity.AuditDiff": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/entity.AuditChange"
+            }
+        },
+        "entity.AuditPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AuditRecord"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AuditRecord": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "$ref": "#/definitions/entity.AuditDiff"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Client": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "mobile_operator_code": {
+                    "type": "integer"
+                },
+                "phone_number": {
+                    "type": "integer"
+                },
+                "tag": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ExportJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "file": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "request": {
+                    "$ref": "#/definitions/entity.ExportRequest"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ExportRequest": {
+            "type": "object",
+            "properties": {
+                "compression": {
+                    "type": "string"
+                },
+                "filter_choice": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "mailing_id": {
+                    "type": "integer"
+                },
+                "mobile_operator_code": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ImportJob": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.Mailing": {
+            "type": "object",
+            "properties": {
+                "datetime_end": {
+                    "type": "string"
+                },
+                "datetime_start": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "filter_choice": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
                 },
                 "interval_end": {
                     "type": "string"
@@ -369,8 +1791,14 @@ const docTemplate = `{
                 "mobile_operator_code": {
                     "type": "string"
                 },
+                "priority": {
+                    "type": "string"
+                },
                 "tag": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -396,6 +1824,34 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.MailingStatsPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.MailingStats"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.MailingsPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Mailing"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.Message": {
             "type": "object",
             "properties": {
@@ -405,6 +1861,9 @@ const docTemplate = `{
                 "date_time_creation": {
                     "type": "string"
                 },
+                "deferred": {
+                    "type": "boolean"
+                },
                 "delivery_status": {
                     "type": "boolean"
                 },
@@ -419,6 +1878,105 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.MessagesPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Message"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Series": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string"
+                },
+                "mailing_id": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.SeriesPoint"
+                    }
+                },
+                "tz": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.SeriesPoint": {
+            "type": "object",
+            "properties": {
+                "deferred": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "sent": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.Result"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.Result": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "optional": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.archiveRequest": {
+            "type": "object",
+            "properties": {
+                "before": {
+                    "description": "Before is bound of mailings end, it's now by default",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.archiveResponse": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.errorResponse": {
             "type": "object",
             "properties": {
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/url"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -19,13 +22,114 @@ type NatsConfig struct {
 	URL      string
 	Host     string   `yaml:"host"`
 	Subjects []string `yaml:"subjects"`
+
+	// Stream persists subjects, Queue is shared by all the worker instances
+	Stream  string        `yaml:"stream" env-default:"MAILING"`
+	Queue   string        `yaml:"queue" env-default:"workers"`
+	AckWait time.Duration `yaml:"ackWait" env-default:"1m"`
+
+	// Workers bounds concurrency of every subject, Limits overrides it by subject
+	Workers WorkerLimit            `yaml:"workers"`
+	Limits  map[string]WorkerLimit `yaml:"limits"`
+
+	// Manager is TaskManager or PriorityManager
+	Manager    string     `yaml:"manager" env-default:"TaskManager"`
+	Starvation Starvation `yaml:"starvation"`
+
+	// RPCPrefix prefixes request-reply API subjects
+	RPCPrefix string `yaml:"rpcPrefix" env-default:"rpc.v1"`
+
+	// EventsPrefix prefixes domain events subjects, events are kept
+	// by EventsStream for EventsMaxAge
+	EventsPrefix string        `yaml:"eventsPrefix" env-default:"events.v1"`
+	EventsStream string        `yaml:"eventsStream" env-default:"EVENTS"`
+	EventsMaxAge time.Duration `yaml:"eventsMaxAge" env-default:"168h"`
+
+	Embedded EmbeddedNats `yaml:"embedded"`
+
+	// Auth and TLS of connection to external server
+	Auth NatsAuth `yaml:"auth"`
+	TLS  NatsTLS  `yaml:"tls"`
+
+	// ConnectAttempts bounds connection retries at startup
+	ConnectAttempts int           `yaml:"connectAttempts" env-default:"10"`
+	ConnectWait     time.Duration `yaml:"connectWait" env-default:"2s"`
+
+	// Lost connection is reconnected forever, delay is doubled by every
+	// failed attempt from ReconnectWait up to MaxReconnectWait
+	ReconnectWait    time.Duration `yaml:"reconnectWait" env-default:"1s"`
+	MaxReconnectWait time.Duration `yaml:"maxReconnectWait" env-default:"30s"`
+}
+
+// NatsAuth - only one of methods is allowed: user and password, token,
+// NKey seed file or credentials file. Secrets could be set by environment.
+type NatsAuth struct {
+	User            string `yaml:"user"`
+	Password        string `yaml:"password" env:"NATS_PASSWORD"`
+	Token           string `yaml:"token" env:"NATS_TOKEN"`
+	NKeySeedFile    string `yaml:"nkeySeedFile"`
+	CredentialsFile string `yaml:"credentialsFile"`
+}
+
+// NatsTLS - CAFile is used if server certificate isn't trusted by system,
+// CertFile and KeyFile are client certificate for mutual TLS
+type NatsTLS struct {
+	Enabled  bool   `yaml:"enabled"`
+	CAFile   string `yaml:"caFile"`
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+}
+
+// EmbeddedNats - NATS server with JetStream started inside the service.
+// Port 0 disables network listener, so only the service is connected.
+type EmbeddedNats struct {
+	Enabled    bool   `yaml:"enabled"`
+	ServerName string `yaml:"serverName" env-default:"event-message-service"`
+	StoreDir   string `yaml:"storeDir" env-default:"./data/nats"`
+	Host       string `yaml:"host" env-default:"127.0.0.1"`
+	Port       int    `yaml:"port"`
+}
+
+// Starvation - bulk message is served after Ratio transactional ones
+// in a row or if it waits longer than MaxWait
+type Starvation struct {
+	Ratio   int           `yaml:"ratio" env-default:"4"`
+	MaxWait time.Duration `yaml:"maxWait" env-default:"30s"`
+}
+
+// WorkerLimit - Workers handle messages concurrently
+// and Queue messages more are fetched in advance
+type WorkerLimit struct {
+	Workers int `yaml:"workers" env-default:"4"`
+	Queue   int `yaml:"queue" env-default:"16"`
+}
+
+// Concurrency is number of workers of all the subjects, so it's the most
+// of messages handled at once
+func (nats *NatsConfig) Concurrency() int {
+	var n int
+	for _, subj := range nats.Subjects {
+		l, ok := nats.Limits[subj]
+		if !ok || l.Workers <= 0 {
+			l = nats.Workers
+		}
+		n += l.Workers
+	}
+
+	return n
 }
 
+// SetURI builds URL of server. Credentials aren't put in URL,
+// they're passed as connection options.
 func (nats *NatsConfig) SetURI() {
+	scheme := "nats"
+	if nats.TLS.Enabled {
+		scheme = "tls"
+	}
+
 	natsURL := &url.URL{
-		Scheme: "nats",
+		Scheme: scheme,
 		Host:   nats.Host,
-		// User:   url.UserPassword(nats.User, nats.Password),
 	}
 
 	nats.URL = natsURL.String()
@@ -39,16 +143,103 @@ type Config struct {
 	Nats             *NatsConfig `yaml:"nats"`
 	PostgreSQL       *PostgresConfig
 	Docker           *DockerConfig
+
+	// ShutdownTimeout is deadline to finish in-flight work on shutdown
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env-default:"10s"`
+
+	// ChunkSize is number of clients in one chunk of mailing audience
+	ChunkSize int `yaml:"chunkSize" env-default:"1000"`
+
+	// Storage is StoragePostgres, StorageSQLite or StorageMemory
+	Storage string `yaml:"storage" env:"STORAGE" env-default:"postgres"`
+	SQLite  SQLite `yaml:"sqlite"`
+
+	// SchemaCheck refuses to start if database schema is outdated
+	SchemaCheck bool `yaml:"schemaCheck" env:"SCHEMA_CHECK"`
+
+	Retention Retention `yaml:"retention"`
+	Import    Import    `yaml:"import"`
+	Export    Export    `yaml:"export"`
+	Health    Health    `yaml:"health"`
+}
+
+// Storages of repositories. SQLite storage is database file of embedded
+// deployment. Memory storage isn't persisted, it's used for development
+// and tests without PostgreSQL.
+const (
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	StorageMemory   = "memory"
+)
+
+// SQLite is database file of SQLite storage, it's created if it doesn't exist
+type SQLite struct {
+	Path string `yaml:"path" env:"SQLITE_PATH" env-default:"./data/service.db"`
+}
+
+// Retention of monthly partitions of messages. Months is number of kept
+// months including the current one, zero keeps messages forever.
+// Messages are exported to ExportDir as CSV before dropping if it's set.
+type Retention struct {
+	Months    int           `yaml:"months"`
+	Ahead     int           `yaml:"ahead" env-default:"2"`
+	Interval  time.Duration `yaml:"interval" env-default:"1h"`
+	ExportDir string        `yaml:"exportDir"`
+}
+
+// Import of clients. Uploads are kept in Dir until their jobs are finished.
+// Valid rows are upserted by batches of BatchSize, only MaxErrors invalid
+// rows of job are reported. Queue bounds number of waiting jobs.
+type Import struct {
+	Dir       string `yaml:"dir" env-default:"./data/imports"`
+	BatchSize int    `yaml:"batchSize" env-default:"1000"`
+	MaxErrors int    `yaml:"maxErrors" env-default:"10000"`
+	Queue     int    `yaml:"queue" env-default:"16"`
+}
+
+// Export of clients and messages. Files of async jobs are written to Dir,
+// Queue bounds number of waiting jobs.
+type Export struct {
+	Dir   string `yaml:"dir" env-default:"./data/exports"`
+	Queue int    `yaml:"queue" env-default:"16"`
+}
+
+// Health of readiness probe. Timeout bounds all the dependency checks.
+type Health struct {
+	Timeout time.Duration `yaml:"timeout" env-default:"2s"`
 }
 
 func (cfg *Config) GetAlt() {
 	cfg.Addr = cfg.Docker.Hosts.ListenerHost
 
 	cfg.Nats.Host = cfg.Docker.Hosts.NatsHost
-	cfg.PostgreSQL.Host = cfg.Docker.Hosts.PostgresqlHost
-
 	cfg.Nats.SetURI()
-	cfg.PostgreSQL.SetURI()
+
+	if cfg.PostgreSQL != nil {
+		cfg.PostgreSQL.Host = cfg.Docker.Hosts.PostgresqlHost
+		cfg.PostgreSQL.SetURI()
+	}
+}
+
+// Validate fails if settings can't be used, e.g. durations of tickers
+// aren't positive
+func (cfg *Config) Validate() error {
+	if cfg.Nats.AckWait <= 0 {
+		return errors.New("nats.ackWait must be positive")
+	}
+	if cfg.Nats.Workers.Workers <= 0 || cfg.Nats.Workers.Queue < 0 {
+		return errors.New("nats.workers must be positive and nats.workers.queue must not be negative")
+	}
+	for subj, l := range cfg.Nats.Limits {
+		if l.Workers < 0 || l.Queue < 0 {
+			return fmt.Errorf("nats.limits of %s must not be negative", subj)
+		}
+	}
+	if cfg.Retention.Interval <= 0 {
+		return errors.New("retention.interval must be positive")
+	}
+
+	return nil
 }
 
 func Setup() *Config {
@@ -67,9 +258,16 @@ func Setup() *Config {
 		log.Fatalf("can't read config: %s", err)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid config: %s", err)
+	}
+
 	cfg.Nats.SetURI()
 
-	cfg.PostgreSQL = NewDB()
+	// Only PostgreSQL storage needs database server
+	if cfg.Storage == StoragePostgres {
+		cfg.PostgreSQL = NewDB()
+	}
 
 	if os.Getenv("DOCKER_PATH") != "" {
 		cfg.Docker = GetDocker()
@@ -0,0 +1,151 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Audited entities
+const (
+	AuditClient  = "client"
+	AuditMailing = "mailing"
+	// AuditImport is import job, its records are batches of upserted clients
+	AuditImport = "import"
+)
+
+// Audited actions
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditArchive = "archive"
+	AuditUpsert  = "upsert"
+)
+
+// AnonymousActor is actor of requests without identity
+const AnonymousActor = "anonymous"
+
+// AuditChange is JSON value of field before and after change,
+// it's null if field didn't exist
+type AuditChange struct {
+	Before json.RawMessage `json:"before" swaggertype:"object"`
+	After  json.RawMessage `json:"after" swaggertype:"object"`
+}
+
+// AuditDiff is changes of fields by JSON names
+type AuditDiff map[string]AuditChange
+
+// AuditRecord is change of entity made by actor in request
+type AuditRecord struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Entity    string    `json:"entity"`
+	EntityID  int64     `json:"entity_id"`
+	Diff      AuditDiff `json:"diff"`
+	RequestID string    `json:"request_id"`
+}
+
+// Diff returns fields of JSON objects which differ. Nil before is
+// creation, nil after is deletion: all the fields are changed.
+func Diff(before, after any) (AuditDiff, error) {
+	b, err := fields(before)
+	if err != nil {
+		return nil, fmt.Errorf("Diff(): %w", err)
+	}
+	a, err := fields(after)
+	if err != nil {
+		return nil, fmt.Errorf("Diff(): %w", err)
+	}
+
+	keys := make([]string, 0, len(b)+len(a))
+	for k := range b {
+		keys = append(keys, k)
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	diff := make(AuditDiff)
+	for _, k := range keys {
+		if !bytes.Equal(b[k], a[k]) {
+			diff[k] = AuditChange{Before: null(b[k]), After: null(a[k])}
+		}
+	}
+
+	return diff, nil
+}
+
+// fields returns JSON values of fields of object, nil object hasn't any
+func fields(v any) (map[string]json.RawMessage, error) {
+	res := make(map[string]json.RawMessage)
+	if v == nil {
+		return res, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func null(v json.RawMessage) json.RawMessage {
+	if v == nil {
+		return json.RawMessage("null")
+	}
+
+	return v
+}
+
+// AuditFilter selects audit records. From and To bound CreatedAt.
+type AuditFilter struct {
+	Page
+	Entity   string    `json:"entity" form:"entity"`
+	EntityID int64     `json:"entity_id" form:"entity_id"`
+	Actor    string    `json:"actor" form:"actor"`
+	From     time.Time `json:"from" form:"from"`
+	To       time.Time `json:"to" form:"to"`
+}
+
+// SortCreatedAt is sort of audit records
+const SortCreatedAt = "created_at"
+
+func (f *AuditFilter) Validate() error {
+	if err := f.normalize(SortID, SortCreatedAt); err != nil {
+		return err
+	}
+
+	if f.Entity != "" && !oneOf(f.Entity, AuditClient, AuditMailing, AuditImport) {
+		return fmt.Errorf("%w: entity %q", ErrInvalidFilter, f.Entity)
+	}
+
+	return nil
+}
+
+// SortTime returns value of time sort, it's zero for sort by ID
+func (r *AuditRecord) SortTime(sort string) time.Time {
+	if sort == SortCreatedAt {
+		return r.CreatedAt
+	}
+
+	return time.Time{}
+}
+
+// AuditPage is page of audit records, NextCursor is empty on the last page
+type AuditPage struct {
+	Items      []*AuditRecord `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
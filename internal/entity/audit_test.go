@@ -0,0 +1,33 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+func TestDiff(t *testing.T) {
+	// test 1: only changed fields are in diff
+	{
+		diff, err := entity.Diff(
+			&entity.Client{ID: 1, Tag: "gold", TimeZone: 12},
+			&entity.Client{ID: 1, Tag: "vip", TimeZone: 12},
+		)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(diff), 1)
+		assert.Equal(t, string(diff["tag"].Before), `"gold"`)
+		assert.Equal(t, string(diff["tag"].After), `"vip"`)
+	}
+
+	// test 2: all the fields are created, omitted field is null
+	{
+		diff, err := entity.Diff(nil, &entity.Mailing{ID: 1})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, string(diff["id"].Before), "null")
+		assert.Equal(t, string(diff["id"].After), "1")
+
+		_, ok := diff["deleted_at"]
+		assert.Equal(t, ok, false)
+	}
+}
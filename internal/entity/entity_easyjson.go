@@ -7,6 +7,7 @@ import (
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
 )
 
 // suppress unused package warning
@@ -17,7 +18,331 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(in *jlexer.Lexer, out *SendResponse) {
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(in *jlexer.Lexer, out *SeriesPoint) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "time":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Time).UnmarshalJSON(data))
+			}
+		case "sent":
+			out.Sent = int(in.Int())
+		case "failed":
+			out.Failed = int(in.Int())
+		case "deferred":
+			out.Deferred = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(out *jwriter.Writer, in SeriesPoint) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"time\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.Time).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"sent\":"
+		out.RawString(prefix)
+		out.Int(int(in.Sent))
+	}
+	{
+		const prefix string = ",\"failed\":"
+		out.RawString(prefix)
+		out.Int(int(in.Failed))
+	}
+	{
+		const prefix string = ",\"deferred\":"
+		out.RawString(prefix)
+		out.Int(int(in.Deferred))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SeriesPoint) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SeriesPoint) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SeriesPoint) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SeriesPoint) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(in *jlexer.Lexer, out *SeriesFilter) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "mailing_id":
+			out.MailingID = int64(in.Int64())
+		case "from":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.From).UnmarshalJSON(data))
+			}
+		case "to":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.To).UnmarshalJSON(data))
+			}
+		case "bucket":
+			out.Bucket = string(in.String())
+		case "tz":
+			out.TimeZone = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(out *jwriter.Writer, in SeriesFilter) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"mailing_id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.MailingID))
+	}
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix)
+		out.Raw((in.From).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Raw((in.To).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"bucket\":"
+		out.RawString(prefix)
+		out.String(string(in.Bucket))
+	}
+	{
+		const prefix string = ",\"tz\":"
+		out.RawString(prefix)
+		out.String(string(in.TimeZone))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SeriesFilter) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SeriesFilter) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SeriesFilter) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SeriesFilter) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(in *jlexer.Lexer, out *Series) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "mailing_id":
+			out.MailingID = int64(in.Int64())
+		case "bucket":
+			out.Bucket = string(in.String())
+		case "tz":
+			out.TimeZone = string(in.String())
+		case "points":
+			if in.IsNull() {
+				in.Skip()
+				out.Points = nil
+			} else {
+				in.Delim('[')
+				if out.Points == nil {
+					if !in.IsDelim(']') {
+						out.Points = make([]*SeriesPoint, 0, 8)
+					} else {
+						out.Points = []*SeriesPoint{}
+					}
+				} else {
+					out.Points = (out.Points)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 *SeriesPoint
+					if in.IsNull() {
+						in.Skip()
+						v1 = nil
+					} else {
+						if v1 == nil {
+							v1 = new(SeriesPoint)
+						}
+						(*v1).UnmarshalEasyJSON(in)
+					}
+					out.Points = append(out.Points, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(out *jwriter.Writer, in Series) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.MailingID != 0 {
+		const prefix string = ",\"mailing_id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.MailingID))
+	}
+	{
+		const prefix string = ",\"bucket\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Bucket))
+	}
+	{
+		const prefix string = ",\"tz\":"
+		out.RawString(prefix)
+		out.String(string(in.TimeZone))
+	}
+	{
+		const prefix string = ",\"points\":"
+		out.RawString(prefix)
+		if in.Points == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Points {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				if v3 == nil {
+					out.RawString("null")
+				} else {
+					(*v3).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Series) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Series) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Series) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Series) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(in *jlexer.Lexer, out *SendResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -50,47 +375,1939 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		in.Consumed()
 	}
 }
-func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(out *jwriter.Writer, in SendResponse) {
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(out *jwriter.Writer, in SendResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Code))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SendResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SendResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SendResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SendResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(in *jlexer.Lexer, out *SendRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int64(in.Int64())
+		case "phone":
+			out.Phone = int64(in.Int64())
+		case "text":
+			out.Text = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(out *jwriter.Writer, in SendRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.ID))
+	}
+	{
+		const prefix string = ",\"phone\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Phone))
+	}
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix)
+		out.String(string(in.Text))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SendRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SendRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SendRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SendRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(in *jlexer.Lexer, out *Partition) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "month":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Month).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(out *jwriter.Writer, in Partition) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"month\":"
+		out.RawString(prefix)
+		out.Raw((in.Month).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Partition) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Partition) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Partition) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Partition) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(in *jlexer.Lexer, out *Page) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "limit":
+			out.Limit = int(in.Int())
+		case "cursor":
+			out.Cursor = string(in.String())
+		case "sort":
+			out.Sort = string(in.String())
+		case "order":
+			out.Order = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(out *jwriter.Writer, in Page) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"cursor\":"
+		out.RawString(prefix)
+		out.String(string(in.Cursor))
+	}
+	{
+		const prefix string = ",\"sort\":"
+		out.RawString(prefix)
+		out.String(string(in.Sort))
+	}
+	{
+		const prefix string = ",\"order\":"
+		out.RawString(prefix)
+		out.String(string(in.Order))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Page) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Page) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Page) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Page) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity7(in *jlexer.Lexer, out *MessagesPage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "items":
+			if in.IsNull() {
+				in.Skip()
+				out.Items = nil
+			} else {
+				in.Delim('[')
+				if out.Items == nil {
+					if !in.IsDelim(']') {
+						out.Items = make(Messages, 0, 8)
+					} else {
+						out.Items = Messages{}
+					}
+				} else {
+					out.Items = (out.Items)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 *Message
+					if in.IsNull() {
+						in.Skip()
+						v4 = nil
+					} else {
+						if v4 == nil {
+							v4 = new(Message)
+						}
+						(*v4).UnmarshalEasyJSON(in)
+					}
+					out.Items = append(out.Items, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "next_cursor":
+			out.NextCursor = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity7(out *jwriter.Writer, in MessagesPage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"items\":"
+		out.RawString(prefix[1:])
+		if in.Items == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Items {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				if v6 == nil {
+					out.RawString("null")
+				} else {
+					(*v6).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.NextCursor != "" {
+		const prefix string = ",\"next_cursor\":"
+		out.RawString(prefix)
+		out.String(string(in.NextCursor))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MessagesPage) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MessagesPage) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MessagesPage) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MessagesPage) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity7(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity8(in *jlexer.Lexer, out *MessageFilter) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "mailing_id":
+			out.MailingID = int64(in.Int64())
+		case "from":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.From).UnmarshalJSON(data))
+			}
+		case "to":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.To).UnmarshalJSON(data))
+			}
+		case "status":
+			out.Status = string(in.String())
+		case "limit":
+			out.Limit = int(in.Int())
+		case "cursor":
+			out.Cursor = string(in.String())
+		case "sort":
+			out.Sort = string(in.String())
+		case "order":
+			out.Order = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity8(out *jwriter.Writer, in MessageFilter) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"mailing_id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.MailingID))
+	}
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix)
+		out.Raw((in.From).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Raw((in.To).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"cursor\":"
+		out.RawString(prefix)
+		out.String(string(in.Cursor))
+	}
+	{
+		const prefix string = ",\"sort\":"
+		out.RawString(prefix)
+		out.String(string(in.Sort))
+	}
+	{
+		const prefix string = ",\"order\":"
+		out.RawString(prefix)
+		out.String(string(in.Order))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MessageFilter) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MessageFilter) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MessageFilter) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MessageFilter) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity8(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity9(in *jlexer.Lexer, out *Message) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int64(in.Int64())
+		case "date_time_creation":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.DateTimeCreation).UnmarshalJSON(data))
+			}
+		case "try":
+			out.Try = int(in.Int())
+		case "delivery_status":
+			out.DeliveryStatus = bool(in.Bool())
+		case "deferred":
+			out.Deferred = bool(in.Bool())
+		case "mailing_id":
+			out.MailingID = int64(in.Int64())
+		case "client_id":
+			out.ClientID = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity9(out *jwriter.Writer, in Message) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.ID))
+	}
+	{
+		const prefix string = ",\"date_time_creation\":"
+		out.RawString(prefix)
+		out.Raw((in.DateTimeCreation).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"try\":"
+		out.RawString(prefix)
+		out.Int(int(in.Try))
+	}
+	{
+		const prefix string = ",\"delivery_status\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.DeliveryStatus))
+	}
+	{
+		const prefix string = ",\"deferred\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Deferred))
+	}
+	{
+		const prefix string = ",\"mailing_id\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.MailingID))
+	}
+	{
+		const prefix string = ",\"client_id\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ClientID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Message) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Message) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Message) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Message) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity9(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity10(in *jlexer.Lexer, out *MailingsPage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "items":
+			if in.IsNull() {
+				in.Skip()
+				out.Items = nil
+			} else {
+				in.Delim('[')
+				if out.Items == nil {
+					if !in.IsDelim(']') {
+						out.Items = make(Mailings, 0, 8)
+					} else {
+						out.Items = Mailings{}
+					}
+				} else {
+					out.Items = (out.Items)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 *Mailing
+					if in.IsNull() {
+						in.Skip()
+						v7 = nil
+					} else {
+						if v7 == nil {
+							v7 = new(Mailing)
+						}
+						(*v7).UnmarshalEasyJSON(in)
+					}
+					out.Items = append(out.Items, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "next_cursor":
+			out.NextCursor = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity10(out *jwriter.Writer, in MailingsPage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"items\":"
+		out.RawString(prefix[1:])
+		if in.Items == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Items {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				if v9 == nil {
+					out.RawString("null")
+				} else {
+					(*v9).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.NextCursor != "" {
+		const prefix string = ",\"next_cursor\":"
+		out.RawString(prefix)
+		out.String(string(in.NextCursor))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MailingsPage) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MailingsPage) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MailingsPage) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MailingsPage) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity10(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity11(in *jlexer.Lexer, out *MailingWithClients) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "mailing":
+			if in.IsNull() {
+				in.Skip()
+				out.Mailing = nil
+			} else {
+				if out.Mailing == nil {
+					out.Mailing = new(Mailing)
+				}
+				(*out.Mailing).UnmarshalEasyJSON(in)
+			}
+		case "clients":
+			if in.IsNull() {
+				in.Skip()
+				out.Clients = nil
+			} else {
+				in.Delim('[')
+				if out.Clients == nil {
+					if !in.IsDelim(']') {
+						out.Clients = make(Clients, 0, 8)
+					} else {
+						out.Clients = Clients{}
+					}
+				} else {
+					out.Clients = (out.Clients)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 *Client
+					if in.IsNull() {
+						in.Skip()
+						v10 = nil
+					} else {
+						if v10 == nil {
+							v10 = new(Client)
+						}
+						(*v10).UnmarshalEasyJSON(in)
+					}
+					out.Clients = append(out.Clients, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "try":
+			out.Try = int(in.Int())
+		case "chunk":
+			out.Chunk = int(in.Int())
+		case "not_before":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.NotBefore).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity11(out *jwriter.Writer, in MailingWithClients) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"mailing\":"
+		out.RawString(prefix[1:])
+		if in.Mailing == nil {
+			out.RawString("null")
+		} else {
+			(*in.Mailing).MarshalEasyJSON(out)
+		}
+	}
+	{
+		const prefix string = ",\"clients\":"
+		out.RawString(prefix)
+		if in.Clients == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.Clients {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				if v12 == nil {
+					out.RawString("null")
+				} else {
+					(*v12).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"try\":"
+		out.RawString(prefix)
+		out.Int(int(in.Try))
+	}
+	if in.Chunk != 0 {
+		const prefix string = ",\"chunk\":"
+		out.RawString(prefix)
+		out.Int(int(in.Chunk))
+	}
+	{
+		const prefix string = ",\"not_before\":"
+		out.RawString(prefix)
+		out.Raw((in.NotBefore).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MailingWithClients) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MailingWithClients) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MailingWithClients) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MailingWithClients) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity11(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity12(in *jlexer.Lexer, out *MailingStatsPage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "items":
+			if in.IsNull() {
+				in.Skip()
+				out.Items = nil
+			} else {
+				in.Delim('[')
+				if out.Items == nil {
+					if !in.IsDelim(']') {
+						out.Items = make([]*MailingStats, 0, 8)
+					} else {
+						out.Items = []*MailingStats{}
+					}
+				} else {
+					out.Items = (out.Items)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 *MailingStats
+					if in.IsNull() {
+						in.Skip()
+						v13 = nil
+					} else {
+						if v13 == nil {
+							v13 = new(MailingStats)
+						}
+						(*v13).UnmarshalEasyJSON(in)
+					}
+					out.Items = append(out.Items, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "next_cursor":
+			out.NextCursor = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity12(out *jwriter.Writer, in MailingStatsPage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"items\":"
+		out.RawString(prefix[1:])
+		if in.Items == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.Items {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				if v15 == nil {
+					out.RawString("null")
+				} else {
+					(*v15).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.NextCursor != "" {
+		const prefix string = ",\"next_cursor\":"
+		out.RawString(prefix)
+		out.String(string(in.NextCursor))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MailingStatsPage) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MailingStatsPage) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MailingStatsPage) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MailingStatsPage) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity12(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity13(in *jlexer.Lexer, out *MailingStats) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "mailing_id":
+			out.MailingID = int64(in.Int64())
+		case "datetime_start":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.DateTimeStart).UnmarshalJSON(data))
+			}
+		case "datetime_end":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.DateTimeEnd).UnmarshalJSON(data))
+			}
+		case "succesed":
+			out.Succesed = int(in.Int())
+		case "failed":
+			out.Failed = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity13(out *jwriter.Writer, in MailingStats) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"mailing_id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.MailingID))
+	}
+	{
+		const prefix string = ",\"datetime_start\":"
+		out.RawString(prefix)
+		out.Raw((in.DateTimeStart).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"datetime_end\":"
+		out.RawString(prefix)
+		out.Raw((in.DateTimeEnd).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"succesed\":"
+		out.RawString(prefix)
+		out.Int(int(in.Succesed))
+	}
+	{
+		const prefix string = ",\"failed\":"
+		out.RawString(prefix)
+		out.Int(int(in.Failed))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MailingStats) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MailingStats) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MailingStats) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MailingStats) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity13(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity14(in *jlexer.Lexer, out *MailingFilter) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "from":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.From).UnmarshalJSON(data))
+			}
+		case "to":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.To).UnmarshalJSON(data))
+			}
+		case "status":
+			out.Status = string(in.String())
+		case "with_deleted":
+			out.WithDeleted = bool(in.Bool())
+		case "limit":
+			out.Limit = int(in.Int())
+		case "cursor":
+			out.Cursor = string(in.String())
+		case "sort":
+			out.Sort = string(in.String())
+		case "order":
+			out.Order = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity14(out *jwriter.Writer, in MailingFilter) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.From).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Raw((in.To).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"with_deleted\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.WithDeleted))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"cursor\":"
+		out.RawString(prefix)
+		out.String(string(in.Cursor))
+	}
+	{
+		const prefix string = ",\"sort\":"
+		out.RawString(prefix)
+		out.String(string(in.Sort))
+	}
+	{
+		const prefix string = ",\"order\":"
+		out.RawString(prefix)
+		out.String(string(in.Order))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MailingFilter) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MailingFilter) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MailingFilter) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MailingFilter) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity14(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity15(in *jlexer.Lexer, out *MailingChunk) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "mailing_id":
+			out.MailingID = int64(in.Int64())
+		case "seq":
+			out.Seq = int(in.Int())
+		case "after_id":
+			out.AfterID = int64(in.Int64())
+		case "last_id":
+			out.LastID = int64(in.Int64())
+		case "status":
+			out.Status = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity15(out *jwriter.Writer, in MailingChunk) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"mailing_id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.MailingID))
+	}
+	{
+		const prefix string = ",\"seq\":"
+		out.RawString(prefix)
+		out.Int(int(in.Seq))
+	}
+	{
+		const prefix string = ",\"after_id\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.AfterID))
+	}
+	{
+		const prefix string = ",\"last_id\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastID))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MailingChunk) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity15(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MailingChunk) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity15(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MailingChunk) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity15(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MailingChunk) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity15(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity16(in *jlexer.Lexer, out *Mailing) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int64(in.Int64())
+		case "message_text":
+			out.MessageText = string(in.String())
+		case "mobile_operator_code":
+			out.MobileOperator = string(in.String())
+		case "tag":
+			out.Tag = string(in.String())
+		case "filter_choice":
+			out.FilterChoice = string(in.String())
+		case "datetime_start":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.DateTimeStart).UnmarshalJSON(data))
+			}
+		case "datetime_end":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.DateTimeEnd).UnmarshalJSON(data))
+			}
+		case "interval_start":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.IntervalStart).UnmarshalJSON(data))
+			}
+		case "interval_end":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.IntervalEnd).UnmarshalJSON(data))
+			}
+		case "priority":
+			out.Priority = string(in.String())
+		case "version":
+			out.Version = int64(in.Int64())
+		case "deleted_at":
+			if in.IsNull() {
+				in.Skip()
+				out.DeletedAt = nil
+			} else {
+				if out.DeletedAt == nil {
+					out.DeletedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.DeletedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity16(out *jwriter.Writer, in Mailing) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.ID))
+	}
+	{
+		const prefix string = ",\"message_text\":"
+		out.RawString(prefix)
+		out.String(string(in.MessageText))
+	}
+	{
+		const prefix string = ",\"mobile_operator_code\":"
+		out.RawString(prefix)
+		out.String(string(in.MobileOperator))
+	}
+	{
+		const prefix string = ",\"tag\":"
+		out.RawString(prefix)
+		out.String(string(in.Tag))
+	}
+	{
+		const prefix string = ",\"filter_choice\":"
+		out.RawString(prefix)
+		out.String(string(in.FilterChoice))
+	}
+	{
+		const prefix string = ",\"datetime_start\":"
+		out.RawString(prefix)
+		out.Raw((in.DateTimeStart).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"datetime_end\":"
+		out.RawString(prefix)
+		out.Raw((in.DateTimeEnd).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"interval_start\":"
+		out.RawString(prefix)
+		out.Raw((in.IntervalStart).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"interval_end\":"
+		out.RawString(prefix)
+		out.Raw((in.IntervalEnd).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"priority\":"
+		out.RawString(prefix)
+		out.String(string(in.Priority))
+	}
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Version))
+	}
+	if in.DeletedAt != nil {
+		const prefix string = ",\"deleted_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.DeletedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Mailing) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity16(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Mailing) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity16(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Mailing) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity16(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Mailing) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity16(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity17(in *jlexer.Lexer, out *ImportRowError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "line":
+			out.Line = int64(in.Int64())
+		case "field":
+			out.Field = string(in.String())
+		case "value":
+			out.Value = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity17(out *jwriter.Writer, in ImportRowError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"line\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Line))
+	}
+	{
+		const prefix string = ",\"field\":"
+		out.RawString(prefix)
+		out.String(string(in.Field))
+	}
+	{
+		const prefix string = ",\"value\":"
+		out.RawString(prefix)
+		out.String(string(in.Value))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ImportRowError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ImportRowError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ImportRowError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ImportRowError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity17(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity18(in *jlexer.Lexer, out *ImportJob) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int64(in.Int64())
+		case "format":
+			out.Format = string(in.String())
+		case "status":
+			out.Status = string(in.String())
+		case "actor":
+			out.Actor = string(in.String())
+		case "request_id":
+			out.RequestID = string(in.String())
+		case "total":
+			out.Total = int64(in.Int64())
+		case "imported":
+			out.Imported = int64(in.Int64())
+		case "failed":
+			out.Failed = int64(in.Int64())
+		case "error":
+			out.Error = string(in.String())
+		case "created_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		case "finished_at":
+			if in.IsNull() {
+				in.Skip()
+				out.FinishedAt = nil
+			} else {
+				if out.FinishedAt == nil {
+					out.FinishedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.FinishedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity18(out *jwriter.Writer, in ImportJob) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.ID))
+	}
+	{
+		const prefix string = ",\"format\":"
+		out.RawString(prefix)
+		out.String(string(in.Format))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"actor\":"
+		out.RawString(prefix)
+		out.String(string(in.Actor))
+	}
+	if in.RequestID != "" {
+		const prefix string = ",\"request_id\":"
+		out.RawString(prefix)
+		out.String(string(in.RequestID))
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Total))
+	}
+	{
+		const prefix string = ",\"imported\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Imported))
+	}
+	{
+		const prefix string = ",\"failed\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Failed))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	if in.FinishedAt != nil {
+		const prefix string = ",\"finished_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.FinishedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ImportJob) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ImportJob) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ImportJob) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ImportJob) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity18(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity19(in *jlexer.Lexer, out *FieldError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Field":
+			out.Field = string(in.String())
+		case "Message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity19(out *jwriter.Writer, in FieldError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Field\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Field))
+	}
+	{
+		const prefix string = ",\"Message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FieldError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity19(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FieldError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity19(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FieldError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity19(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FieldError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity19(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity20(in *jlexer.Lexer, out *ExportRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "kind":
+			out.Kind = string(in.String())
+		case "format":
+			out.Format = string(in.String())
+		case "compression":
+			out.Compression = string(in.String())
+		case "filter_choice":
+			out.FilterChoice = string(in.String())
+		case "tag":
+			out.Tag = string(in.String())
+		case "mobile_operator_code":
+			out.MobileOperator = string(in.String())
+		case "mailing_id":
+			out.MailingID = int64(in.Int64())
+		case "from":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.From).UnmarshalJSON(data))
+			}
+		case "to":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.To).UnmarshalJSON(data))
+			}
+		case "status":
+			out.Status = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity20(out *jwriter.Writer, in ExportRequest) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"code\":"
+		const prefix string = ",\"kind\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Code))
+		out.String(string(in.Kind))
 	}
 	{
-		const prefix string = ",\"message\":"
+		const prefix string = ",\"format\":"
 		out.RawString(prefix)
-		out.String(string(in.Message))
+		out.String(string(in.Format))
+	}
+	if in.Compression != "" {
+		const prefix string = ",\"compression\":"
+		out.RawString(prefix)
+		out.String(string(in.Compression))
+	}
+	if in.FilterChoice != "" {
+		const prefix string = ",\"filter_choice\":"
+		out.RawString(prefix)
+		out.String(string(in.FilterChoice))
+	}
+	if in.Tag != "" {
+		const prefix string = ",\"tag\":"
+		out.RawString(prefix)
+		out.String(string(in.Tag))
+	}
+	if in.MobileOperator != "" {
+		const prefix string = ",\"mobile_operator_code\":"
+		out.RawString(prefix)
+		out.String(string(in.MobileOperator))
+	}
+	if in.MailingID != 0 {
+		const prefix string = ",\"mailing_id\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.MailingID))
+	}
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix)
+		out.Raw((in.From).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Raw((in.To).MarshalJSON())
+	}
+	if in.Status != "" {
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v SendResponse) MarshalJSON() ([]byte, error) {
+func (v ExportRequest) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(&w, v)
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity20(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v SendResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(w, v)
+func (v ExportRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity20(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *SendResponse) UnmarshalJSON(data []byte) error {
+func (v *ExportRequest) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(&r, v)
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity20(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *SendResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity(l, v)
+func (v *ExportRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity20(l, v)
 }
-func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(in *jlexer.Lexer, out *SendRequest) {
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity21(in *jlexer.Lexer, out *ExportJob) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -111,10 +2328,32 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		switch key {
 		case "id":
 			out.ID = int64(in.Int64())
-		case "phone":
-			out.Phone = int64(in.Int64())
-		case "text":
-			out.Text = string(in.String())
+		case "request":
+			(out.Request).UnmarshalEasyJSON(in)
+		case "status":
+			out.Status = string(in.String())
+		case "rows":
+			out.Rows = int64(in.Int64())
+		case "file":
+			out.File = string(in.String())
+		case "error":
+			out.Error = string(in.String())
+		case "created_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		case "finished_at":
+			if in.IsNull() {
+				in.Skip()
+				out.FinishedAt = nil
+			} else {
+				if out.FinishedAt == nil {
+					out.FinishedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.FinishedAt).UnmarshalJSON(data))
+				}
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -125,7 +2364,7 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		in.Consumed()
 	}
 }
-func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(out *jwriter.Writer, in SendRequest) {
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity21(out *jwriter.Writer, in ExportJob) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -135,42 +2374,67 @@ func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		out.Int64(int64(in.ID))
 	}
 	{
-		const prefix string = ",\"phone\":"
+		const prefix string = ",\"request\":"
 		out.RawString(prefix)
-		out.Int64(int64(in.Phone))
+		(in.Request).MarshalEasyJSON(out)
 	}
 	{
-		const prefix string = ",\"text\":"
+		const prefix string = ",\"status\":"
 		out.RawString(prefix)
-		out.String(string(in.Text))
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"rows\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Rows))
+	}
+	if in.File != "" {
+		const prefix string = ",\"file\":"
+		out.RawString(prefix)
+		out.String(string(in.File))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	if in.FinishedAt != nil {
+		const prefix string = ",\"finished_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.FinishedAt).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v SendRequest) MarshalJSON() ([]byte, error) {
+func (v ExportJob) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(&w, v)
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity21(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v SendRequest) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(w, v)
+func (v ExportJob) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity21(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *SendRequest) UnmarshalJSON(data []byte) error {
+func (v *ExportJob) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(&r, v)
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity21(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *SendRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity1(l, v)
+func (v *ExportJob) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity21(l, v)
 }
-func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(in *jlexer.Lexer, out *Message) {
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity22(in *jlexer.Lexer, out *Event) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -190,19 +2454,214 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		}
 		switch key {
 		case "id":
-			out.ID = int64(in.Int64())
-		case "date_time_creation":
+			out.ID = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "version":
+			out.Version = int(in.Int())
+		case "occurred_at":
 			if data := in.Raw(); in.Ok() {
-				in.AddError((out.DateTimeCreation).UnmarshalJSON(data))
+				in.AddError((out.OccurredAt).UnmarshalJSON(data))
 			}
-		case "try":
-			out.Try = int(in.Int())
-		case "delivery_status":
-			out.DeliveryStatus = bool(in.Bool())
-		case "mailing_id":
-			out.MailingID = int64(in.Int64())
-		case "client_id":
-			out.ClientID = int64(in.Int64())
+		case "data":
+			if m, ok := out.Data.(easyjson.Unmarshaler); ok {
+				m.UnmarshalEasyJSON(in)
+			} else if m, ok := out.Data.(json.Unmarshaler); ok {
+				_ = m.UnmarshalJSON(in.Raw())
+			} else {
+				out.Data = in.Interface()
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity22(out *jwriter.Writer, in Event) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix)
+		out.Int(int(in.Version))
+	}
+	{
+		const prefix string = ",\"occurred_at\":"
+		out.RawString(prefix)
+		out.Raw((in.OccurredAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"data\":"
+		out.RawString(prefix)
+		if m, ok := in.Data.(easyjson.Marshaler); ok {
+			m.MarshalEasyJSON(out)
+		} else if m, ok := in.Data.(json.Marshaler); ok {
+			out.Raw(m.MarshalJSON())
+		} else {
+			out.Raw(json.Marshal(in.Data))
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Event) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Event) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Event) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Event) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity22(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity23(in *jlexer.Lexer, out *Cursor) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "s":
+			out.Sort = string(in.String())
+		case "o":
+			out.Order = string(in.String())
+		case "t":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Time).UnmarshalJSON(data))
+			}
+		case "i":
+			out.ID = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity23(out *jwriter.Writer, in Cursor) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"s\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Sort))
+	}
+	{
+		const prefix string = ",\"o\":"
+		out.RawString(prefix)
+		out.String(string(in.Order))
+	}
+	{
+		const prefix string = ",\"t\":"
+		out.RawString(prefix)
+		out.Raw((in.Time).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"i\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Cursor) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Cursor) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Cursor) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Cursor) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity23(l, v)
+}
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity24(in *jlexer.Lexer, out *Client) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int64(in.Int64())
+		case "phone_number":
+			out.PhoneNumber = int64(in.Int64())
+		case "mobile_operator_code":
+			out.MobileOperator = int(in.Int())
+		case "tag":
+			out.Tag = string(in.String())
+		case "time_zone":
+			out.TimeZone = int(in.Int())
+		case "version":
+			out.Version = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -213,7 +2672,7 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		in.Consumed()
 	}
 }
-func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(out *jwriter.Writer, in Message) {
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity24(out *jwriter.Writer, in Client) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -223,57 +2682,57 @@ func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		out.Int64(int64(in.ID))
 	}
 	{
-		const prefix string = ",\"date_time_creation\":"
+		const prefix string = ",\"phone_number\":"
 		out.RawString(prefix)
-		out.Raw((in.DateTimeCreation).MarshalJSON())
+		out.Int64(int64(in.PhoneNumber))
 	}
 	{
-		const prefix string = ",\"try\":"
+		const prefix string = ",\"mobile_operator_code\":"
 		out.RawString(prefix)
-		out.Int(int(in.Try))
+		out.Int(int(in.MobileOperator))
 	}
 	{
-		const prefix string = ",\"delivery_status\":"
+		const prefix string = ",\"tag\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.DeliveryStatus))
+		out.String(string(in.Tag))
 	}
 	{
-		const prefix string = ",\"mailing_id\":"
+		const prefix string = ",\"time_zone\":"
 		out.RawString(prefix)
-		out.Int64(int64(in.MailingID))
+		out.Int(int(in.TimeZone))
 	}
 	{
-		const prefix string = ",\"client_id\":"
+		const prefix string = ",\"version\":"
 		out.RawString(prefix)
-		out.Int64(int64(in.ClientID))
+		out.Int64(int64(in.Version))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Message) MarshalJSON() ([]byte, error) {
+func (v Client) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(&w, v)
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity24(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Message) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(w, v)
+func (v Client) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity24(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Message) UnmarshalJSON(data []byte) error {
+func (v *Client) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(&r, v)
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity24(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Message) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity2(l, v)
+func (v *Client) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity24(l, v)
 }
-func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(in *jlexer.Lexer, out *MailingWithClients) {
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity25(in *jlexer.Lexer, out *AuditRecord) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -292,49 +2751,38 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 			continue
 		}
 		switch key {
-		case "mailing":
-			if in.IsNull() {
-				in.Skip()
-				out.Mailing = nil
-			} else {
-				if out.Mailing == nil {
-					out.Mailing = new(Mailing)
-				}
-				(*out.Mailing).UnmarshalEasyJSON(in)
+		case "id":
+			out.ID = int64(in.Int64())
+		case "created_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
 			}
-		case "clients":
+		case "actor":
+			out.Actor = string(in.String())
+		case "action":
+			out.Action = string(in.String())
+		case "entity":
+			out.Entity = string(in.String())
+		case "entity_id":
+			out.EntityID = int64(in.Int64())
+		case "diff":
 			if in.IsNull() {
 				in.Skip()
-				out.Clients = nil
 			} else {
-				in.Delim('[')
-				if out.Clients == nil {
-					if !in.IsDelim(']') {
-						out.Clients = make(Clients, 0, 8)
-					} else {
-						out.Clients = Clients{}
-					}
-				} else {
-					out.Clients = (out.Clients)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v1 *Client
-					if in.IsNull() {
-						in.Skip()
-						v1 = nil
-					} else {
-						if v1 == nil {
-							v1 = new(Client)
-						}
-						(*v1).UnmarshalEasyJSON(in)
-					}
-					out.Clients = append(out.Clients, v1)
+				in.Delim('{')
+				out.Diff = make(AuditDiff)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v16 AuditChange
+					(v16).UnmarshalEasyJSON(in)
+					(out.Diff)[key] = v16
 					in.WantComma()
 				}
-				in.Delim(']')
+				in.Delim('}')
 			}
-		case "try":
-			out.Try = int(in.Int())
+		case "request_id":
+			out.RequestID = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -345,71 +2793,93 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		in.Consumed()
 	}
 }
-func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(out *jwriter.Writer, in MailingWithClients) {
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity25(out *jwriter.Writer, in AuditRecord) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"mailing\":"
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		if in.Mailing == nil {
-			out.RawString("null")
-		} else {
-			(*in.Mailing).MarshalEasyJSON(out)
-		}
+		out.Int64(int64(in.ID))
 	}
 	{
-		const prefix string = ",\"clients\":"
+		const prefix string = ",\"created_at\":"
 		out.RawString(prefix)
-		if in.Clients == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"actor\":"
+		out.RawString(prefix)
+		out.String(string(in.Actor))
+	}
+	{
+		const prefix string = ",\"action\":"
+		out.RawString(prefix)
+		out.String(string(in.Action))
+	}
+	{
+		const prefix string = ",\"entity\":"
+		out.RawString(prefix)
+		out.String(string(in.Entity))
+	}
+	{
+		const prefix string = ",\"entity_id\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.EntityID))
+	}
+	{
+		const prefix string = ",\"diff\":"
+		out.RawString(prefix)
+		if in.Diff == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
 		} else {
-			out.RawByte('[')
-			for v2, v3 := range in.Clients {
-				if v2 > 0 {
-					out.RawByte(',')
-				}
-				if v3 == nil {
-					out.RawString("null")
+			out.RawByte('{')
+			v17First := true
+			for v17Name, v17Value := range in.Diff {
+				if v17First {
+					v17First = false
 				} else {
-					(*v3).MarshalEasyJSON(out)
+					out.RawByte(',')
 				}
+				out.String(string(v17Name))
+				out.RawByte(':')
+				(v17Value).MarshalEasyJSON(out)
 			}
-			out.RawByte(']')
+			out.RawByte('}')
 		}
 	}
 	{
-		const prefix string = ",\"try\":"
+		const prefix string = ",\"request_id\":"
 		out.RawString(prefix)
-		out.Int(int(in.Try))
+		out.String(string(in.RequestID))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v MailingWithClients) MarshalJSON() ([]byte, error) {
+func (v AuditRecord) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(&w, v)
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity25(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v MailingWithClients) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(w, v)
+func (v AuditRecord) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity25(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *MailingWithClients) UnmarshalJSON(data []byte) error {
+func (v *AuditRecord) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(&r, v)
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity25(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *MailingWithClients) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity3(l, v)
+func (v *AuditRecord) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity25(l, v)
 }
-func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(in *jlexer.Lexer, out *MailingStats) {
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity26(in *jlexer.Lexer, out *AuditPage) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -428,20 +2898,39 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 			continue
 		}
 		switch key {
-		case "mailing_id":
-			out.MailingID = int64(in.Int64())
-		case "datetime_start":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.DateTimeStart).UnmarshalJSON(data))
-			}
-		case "datetime_end":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.DateTimeEnd).UnmarshalJSON(data))
+		case "items":
+			if in.IsNull() {
+				in.Skip()
+				out.Items = nil
+			} else {
+				in.Delim('[')
+				if out.Items == nil {
+					if !in.IsDelim(']') {
+						out.Items = make([]*AuditRecord, 0, 8)
+					} else {
+						out.Items = []*AuditRecord{}
+					}
+				} else {
+					out.Items = (out.Items)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v18 *AuditRecord
+					if in.IsNull() {
+						in.Skip()
+						v18 = nil
+					} else {
+						if v18 == nil {
+							v18 = new(AuditRecord)
+						}
+						(*v18).UnmarshalEasyJSON(in)
+					}
+					out.Items = append(out.Items, v18)
+					in.WantComma()
+				}
+				in.Delim(']')
 			}
-		case "succesed":
-			out.Succesed = int(in.Int())
-		case "failed":
-			out.Failed = int(in.Int())
+		case "next_cursor":
+			out.NextCursor = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -452,62 +2941,62 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		in.Consumed()
 	}
 }
-func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(out *jwriter.Writer, in MailingStats) {
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity26(out *jwriter.Writer, in AuditPage) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"mailing_id\":"
+		const prefix string = ",\"items\":"
 		out.RawString(prefix[1:])
-		out.Int64(int64(in.MailingID))
-	}
-	{
-		const prefix string = ",\"datetime_start\":"
-		out.RawString(prefix)
-		out.Raw((in.DateTimeStart).MarshalJSON())
-	}
-	{
-		const prefix string = ",\"datetime_end\":"
-		out.RawString(prefix)
-		out.Raw((in.DateTimeEnd).MarshalJSON())
-	}
-	{
-		const prefix string = ",\"succesed\":"
-		out.RawString(prefix)
-		out.Int(int(in.Succesed))
+		if in.Items == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v19, v20 := range in.Items {
+				if v19 > 0 {
+					out.RawByte(',')
+				}
+				if v20 == nil {
+					out.RawString("null")
+				} else {
+					(*v20).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
 	}
-	{
-		const prefix string = ",\"failed\":"
+	if in.NextCursor != "" {
+		const prefix string = ",\"next_cursor\":"
 		out.RawString(prefix)
-		out.Int(int(in.Failed))
+		out.String(string(in.NextCursor))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v MailingStats) MarshalJSON() ([]byte, error) {
+func (v AuditPage) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(&w, v)
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity26(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v MailingStats) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(w, v)
+func (v AuditPage) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity26(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *MailingStats) UnmarshalJSON(data []byte) error {
+func (v *AuditPage) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(&r, v)
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity26(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *MailingStats) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity4(l, v)
+func (v *AuditPage) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity26(l, v)
 }
-func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(in *jlexer.Lexer, out *Mailing) {
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity27(in *jlexer.Lexer, out *AuditFilter) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -526,32 +3015,28 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int64(in.Int64())
-		case "message_text":
-			out.MessageText = string(in.String())
-		case "mobile_operator_code":
-			out.MobileOperator = string(in.String())
-		case "tag":
-			out.Tag = string(in.String())
-		case "filter_choice":
-			out.FilterChoice = string(in.String())
-		case "datetime_start":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.DateTimeStart).UnmarshalJSON(data))
-			}
-		case "datetime_end":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.DateTimeEnd).UnmarshalJSON(data))
-			}
-		case "interval_start":
+		case "entity":
+			out.Entity = string(in.String())
+		case "entity_id":
+			out.EntityID = int64(in.Int64())
+		case "actor":
+			out.Actor = string(in.String())
+		case "from":
 			if data := in.Raw(); in.Ok() {
-				in.AddError((out.IntervalStart).UnmarshalJSON(data))
+				in.AddError((out.From).UnmarshalJSON(data))
 			}
-		case "interval_end":
+		case "to":
 			if data := in.Raw(); in.Ok() {
-				in.AddError((out.IntervalEnd).UnmarshalJSON(data))
+				in.AddError((out.To).UnmarshalJSON(data))
 			}
+		case "limit":
+			out.Limit = int(in.Int())
+		case "cursor":
+			out.Cursor = string(in.String())
+		case "sort":
+			out.Sort = string(in.String())
+		case "order":
+			out.Order = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -562,82 +3047,82 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		in.Consumed()
 	}
 }
-func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(out *jwriter.Writer, in Mailing) {
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity27(out *jwriter.Writer, in AuditFilter) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"id\":"
+		const prefix string = ",\"entity\":"
 		out.RawString(prefix[1:])
-		out.Int64(int64(in.ID))
+		out.String(string(in.Entity))
 	}
 	{
-		const prefix string = ",\"message_text\":"
+		const prefix string = ",\"entity_id\":"
 		out.RawString(prefix)
-		out.String(string(in.MessageText))
+		out.Int64(int64(in.EntityID))
 	}
 	{
-		const prefix string = ",\"mobile_operator_code\":"
+		const prefix string = ",\"actor\":"
 		out.RawString(prefix)
-		out.String(string(in.MobileOperator))
+		out.String(string(in.Actor))
 	}
 	{
-		const prefix string = ",\"tag\":"
+		const prefix string = ",\"from\":"
 		out.RawString(prefix)
-		out.String(string(in.Tag))
+		out.Raw((in.From).MarshalJSON())
 	}
 	{
-		const prefix string = ",\"filter_choice\":"
+		const prefix string = ",\"to\":"
 		out.RawString(prefix)
-		out.String(string(in.FilterChoice))
+		out.Raw((in.To).MarshalJSON())
 	}
 	{
-		const prefix string = ",\"datetime_start\":"
+		const prefix string = ",\"limit\":"
 		out.RawString(prefix)
-		out.Raw((in.DateTimeStart).MarshalJSON())
+		out.Int(int(in.Limit))
 	}
 	{
-		const prefix string = ",\"datetime_end\":"
+		const prefix string = ",\"cursor\":"
 		out.RawString(prefix)
-		out.Raw((in.DateTimeEnd).MarshalJSON())
+		out.String(string(in.Cursor))
 	}
 	{
-		const prefix string = ",\"interval_start\":"
+		const prefix string = ",\"sort\":"
 		out.RawString(prefix)
-		out.Raw((in.IntervalStart).MarshalJSON())
+		out.String(string(in.Sort))
 	}
 	{
-		const prefix string = ",\"interval_end\":"
+		const prefix string = ",\"order\":"
 		out.RawString(prefix)
-		out.Raw((in.IntervalEnd).MarshalJSON())
+		out.String(string(in.Order))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Mailing) MarshalJSON() ([]byte, error) {
+func (v AuditFilter) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(&w, v)
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity27(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Mailing) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(w, v)
+func (v AuditFilter) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity27(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Mailing) UnmarshalJSON(data []byte) error {
+func (v *AuditFilter) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(&r, v)
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity27(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Mailing) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity5(l, v)
+func (v *AuditFilter) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity27(l, v)
 }
-func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(in *jlexer.Lexer, out *Client) {
+func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity28(in *jlexer.Lexer, out *AuditChange) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -656,16 +3141,14 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int64(in.Int64())
-		case "phone_number":
-			out.PhoneNumber = int64(in.Int64())
-		case "mobile_operator_code":
-			out.MobileOperator = int(in.Int())
-		case "tag":
-			out.Tag = string(in.String())
-		case "time_zone":
-			out.TimeZone = int(in.Int())
+		case "before":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Before).UnmarshalJSON(data))
+			}
+		case "after":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.After).UnmarshalJSON(data))
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -676,58 +3159,43 @@ func easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEnti
 		in.Consumed()
 	}
 }
-func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(out *jwriter.Writer, in Client) {
+func easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity28(out *jwriter.Writer, in AuditChange) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"id\":"
+		const prefix string = ",\"before\":"
 		out.RawString(prefix[1:])
-		out.Int64(int64(in.ID))
-	}
-	{
-		const prefix string = ",\"phone_number\":"
-		out.RawString(prefix)
-		out.Int64(int64(in.PhoneNumber))
-	}
-	{
-		const prefix string = ",\"mobile_operator_code\":"
-		out.RawString(prefix)
-		out.Int(int(in.MobileOperator))
-	}
-	{
-		const prefix string = ",\"tag\":"
-		out.RawString(prefix)
-		out.String(string(in.Tag))
+		out.Raw((in.Before).MarshalJSON())
 	}
 	{
-		const prefix string = ",\"time_zone\":"
+		const prefix string = ",\"after\":"
 		out.RawString(prefix)
-		out.Int(int(in.TimeZone))
+		out.Raw((in.After).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Client) MarshalJSON() ([]byte, error) {
+func (v AuditChange) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(&w, v)
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity28(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Client) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(w, v)
+func (v AuditChange) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson163c17a9EncodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity28(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Client) UnmarshalJSON(data []byte) error {
+func (v *AuditChange) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(&r, v)
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity28(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Client) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity6(l, v)
+func (v *AuditChange) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson163c17a9DecodeGitlabComFluxx1onGroupEventMessageServiceInternalEntity28(l, v)
 }
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Event types. Event is published to "<prefix>.<type>" subject,
+// its schema is described in docs/events.schema.json
+const (
+	EventClientCreated  = "client.created"
+	EventClientUpdated  = "client.updated"
+	EventClientDeleted  = "client.deleted"
+	EventClientRestored = "client.restored"
+
+	EventMailingCreated   = "mailing.created"
+	EventMailingStarted   = "mailing.started"
+	EventMailingPaused    = "mailing.paused"
+	EventMailingCompleted = "mailing.completed"
+
+	EventMessageSent   = "message.sent"
+	EventMessageFailed = "message.failed"
+)
+
+// EventVersion is version of events schema. It's increased
+// on every incompatible change of Event or its Data.
+const EventVersion = 1
+
+// Event is domain event for downstream consumers.
+//
+// ID is made of type and IDs of change, e.g. "message.sent:<mailing>:<client>:<try>".
+// It doesn't change when event is emitted again, so it's used for deduplication.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Version    int         `json:"version"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
@@ -0,0 +1,150 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidExport is returned if export request is invalid
+var ErrInvalidExport = errors.New("invalid export")
+
+// ErrExportBusy is returned if export queue is full
+var ErrExportBusy = errors.New("export queue is full")
+
+// ErrExportInterrupted fails job which was running when service stopped
+var ErrExportInterrupted = errors.New("export is interrupted by restart")
+
+// ErrExportNotReady is returned if file of export job isn't written yet
+var ErrExportNotReady = errors.New("export isn't finished")
+
+// Exported entities
+const (
+	ExportClients  = "clients"
+	ExportMessages = "messages"
+)
+
+// Export formats
+const (
+	ExportCSV     = "csv"
+	ExportNDJSON  = "ndjson"
+	ExportParquet = "parquet"
+)
+
+// ExportGzip is compression of export
+const ExportGzip = "gzip"
+
+// Export statuses
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportDone    = "done"
+	ExportFailed  = "failed"
+)
+
+// ExportRequest selects exported rows. Clients are selected like audience
+// of mailing by FilterChoice, Tag and MobileOperator, deleted clients
+// aren't exported. Messages are selected by mailing like MessageFilter.
+type ExportRequest struct {
+	Kind        string `json:"kind" form:"-"`
+	Format      string `json:"format" form:"format"`
+	Compression string `json:"compression,omitempty" form:"compression"`
+
+	FilterChoice   string `json:"filter_choice,omitempty" form:"filter_choice"`
+	Tag            string `json:"tag,omitempty" form:"tag"`
+	MobileOperator string `json:"mobile_operator_code,omitempty" form:"mobile_operator_code"`
+
+	MailingID int64     `json:"mailing_id,omitempty" form:"mailing_id"`
+	From      time.Time `json:"from" form:"from"`
+	To        time.Time `json:"to" form:"to"`
+	Status    string    `json:"status,omitempty" form:"status"`
+}
+
+// Validate checks request, format is CSV by default
+func (r *ExportRequest) Validate() error {
+	if r.Format == "" {
+		r.Format = ExportCSV
+	}
+	if !oneOf(r.Format, ExportCSV, ExportNDJSON, ExportParquet) {
+		return fmt.Errorf("%w: format %q", ErrInvalidExport, r.Format)
+	}
+	if r.Compression != "" && r.Compression != ExportGzip {
+		return fmt.Errorf("%w: compression %q", ErrInvalidExport, r.Compression)
+	}
+
+	switch r.Kind {
+	case ExportClients:
+		switch r.FilterChoice {
+		case "":
+		case "tag":
+			if !oneOf(r.Tag, ClientTags...) {
+				return fmt.Errorf("%w: tag %q", ErrInvalidExport, r.Tag)
+			}
+		case "code":
+			if _, err := strconv.Atoi(r.MobileOperator); err != nil {
+				return fmt.Errorf("%w: mobile_operator_code %q", ErrInvalidExport, r.MobileOperator)
+			}
+		default:
+			return fmt.Errorf("%w: filter_choice %q", ErrInvalidExport, r.FilterChoice)
+		}
+	case ExportMessages:
+		if r.MailingID <= 0 {
+			return fmt.Errorf("%w: mailing_id is required", ErrInvalidExport)
+		}
+		if r.Status != "" && !oneOf(r.Status, MessageDelivered, MessageFailed, MessageDeferred) {
+			return fmt.Errorf("%w: status %q", ErrInvalidExport, r.Status)
+		}
+	default:
+		return fmt.Errorf("%w: kind %q", ErrInvalidExport, r.Kind)
+	}
+
+	return nil
+}
+
+// Audience returns mailing of audience filter of clients
+func (r *ExportRequest) Audience() *Mailing {
+	return &Mailing{FilterChoice: r.FilterChoice, Tag: r.Tag, MobileOperator: r.MobileOperator}
+}
+
+// Messages returns filter of messages, page isn't used
+func (r *ExportRequest) Messages() *MessageFilter {
+	return &MessageFilter{MailingID: r.MailingID, From: r.From, To: r.To, Status: r.Status}
+}
+
+// FileName returns name of exported file, e.g. "clients.csv.gz"
+func (r *ExportRequest) FileName() string {
+	name := r.Kind + "." + r.Format
+	if r.Compression == ExportGzip {
+		name += ".gz"
+	}
+
+	return name
+}
+
+// ContentType returns media type of exported file
+func (r *ExportRequest) ContentType() string {
+	switch {
+	case r.Compression == ExportGzip:
+		return "application/gzip"
+	case r.Format == ExportNDJSON:
+		return "application/x-ndjson"
+	case r.Format == ExportParquet:
+		return "application/vnd.apache.parquet"
+	}
+
+	return "text/csv"
+}
+
+// ExportJob is async export of request to file of export directory.
+// File is name of the file, it's set when job is done.
+type ExportJob struct {
+	ID         int64         `json:"id"`
+	Request    ExportRequest `json:"request"`
+	Status     string        `json:"status"`
+	Rows       int64         `json:"rows"`
+	File       string        `json:"file,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidImport is returned if import file can't be read at all,
+// e.g. its format is unknown or CSV header misses columns
+var ErrInvalidImport = errors.New("invalid import")
+
+// ErrImportBusy is returned if import queue is full
+var ErrImportBusy = errors.New("import queue is full")
+
+// ErrImportInterrupted fails job which was running when service stopped
+var ErrImportInterrupted = errors.New("import is interrupted by restart")
+
+// Import formats. CSV has header with columns named like JSON fields of Client,
+// NDJSON is one JSON object of Client per line.
+const (
+	ImportCSV    = "csv"
+	ImportNDJSON = "ndjson"
+)
+
+// Import statuses
+const (
+	ImportPending = "pending"
+	ImportRunning = "running"
+	ImportDone    = "done"
+	ImportFailed  = "failed"
+)
+
+// ImportJob is async import of clients from uploaded file. Valid rows are
+// upserted by phone number, invalid ones are counted in Failed and written
+// to error report. Error is set if job is failed. Actor and RequestID are
+// of request which started job, upserted batches are audited with them.
+type ImportJob struct {
+	ID         int64      `json:"id"`
+	Format     string     `json:"format"`
+	Status     string     `json:"status"`
+	Actor      string     `json:"actor"`
+	RequestID  string     `json:"request_id,omitempty"`
+	Total      int64      `json:"total"`
+	Imported   int64      `json:"imported"`
+	Failed     int64      `json:"failed"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Finished is true if job is done or failed
+func (j *ImportJob) Finished() bool {
+	return j.Status == ImportDone || j.Status == ImportFailed
+}
+
+// ImportRowError is invalid field of row of import file. Line is line of
+// file, Field is empty if row isn't parsed at all.
+type ImportRowError struct {
+	ID      int64  `json:"-"`
+	JobID   int64  `json:"-"`
+	Line    int64  `json:"line"`
+	Field   string `json:"field"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
@@ -1,40 +1,91 @@
 package entity
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
+// ErrNotFound is returned if entity doesn't exist or it's already in requested state
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned if entity is changed since version of change
+var ErrConflict = errors.New("version conflict")
+
+// ErrVersionRequired is returned if change doesn't have version of entity
+var ErrVersionRequired = errors.New("version required")
+
+// ConflictError is ErrConflict with current state of entity
+//
+//easyjson:skip
+type ConflictError struct {
+	Version int64
+	Current any
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: current version is %d", ErrConflict, e.Version)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
 /*
 For future:
 Need to add module with some group solutions to automate `key:value` merge with entities structs
 */
 
 // Identic with DB
+//
+// Priority is "transactional" or "bulk". Transactional mailings are sent
+// ahead of bulk ones if service runs with priority manager.
+//
+// DeletedAt is set by soft deletion, deleted mailing isn't sent.
+//
+// Version is incremented by every change. Patch and deletion are made
+// only for the current version, so concurrent changes aren't lost.
 type Mailing struct {
-	ID             int64     `json:"id"`
-	MessageText    string    `json:"message_text"`
-	MobileOperator string    `json:"mobile_operator_code"`
-	Tag            string    `json:"tag"`
-	FilterChoice   string    `json:"filter_choice"`
-	DateTimeStart  time.Time `json:"datetime_start"`
-	DateTimeEnd    time.Time `json:"datetime_end"`
-	IntervalStart  time.Time `json:"interval_start"`
-	IntervalEnd    time.Time `json:"interval_end"`
+	ID             int64      `json:"id"`
+	MessageText    string     `json:"message_text"`
+	MobileOperator string     `json:"mobile_operator_code"`
+	Tag            string     `json:"tag"`
+	FilterChoice   string     `json:"filter_choice"`
+	DateTimeStart  time.Time  `json:"datetime_start"`
+	DateTimeEnd    time.Time  `json:"datetime_end"`
+	IntervalStart  time.Time  `json:"interval_start"`
+	IntervalEnd    time.Time  `json:"interval_end"`
+	Priority       string     `json:"priority"`
+	Version        int64      `json:"version"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
 }
 
 type Mailings []*Mailing
 
+// Mailing priorities
+const (
+	PriorityTransactional = "transactional"
+	PriorityBulk          = "bulk"
+)
+
+// ClientTags are values of Client.Tag, client_tag enum of schema has the same ones
+var ClientTags = []string{"silver", "gold", "vip"}
+
 // Client is the User based entity
 //
 // TimeZone simply is offset about UTC+0 with step equal 15 min = 1/4 hour.
 // So if TimeZone value is 12 then timezone for client is UTC+3.
 // TimeZone can be negative it's not mistake.
+//
+// Version is incremented by every change like version of Mailing.
+// Parquet tags are columns of export like JSON tags.
 type Client struct {
-	ID             int64  `json:"id"`
-	PhoneNumber    int64  `json:"phone_number"`
-	MobileOperator int    `json:"mobile_operator_code"`
-	Tag            string `json:"tag"`
-	TimeZone       int    `json:"time_zone"`
+	ID             int64  `json:"id" parquet:"id"`
+	PhoneNumber    int64  `json:"phone_number" parquet:"phone_number"`
+	MobileOperator int    `json:"mobile_operator_code" parquet:"mobile_operator_code"`
+	Tag            string `json:"tag" parquet:"tag,dict"`
+	TimeZone       int    `json:"time_zone" parquet:"time_zone"`
+	Version        int64  `json:"version" parquet:"version"`
 }
 
 func (c Client) CheckTimeZone(IntervalStart, IntervalEnd time.Time) bool {
@@ -43,15 +94,56 @@ func (c Client) CheckTimeZone(IntervalStart, IntervalEnd time.Time) bool {
 	return currentTime.After(IntervalStart) && currentTime.Before(IntervalEnd)
 }
 
+// Window returns bounds of mailing interval in time zone of client,
+// so it's time when client could be sent to
+func (c Client) Window(IntervalStart, IntervalEnd time.Time) (opens time.Time, closes time.Time) {
+	offset := 15 * time.Minute * time.Duration(c.TimeZone)
+
+	return IntervalStart.Add(-offset), IntervalEnd.Add(-offset)
+}
+
+// FieldError is invalid field of entity
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Validate returns invalid fields of new client. Phone number is
+// 7XXXXXXXXXX, operator code has 3 digits and time zone is between
+// UTC-12 and UTC+14.
+func (c *Client) Validate() []FieldError {
+	var errs []FieldError
+
+	if c.PhoneNumber < 70000000000 || c.PhoneNumber > 79999999999 {
+		errs = append(errs, FieldError{"phone_number", "must be 7XXXXXXXXXX"})
+	}
+	if c.MobileOperator < 100 || c.MobileOperator > 999 {
+		errs = append(errs, FieldError{"mobile_operator_code", "must have 3 digits"})
+	}
+	if !oneOf(c.Tag, ClientTags...) {
+		errs = append(errs, FieldError{"tag", "must be silver, gold or vip"})
+	}
+	if c.TimeZone < -12*4 || c.TimeZone > 14*4 {
+		errs = append(errs, FieldError{"time_zone", "must be between -48 and 56 quarters of hour"})
+	}
+
+	return errs
+}
+
 type Clients []*Client
 
+// Message is attempt to send mailing to client. Deferred message wasn't
+// sent because client was outside of mailing interval in its time zone.
+//
+// Parquet tags are columns of export like JSON tags.
 type Message struct {
-	ID               int64     `json:"id"`
-	DateTimeCreation time.Time `json:"date_time_creation"`
-	Try              int       `json:"try"`
-	DeliveryStatus   bool      `json:"delivery_status"`
-	MailingID        int64     `json:"mailing_id"`
-	ClientID         int64     `json:"client_id"`
+	ID               int64     `json:"id" parquet:"id"`
+	DateTimeCreation time.Time `json:"date_time_creation" parquet:"date_time_creation,timestamp(microsecond)"`
+	Try              int       `json:"try" parquet:"try"`
+	DeliveryStatus   bool      `json:"delivery_status" parquet:"delivery_status"`
+	Deferred         bool      `json:"deferred" parquet:"deferred"`
+	MailingID        int64     `json:"mailing_id" parquet:"mailing_id"`
+	ClientID         int64     `json:"client_id" parquet:"client_id"`
 }
 
 type Messages []*Message
@@ -64,3 +156,24 @@ type MailingStats struct {
 	Succesed      int       `json:"succesed"` // About Messages DeliveryStatus atribute
 	Failed        int       `json:"failed"`   // About Messages DeliveryStatus atribute
 }
+
+// MailingChunk is part of mailing audience: clients with AfterID < ID <= LastID.
+//
+// Chunk is created by fan-out, it's published and done after all its
+// clients are sent. Mailing is complete when all its chunks are done.
+type MailingChunk struct {
+	MailingID int64  `json:"mailing_id"`
+	Seq       int    `json:"seq"`
+	AfterID   int64  `json:"after_id"`
+	LastID    int64  `json:"last_id"`
+	Status    string `json:"status"`
+}
+
+type MailingChunks []*MailingChunk
+
+// Chunk statuses
+const (
+	ChunkCreated   = "created"
+	ChunkPublished = "published"
+	ChunkDone      = "done"
+)
@@ -31,3 +31,19 @@ func TestCheckTimeZone(t *testing.T) {
 		assert.Equal(t, ok, false)
 	}
 }
+
+func TestClientValidate(t *testing.T) {
+	// test 1: valid client
+	{
+		c := entity.Client{PhoneNumber: 79001234567, MobileOperator: 900, Tag: "gold", TimeZone: -12}
+		assert.Equal(t, len(c.Validate()), 0)
+	}
+	// test 2: all the invalid fields are returned
+	{
+		c := entity.Client{PhoneNumber: 89001234567, MobileOperator: 90, Tag: "bronze", TimeZone: 57}
+		errs := c.Validate()
+		assert.Equal(t, len(errs), 4)
+		assert.Equal(t, errs[0].Field, "phone_number")
+		assert.Equal(t, errs[3].Field, "time_zone")
+	}
+}
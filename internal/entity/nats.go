@@ -1,7 +1,18 @@
 package entity
 
+import "time"
+
+// MailingWithClients is chunk of mailing audience. Clients deferred by
+// their time zone are published with NotBefore, so they're consumed
+// when window of the first of them opens.
 type MailingWithClients struct {
 	Mailing *Mailing `json:"mailing"`
 	Clients Clients  `json:"clients"`
 	Try     int      `json:"try"`
+
+	// Chunk is sequence number of audience chunk starting from 1.
+	// It's 0 for clients reserved before chunked fan-out.
+	Chunk int `json:"chunk,omitempty"`
+
+	NotBefore time.Time `json:"not_before"`
 }
@@ -0,0 +1,215 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidFilter is returned for unknown sort, status or broken cursor
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Limits of page size
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+// Sort orders
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// Mailing statuses by its time range
+const (
+	MailingScheduled = "scheduled"
+	MailingActive    = "active"
+	MailingFinished  = "finished"
+)
+
+// Message statuses by delivery
+const (
+	MessageDelivered = "delivered"
+	MessageFailed    = "failed"
+	MessageDeferred  = "deferred"
+)
+
+// Page is request of keyset pagination. Cursor is opaque, it's taken
+// from Next of the previous page with the same sort and order.
+type Page struct {
+	Limit  int    `json:"limit" form:"limit"`
+	Cursor string `json:"cursor" form:"cursor"`
+	Sort   string `json:"sort" form:"sort"`
+	Order  string `json:"order" form:"order"`
+}
+
+// normalize sets defaults and checks sort is one of sorts, the first is default
+func (p *Page) normalize(sorts ...string) error {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+
+	if p.Sort == "" {
+		p.Sort = sorts[0]
+	}
+	if !oneOf(p.Sort, sorts...) {
+		return fmt.Errorf("%w: sort %q", ErrInvalidFilter, p.Sort)
+	}
+
+	if p.Order == "" {
+		p.Order = OrderAsc
+	}
+	if !oneOf(p.Order, OrderAsc, OrderDesc) {
+		return fmt.Errorf("%w: order %q", ErrInvalidFilter, p.Order)
+	}
+
+	return nil
+}
+
+// Cursor is position after the last item of page: value of sort field
+// and ID. Time is zero if page is sorted by ID.
+type Cursor struct {
+	Sort  string    `json:"s"`
+	Order string    `json:"o"`
+	Time  time.Time `json:"t"`
+	ID    int64     `json:"i"`
+}
+
+// After returns position of the previous page, it's nil for the first page
+func (p *Page) After() (*Cursor, error) {
+	if p.Cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor: %s", ErrInvalidFilter, err)
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%w: cursor: %s", ErrInvalidFilter, err)
+	}
+
+	// Cursor of another sort points to another sequence
+	if c.Sort != p.Sort || c.Order != p.Order {
+		return nil, fmt.Errorf("%w: cursor of another sort", ErrInvalidFilter)
+	}
+
+	return &c, nil
+}
+
+// Next returns cursor after item with sort value t and id
+func (p *Page) Next(t time.Time, id int64) string {
+	data, _ := json.Marshal(Cursor{Sort: p.Sort, Order: p.Order, Time: t, ID: id})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// MailingFilter selects mailings. From and To bound DateTimeStart.
+// Deleted mailings are selected only WithDeleted.
+type MailingFilter struct {
+	Page
+	From        time.Time `json:"from" form:"from"`
+	To          time.Time `json:"to" form:"to"`
+	Status      string    `json:"status" form:"status"`
+	WithDeleted bool      `json:"with_deleted" form:"with_deleted"`
+}
+
+// Mailing sorts
+const (
+	SortID            = "id"
+	SortDateTimeStart = "datetime_start"
+	SortDateTimeEnd   = "datetime_end"
+)
+
+func (f *MailingFilter) Validate() error {
+	if err := f.normalize(SortID, SortDateTimeStart, SortDateTimeEnd); err != nil {
+		return err
+	}
+
+	if f.Status != "" && !oneOf(f.Status, MailingScheduled, MailingActive, MailingFinished) {
+		return fmt.Errorf("%w: status %q", ErrInvalidFilter, f.Status)
+	}
+
+	return nil
+}
+
+// SortTime returns value of time sort, it's zero for sort by ID
+func (m *Mailing) SortTime(sort string) time.Time {
+	switch sort {
+	case SortDateTimeStart:
+		return m.DateTimeStart
+	case SortDateTimeEnd:
+		return m.DateTimeEnd
+	}
+
+	return time.Time{}
+}
+
+// MessageFilter selects messages of mailing. From and To bound DateTimeCreation.
+type MessageFilter struct {
+	Page
+	MailingID int64     `json:"mailing_id" form:"-"`
+	From      time.Time `json:"from" form:"from"`
+	To        time.Time `json:"to" form:"to"`
+	Status    string    `json:"status" form:"status"`
+}
+
+// SortDateTimeCreation is sort of messages
+const SortDateTimeCreation = "date_time_creation"
+
+func (f *MessageFilter) Validate() error {
+	if err := f.normalize(SortID, SortDateTimeCreation); err != nil {
+		return err
+	}
+
+	if f.Status != "" && !oneOf(f.Status, MessageDelivered, MessageFailed, MessageDeferred) {
+		return fmt.Errorf("%w: status %q", ErrInvalidFilter, f.Status)
+	}
+
+	return nil
+}
+
+// SortTime returns value of time sort, it's zero for sort by ID
+func (m *Message) SortTime(sort string) time.Time {
+	if sort == SortDateTimeCreation {
+		return m.DateTimeCreation
+	}
+
+	return time.Time{}
+}
+
+// MailingsPage is page of mailings, NextCursor is empty on the last page
+type MailingsPage struct {
+	Items      Mailings `json:"items"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// MailingStatsPage is page of stats, NextCursor is empty on the last page
+type MailingStatsPage struct {
+	Items      []*MailingStats `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// MessagesPage is page of messages, NextCursor is empty on the last page
+type MessagesPage struct {
+	Items      Messages `json:"items"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+func oneOf(s string, values ...string) bool {
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+
+	return false
+}
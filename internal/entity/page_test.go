@@ -0,0 +1,52 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+func TestPage(t *testing.T) {
+	// test 1: defaults are set, limit is bounded
+	{
+		filter := entity.MailingFilter{Page: entity.Page{Limit: 100000}}
+		assert.Equal(t, filter.Validate(), nil)
+		assert.Equal(t, filter.Limit, entity.MaxPageLimit)
+		assert.Equal(t, filter.Sort, entity.SortID)
+		assert.Equal(t, filter.Order, entity.OrderAsc)
+	}
+
+	// test 2: cursor points after the last item
+	{
+		page := entity.Page{Sort: entity.SortDateTimeStart, Order: entity.OrderDesc}
+		at := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+
+		page.Cursor = page.Next(at, 42)
+		after, err := page.After()
+		assert.Equal(t, err, nil)
+		assert.Equal(t, after.ID, int64(42))
+		assert.Equal(t, after.Time.Equal(at), true)
+	}
+
+	// test 3: cursor of another sort and unknown values are rejected
+	{
+		page := entity.Page{Sort: entity.SortID, Order: entity.OrderAsc}
+		page.Cursor = page.Next(time.Time{}, 1)
+		page.Order = entity.OrderDesc
+		_, err := page.After()
+		assert.Equal(t, errors.Is(err, entity.ErrInvalidFilter), true)
+
+		page.Cursor = "%%%"
+		_, err = page.After()
+		assert.Equal(t, errors.Is(err, entity.ErrInvalidFilter), true)
+
+		filter := entity.MessageFilter{Page: entity.Page{Sort: "phone"}}
+		assert.Equal(t, errors.Is(filter.Validate(), entity.ErrInvalidFilter), true)
+
+		filter = entity.MessageFilter{Status: "lost"}
+		assert.Equal(t, errors.Is(filter.Validate(), entity.ErrInvalidFilter), true)
+	}
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Partition is monthly partition of messages created in [Month, next month)
+type Partition struct {
+	Name  string    `json:"name"`
+	Month time.Time `json:"month"`
+}
+
+type Partitions []*Partition
+
+// MonthStart returns the first day of month of t in UTC
+func MonthStart(t time.Time) time.Time {
+	y, m, _ := t.UTC().Date()
+
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,13 @@
+package entity
+
+// Payload types of mailing subjects. Type and version are carried
+// in message envelope, see broker.Envelope.
+const (
+	PayloadMailing            = "mailing"
+	PayloadMailingWithClients = "mailing_with_clients"
+)
+
+// PayloadVersion is version of mailing payloads schema. It's increased
+// on every incompatible change of Mailing or MailingWithClients.
+// Consumers accept payloads of this and older versions.
+const PayloadVersion = 1
@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Buckets of time series
+const (
+	BucketMinute = "minute"
+	BucketHour   = "hour"
+	BucketDay    = "day"
+)
+
+// MaxSeriesPoints bounds number of buckets in range of series
+const MaxSeriesPoints = 10000
+
+// SeriesFilter selects messages created in [From, To) of mailing or of all
+// the mailings if MailingID is zero. Buckets start at boundaries of TimeZone.
+type SeriesFilter struct {
+	MailingID int64     `json:"mailing_id" form:"mailing_id"`
+	From      time.Time `json:"from" form:"from"`
+	To        time.Time `json:"to" form:"to"`
+	Bucket    string    `json:"bucket" form:"bucket"`
+	TimeZone  string    `json:"tz" form:"tz"`
+
+	location *time.Location
+}
+
+// Validate sets hour bucket and UTC by default
+func (f *SeriesFilter) Validate() error {
+	if f.From.IsZero() || f.To.IsZero() || !f.From.Before(f.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidFilter)
+	}
+
+	if f.Bucket == "" {
+		f.Bucket = BucketHour
+	}
+	step, ok := bucketSteps[f.Bucket]
+	if !ok {
+		return fmt.Errorf("%w: bucket %q", ErrInvalidFilter, f.Bucket)
+	}
+	if f.To.Sub(f.From)/step > MaxSeriesPoints {
+		return fmt.Errorf("%w: more than %d buckets", ErrInvalidFilter, MaxSeriesPoints)
+	}
+
+	if f.TimeZone == "" {
+		f.TimeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(f.TimeZone)
+	if err != nil {
+		return fmt.Errorf("%w: tz: %s", ErrInvalidFilter, err)
+	}
+	f.location = loc
+
+	return nil
+}
+
+// Location of TimeZone, it's set by Validate
+func (f *SeriesFilter) Location() *time.Location {
+	if f.location == nil {
+		return time.UTC
+	}
+
+	return f.location
+}
+
+// BucketStart returns start of bucket of t in TimeZone
+func (f *SeriesFilter) BucketStart(t time.Time) time.Time {
+	return truncate(t.In(f.Location()), f.Bucket)
+}
+
+var bucketSteps = map[string]time.Duration{
+	BucketMinute: time.Minute,
+	BucketHour:   time.Hour,
+	BucketDay:    24 * time.Hour,
+}
+
+// SeriesPoint is counters of messages created in bucket starting at Time.
+// Provider doesn't report delivery, so sent messages aren't counted
+// as delivered.
+type SeriesPoint struct {
+	Time     time.Time `json:"time"`
+	Sent     int       `json:"sent"`
+	Failed   int       `json:"failed"`
+	Deferred int       `json:"deferred"`
+}
+
+// Series has point for every bucket of range, empty buckets are zero
+type Series struct {
+	MailingID int64          `json:"mailing_id,omitempty"`
+	Bucket    string         `json:"bucket"`
+	TimeZone  string         `json:"tz"`
+	Points    []*SeriesPoint `json:"points"`
+}
+
+// NewSeries fills gaps between points of filter range. Points are ordered by Time.
+func NewSeries(filter *SeriesFilter, points []*SeriesPoint) *Series {
+	loc := filter.Location()
+
+	byTime := make(map[int64]*SeriesPoint, len(points))
+	for _, p := range points {
+		byTime[p.Time.Unix()] = p
+	}
+
+	s := &Series{
+		MailingID: filter.MailingID,
+		Bucket:    filter.Bucket,
+		TimeZone:  filter.TimeZone,
+		Points:    make([]*SeriesPoint, 0, len(points)),
+	}
+	for t := truncate(filter.From.In(loc), filter.Bucket); t.Before(filter.To); t = next(t, filter.Bucket) {
+		p, ok := byTime[t.Unix()]
+		if !ok {
+			p = &SeriesPoint{}
+		}
+		p.Time = t
+		s.Points = append(s.Points, p)
+	}
+
+	return s
+}
+
+// truncate returns start of bucket in location of t
+func truncate(t time.Time, bucket string) time.Time {
+	y, m, d := t.Date()
+	switch bucket {
+	case BucketMinute:
+		return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, t.Location())
+	case BucketHour:
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, t.Location())
+	}
+
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// next returns start of the next bucket, days are calendar ones
+func next(t time.Time, bucket string) time.Time {
+	if bucket == BucketDay {
+		return t.AddDate(0, 0, 1)
+	}
+
+	return t.Add(bucketSteps[bucket])
+}
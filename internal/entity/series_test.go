@@ -0,0 +1,47 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+func TestSeries(t *testing.T) {
+	// test 1: empty buckets are filled, buckets start at midnight of time zone
+	{
+		filter := entity.SeriesFilter{
+			From:     time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC),
+			To:       time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+			Bucket:   entity.BucketDay,
+			TimeZone: "Asia/Kolkata",
+		}
+		assert.Equal(t, filter.Validate(), nil)
+
+		loc := filter.Location()
+		day := time.Date(2024, 3, 3, 0, 0, 0, 0, loc)
+		series := entity.NewSeries(&filter, []*entity.SeriesPoint{{Time: day.UTC(), Sent: 3, Failed: 1}})
+
+		// Range is 03-02 01:30 - 03-04 05:30 in +05:30, so buckets are 03-02, 03-03, 03-04
+		assert.Equal(t, len(series.Points), 3)
+		assert.Equal(t, series.Points[0].Time.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, loc)), true)
+		assert.Equal(t, series.Points[1].Time.Equal(day), true)
+		assert.Equal(t, series.Points[1].Sent, 3)
+		assert.Equal(t, series.Points[2].Sent, 0)
+	}
+
+	// test 2: range, bucket and time zone are checked
+	{
+		now := time.Now()
+		for _, filter := range []entity.SeriesFilter{
+			{From: now, To: now},
+			{From: now, To: now.Add(time.Hour), Bucket: "week"},
+			{From: now, To: now.Add(time.Hour), TimeZone: "Mars/Olympus"},
+			{From: now, To: now.AddDate(1, 0, 0), Bucket: entity.BucketMinute},
+		} {
+			assert.Equal(t, errors.Is(filter.Validate(), entity.ErrInvalidFilter), true)
+		}
+	}
+}
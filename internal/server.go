@@ -2,28 +2,51 @@ package internal
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 
 	"log/slog"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/config"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
 	v1 "gitlab.com/fluxx1on_group/event_message_service/internal/transport/http/v1"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/transport/nats_rpc"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/external"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/mq/events"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/mq/mailing"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/memory"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/postgres"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/sqlite"
+	"gitlab.com/fluxx1on_group/event_message_service/migrations"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/health"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/migrate"
 	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server"
 	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server/mod"
 	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server/server"
 )
 
+var errSchedulerStopped = errors.New("scheduler loop isn't running")
+
 type Node struct {
 	dbConn     *pgxpool.Pool
+	leaseConn  *pgxpool.Pool
+	sqliteDB   *sql.DB
 	httpServer *http.Server
+	natsConn   *nats_server.Connection
 	natsServer *server.Server
+	natsAPI    *nats_rpc.API
+	natsd      *nats_server.Embedded
+
+	// Background jobs are stopped by stopJobs
+	stopJobs context.CancelFunc
+	jobs     sync.WaitGroup
 }
 
 func (n *Node) Start(cfg *config.Config) {
@@ -31,31 +54,76 @@ func (n *Node) Start(cfg *config.Config) {
 
 	// ___ Connections ___
 
-	// PostgreSQL
-	n.dbConn, err = pgxpool.New(context.Background(), cfg.PostgreSQL.URL)
+	// Storage
+	repos, err := n.openStorage(cfg)
 	if err != nil {
-		slog.Error("PostgreSQL unreached", slog.String("ErrorMsg", err.Error()))
+		slog.Error("Storage unavailable", slog.String("ErrorMsg", err.Error()))
 		panic("startup")
 	}
 
 	// Nats
-	conn := nats_server.OpenConnection(nats_server.Config{
-		URL: cfg.Nats.URL,
+	if cfg.Nats.Embedded.Enabled {
+		n.natsd, err = nats_server.StartEmbedded(nats_server.EmbeddedConfig{
+			ServerName: cfg.Nats.Embedded.ServerName,
+			StoreDir:   cfg.Nats.Embedded.StoreDir,
+			Host:       cfg.Nats.Embedded.Host,
+			Port:       cfg.Nats.Embedded.Port,
+		})
+		if err != nil {
+			slog.Error("Embedded NATS server failed", slog.String("ErrorMsg", err.Error()))
+			panic("startup")
+		}
+	}
+
+	conn, err := nats_server.OpenConnection(nats_server.Config{
+		URL:      cfg.Nats.URL,
+		Embedded: n.natsd,
+		Auth: nats_server.Auth{
+			User:            cfg.Nats.Auth.User,
+			Password:        cfg.Nats.Auth.Password,
+			Token:           cfg.Nats.Auth.Token,
+			NKeySeedFile:    cfg.Nats.Auth.NKeySeedFile,
+			CredentialsFile: cfg.Nats.Auth.CredentialsFile,
+		},
+		TLS: nats_server.TLS{
+			Enabled:  cfg.Nats.TLS.Enabled,
+			CAFile:   cfg.Nats.TLS.CAFile,
+			CertFile: cfg.Nats.TLS.CertFile,
+			KeyFile:  cfg.Nats.TLS.KeyFile,
+		},
+		ConnectAttempts:  cfg.Nats.ConnectAttempts,
+		ConnectWait:      cfg.Nats.ConnectWait,
+		ReconnectWait:    cfg.Nats.ReconnectWait,
+		MaxReconnectWait: cfg.Nats.MaxReconnectWait,
 	})
+	if err != nil {
+		slog.Error("NATS unreached", slog.String("ErrorMsg", err.Error()))
+		panic("startup")
+	}
+	n.natsConn = conn
+	prometheus.MustRegister(nats_server.Collectors()...)
 
-	// ___ Infrastructure Layer ___
+	err = conn.EnsureStream(cfg.Nats.Stream, cfg.Nats.Subjects...)
+	if err != nil {
+		slog.Error("NATS stream unavailable", slog.String("ErrorMsg", err.Error()))
+		panic("startup")
+	}
 
-	// Repositories
-	var (
-		clientRepo  *postgres.ClientRepo  = postgres.NewClient(n.dbConn)
-		mailingRepo *postgres.MailingRepo = postgres.NewMailing(n.dbConn)
-		messageRepo *postgres.MessageRepo = postgres.NewMessage(n.dbConn)
-	)
+	err = conn.EnsureLogStream(cfg.Nats.EventsStream, cfg.Nats.EventsMaxAge, cfg.Nats.EventsPrefix+".>")
+	if err != nil {
+		slog.Error("NATS events stream unavailable", slog.String("ErrorMsg", err.Error()))
+		panic("startup")
+	}
+
+	broker := nats_server.NewBroker(conn, cfg.Nats.Stream, cfg.Nats.AckWait)
+
+	// ___ Infrastructure Layer ___
 
 	// Producers
 	var (
-		mailingProducer *mailing.GeneralProducer    = mailing.NewGeneral(conn, cfg.Nats.Subjects[0])
-		clientProducer  *mailing.AdditionalProducer = mailing.NewAdditional(conn, cfg.Nats.Subjects[1])
+		mailingProducer *mailing.GeneralProducer    = mailing.NewGeneral(broker, cfg.Nats.Subjects[0])
+		clientProducer  *mailing.AdditionalProducer = mailing.NewAdditional(broker, cfg.Nats.Subjects[1])
+		eventProducer   *events.Producer            = events.New(broker, cfg.Nats.EventsPrefix)
 	)
 
 	// External API
@@ -65,24 +133,66 @@ func (n *Node) Start(cfg *config.Config) {
 
 	// -
 	var (
-		client  *usecase.ClientUseCase  = usecase.NewClient(clientRepo)
+		client  *usecase.ClientUseCase  = usecase.NewClient(repos.client, repos.audit, eventProducer, repos.tx)
 		mailing *usecase.MailingUseCase = usecase.NewMailing(
-			mailingRepo, messageRepo, mailingProducer,
+			repos.mailing, repos.message, repos.audit, mailingProducer, eventProducer, repos.tx,
 		)
+		audit    *usecase.AuditUseCase    = usecase.NewAudit(repos.audit)
 		consumer *usecase.ConsumerUseCase = usecase.NewConsumer(
-			messageRepo, clientRepo, mailingRepo, sender, clientProducer,
+			repos.message, repos.client, repos.mailing, repos.chunk, repos.lease,
+			sender, clientProducer, eventProducer, cfg.ChunkSize,
+		)
+		retention *usecase.RetentionUseCase = usecase.NewRetention(
+			repos.partition, cfg.Retention.Months, cfg.Retention.Ahead, cfg.Retention.ExportDir,
+		)
+		imports *usecase.ImportUseCase = usecase.NewImport(
+			repos.imports, repos.client, repos.audit, repos.tx, cfg.Import.Dir, cfg.Import.BatchSize, cfg.Import.MaxErrors, cfg.Import.Queue,
+		)
+		exports *usecase.ExportUseCase = usecase.NewExport(
+			repos.exports, repos.client, repos.message, cfg.Export.Dir, cfg.Export.Queue,
 		)
 	)
 
+	if err := imports.Recover(context.Background()); err != nil {
+		slog.Error("Import jobs recovery failed", slog.String("ErrorMsg", err.Error()))
+		panic("startup")
+	}
+
+	if err := exports.Recover(context.Background()); err != nil {
+		slog.Error("Export jobs recovery failed", slog.String("ErrorMsg", err.Error()))
+		panic("startup")
+	}
+
 	// ___ Transport Layer ___
 
 	// NatsServer - Consumer server
 	natsRouter := nats_rpc.NewRouter(consumer, cfg.Nats.Subjects...)
-	n.natsServer = server.New(conn, natsRouter, mod.TaskType)
+	limits := make(map[string]mod.Limit, len(cfg.Nats.Limits))
+	for subj, l := range cfg.Nats.Limits {
+		limits[subj] = mod.Limit{Workers: l.Workers, Queue: l.Queue}
+	}
+
+	n.natsServer = server.New(broker, natsRouter, mod.ManagerType(cfg.Nats.Manager),
+		server.Queue(cfg.Nats.Queue),
+		server.AckWait(cfg.Nats.AckWait),
+		server.Limits(mod.Limit{
+			Workers: cfg.Nats.Workers.Workers,
+			Queue:   cfg.Nats.Workers.Queue,
+		}, limits),
+		server.Transactional(entity.PriorityTransactional),
+		server.Starvation(mod.Starvation{
+			Ratio:   cfg.Nats.Starvation.Ratio,
+			MaxWait: cfg.Nats.Starvation.MaxWait,
+		}),
+	)
+	prometheus.MustRegister(mod.Collectors()...)
+
+	// NATS RPC - API
+	n.natsAPI = nats_rpc.NewAPI(conn, cfg.Nats.RPCPrefix, cfg.Nats.Queue, client, mailing)
 
 	// HTTP Server - API
 	handler := gin.New()
-	v1.NewRouter(handler, client, mailing)
+	v1.NewRouter(handler, client, mailing, audit, imports, exports, n.checker(cfg))
 	n.httpServer = &http.Server{
 		Addr:    cfg.Addr,
 		Handler: handler,
@@ -90,25 +200,231 @@ func (n *Node) Start(cfg *config.Config) {
 
 	// Servers starting
 	go func() {
-		if err := n.httpServer.ListenAndServe(); err != nil {
+		if err := n.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			slog.Error("Failed to serve", slog.String("ErrorMsg", err.Error()))
 		}
 	}()
 
 	slog.Info("HTTP server started.", slog.String("HTTP Address", cfg.Addr))
 
+	if err := n.natsAPI.Start(); err != nil {
+		slog.Error("NATS RPC subscription failed", slog.String("ErrorMsg", err.Error()))
+		panic("startup")
+	}
+
+	slog.Info("NATS RPC started.", slog.String("Subjects prefix", cfg.Nats.RPCPrefix))
+
 	// Start consumers
 	go n.natsServer.StartWorkers()
 
 	slog.Info("NATS server started.", slog.String("NATS Address", cfg.Nats.Host))
+
+	// Background jobs
+	var jobsCtx context.Context
+	jobsCtx, n.stopJobs = context.WithCancel(context.Background())
+
+	n.jobs.Add(1)
+	go func() {
+		defer n.jobs.Done()
+		retention.Run(jobsCtx, cfg.Retention.Interval)
+	}()
+
+	slog.Info("Retention job started.", slog.Int("Months", cfg.Retention.Months))
+
+	n.jobs.Add(1)
+	go func() {
+		defer n.jobs.Done()
+		imports.Run(jobsCtx)
+	}()
+
+	slog.Info("Import job started.", slog.String("Uploads", cfg.Import.Dir))
+
+	n.jobs.Add(1)
+	go func() {
+		defer n.jobs.Done()
+		exports.Run(jobsCtx)
+	}()
+
+	slog.Info("Export job started.", slog.String("Files", cfg.Export.Dir))
+}
+
+// repositories of storage selected by config
+type repositories struct {
+	client    usecase.ClientRepo
+	mailing   usecase.MailingRepo
+	message   usecase.MessageRepo
+	chunk     usecase.ChunkRepo
+	lease     usecase.LeaseRepo
+	partition usecase.PartitionRepo
+	audit     usecase.AuditRepo
+	imports   usecase.ImportRepo
+	exports   usecase.ExportRepo
+	tx        usecase.TxManager
+}
+
+// openStorage connects to storage of config and returns its repositories
+func (n *Node) openStorage(cfg *config.Config) (*repositories, error) {
+	switch cfg.Storage {
+	case config.StorageMemory:
+		store := memory.NewStore()
+		slog.Warn("Memory storage is used, data isn't persisted")
+
+		return &repositories{
+			client:    memory.NewClient(store),
+			mailing:   memory.NewMailing(store),
+			message:   memory.NewMessage(store),
+			chunk:     memory.NewChunk(store),
+			lease:     memory.NewLease(store),
+			partition: memory.NewPartition(store),
+			audit:     memory.NewAudit(store),
+			imports:   memory.NewImport(store),
+			exports:   memory.NewExport(store),
+			tx:        memory.NewTxManager(store),
+		}, nil
+
+	case config.StorageSQLite:
+		var err error
+		n.sqliteDB, err = sqlite.Open(context.Background(), cfg.SQLite.Path)
+		if err != nil {
+			return nil, fmt.Errorf("SQLite unavailable: %w", err)
+		}
+
+		return &repositories{
+			client:    sqlite.NewClient(n.sqliteDB),
+			mailing:   sqlite.NewMailing(n.sqliteDB),
+			message:   sqlite.NewMessage(n.sqliteDB),
+			chunk:     sqlite.NewChunk(n.sqliteDB),
+			lease:     sqlite.NewLease(),
+			partition: sqlite.NewPartition(n.sqliteDB),
+			audit:     sqlite.NewAudit(n.sqliteDB),
+			imports:   sqlite.NewImport(n.sqliteDB),
+			exports:   sqlite.NewExport(n.sqliteDB),
+			tx:        sqlite.NewTxManager(n.sqliteDB),
+		}, nil
+
+	case config.StoragePostgres:
+		var err error
+		n.dbConn, err = pgxpool.New(context.Background(), cfg.PostgreSQL.URL)
+		if err != nil {
+			return nil, fmt.Errorf("PostgreSQL unreached: %w", err)
+		}
+
+		// Lease holds its connection while chunk is sent, so leases have own
+		// pool of connection for every worker. Workers of config are validated,
+		// so they're the workers of server.Limits.
+		leaseCfg, err := pgxpool.ParseConfig(cfg.PostgreSQL.URL)
+		if err != nil {
+			return nil, fmt.Errorf("PostgreSQL unreached: %w", err)
+		}
+		leaseCfg.MaxConns = int32(cfg.Nats.Concurrency())
+		n.leaseConn, err = pgxpool.NewWithConfig(context.Background(), leaseCfg)
+		if err != nil {
+			return nil, fmt.Errorf("PostgreSQL unreached: %w", err)
+		}
+
+		if cfg.SchemaCheck {
+			if err = n.checkSchema(); err != nil {
+				return nil, fmt.Errorf("PostgreSQL schema check failed: %w", err)
+			}
+		}
+
+		return &repositories{
+			client:    postgres.NewClient(n.dbConn),
+			mailing:   postgres.NewMailing(n.dbConn),
+			message:   postgres.NewMessage(n.dbConn),
+			chunk:     postgres.NewChunk(n.dbConn),
+			lease:     postgres.NewLease(n.leaseConn),
+			partition: postgres.NewPartition(n.dbConn),
+			audit:     postgres.NewAudit(n.dbConn),
+			imports:   postgres.NewImport(n.dbConn),
+			exports:   postgres.NewExport(n.dbConn),
+			tx:        postgres.NewTxManager(n.dbConn),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown storage %q", cfg.Storage)
+}
+
+// checker checks dependencies of node for readiness probe.
+// Memory storage has nothing to check.
+func (n *Node) checker(cfg *config.Config) *health.Checker {
+	checker := health.New(cfg.Health.Timeout)
+
+	switch {
+	case n.dbConn != nil:
+		checker.Add("database", n.dbConn.Ping)
+	case n.sqliteDB != nil:
+		checker.Add("database", n.sqliteDB.PingContext)
+	}
+
+	checker.Add("nats", n.natsConn.Check)
+	checker.Add("jetstream", func(ctx context.Context) error {
+		return n.natsConn.CheckStream(ctx, cfg.Nats.Stream)
+	})
+	checker.Add("scheduler", func(context.Context) error {
+		if !n.natsServer.Alive() {
+			return errSchedulerStopped
+		}
+		return nil
+	})
+
+	return checker
+}
+
+// checkSchema refuses schema older than the latest embedded migration
+func (n *Node) checkSchema() error {
+	ms, err := migrate.Load(migrations.Postgres, migrations.PostgresDir)
+	if err != nil {
+		return err
+	}
+
+	return migrate.Check(context.Background(), n.dbConn, ms)
 }
 
-func (n *Node) Stop() {
-	n.httpServer.Close()
+// Stop shutdowns node gracefully. Background jobs and NATS consumption are
+// stopped first and in-flight sends are finished or checkpointed until ctx is done.
+// Then HTTP server, NATS connection and database are closed
+// in order of their dependencies.
+func (n *Node) Stop(ctx context.Context) {
+	n.stopJobs()
+	n.jobs.Wait()
+
+	if err := n.natsServer.Shutdown(ctx); err != nil {
+		slog.Error("NATS consumers shutdown failed", slog.String("ErrorMsg", err.Error()))
+	} else {
+		slog.Info("NATS consumers stopped")
+	}
+
+	if err := n.natsAPI.Shutdown(); err != nil {
+		slog.Error("NATS RPC shutdown failed", slog.String("ErrorMsg", err.Error()))
+	}
+
+	if err := n.httpServer.Shutdown(ctx); err != nil {
+		slog.Error("HTTP server shutdown failed", slog.String("ErrorMsg", err.Error()))
+		n.httpServer.Close()
+	}
 	slog.Info("HTTP server shutted down")
 
-	n.natsServer.Shutdown()
+	if err := n.natsConn.Close(ctx); err != nil {
+		slog.Error("NATS connection closing failed", slog.String("ErrorMsg", err.Error()))
+	}
+
+	if n.natsd != nil {
+		n.natsd.Close()
+	}
+
+	if n.leaseConn != nil {
+		n.leaseConn.Close()
+	}
+
+	if n.dbConn != nil {
+		n.dbConn.Close()
+		slog.Info("PostgreSQL disconnected")
+	}
 
-	n.dbConn.Close()
-	slog.Info("PostgreSQL disconnected")
+	if n.sqliteDB != nil {
+		if err := n.sqliteDB.Close(); err != nil {
+			slog.Error("SQLite closing failed", slog.String("ErrorMsg", err.Error()))
+		}
+	}
 }
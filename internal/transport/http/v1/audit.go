@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+const auditPath = basePath + "/audit"
+
+type auditRoutes struct {
+	a usecase.Audit
+}
+
+func newAuditRoutes(handler *gin.RouterGroup, a usecase.Audit) {
+	r := &auditRoutes{a}
+
+	handler.GET("/audit", r.GetRecords)
+}
+
+// @Summary 	Get audit log
+// @Description Get page of changes of clients and mailings. Next page is requested with next_cursor of the previous one.
+// @ID 			getAudit
+// @Tags 		audit
+// @Accept 		json
+// @Produce 	json
+// @Param 		limit query int false "Page size, 50 by default, 500 at most"
+// @Param 		cursor query string false "next_cursor of the previous page"
+// @Param 		sort query string false "Sort field" Enums(id, created_at)
+// @Param 		order query string false "Sort order" Enums(asc, desc)
+// @Param 		entity query string false "Changed entity" Enums(client, mailing, import)
+// @Param 		entity_id query int false "ID of changed entity"
+// @Param 		actor query string false "Actor of change, X-Actor header of its request"
+// @Param 		from query string false "Changes made from, RFC3339"
+// @Param 		to query string false "Changes made before, RFC3339"
+// @Success  	200 {object} entity.AuditPage "Audit records received"
+// @Failure 	400 {object} errorResponse "Bad request, invalid filter"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to receive audit records"
+// @Router 		/audit [get]
+func (r *auditRoutes) GetRecords(c *gin.Context) {
+	var filter entity.AuditFilter
+
+	err := c.ShouldBindQuery(&filter)
+	if err == nil {
+		err = filter.Validate()
+	}
+	if err != nil {
+		slog.Warn("Unexpected request query",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid filter",
+		})
+		pushMetric(http.MethodGet, auditPath, http.StatusBadRequest)
+		return
+	}
+
+	page, err := r.a.GetRecords(c.Request.Context(), &filter)
+	if err != nil {
+		slog.Info("Audit reading failed",
+			slog.Int("Status code", http.StatusInternalServerError),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{
+			ErrorMsg: "Internal server error, failed to receive audit records",
+		})
+		pushMetric(http.MethodGet, auditPath, http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("Audit reading succeeded",
+		slog.Int("Status code", http.StatusOK),
+		slog.Int("Records", len(page.Items)))
+	c.JSON(http.StatusOK, page)
+	pushMetric(http.MethodGet, auditPath, http.StatusOK)
+}
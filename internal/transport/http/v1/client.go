@@ -1,8 +1,10 @@
 package v1
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
@@ -10,6 +12,8 @@ import (
 )
 
 const clientPath = basePath + "/client"
+const clientRestorePath = clientPath + "/restore"
+const clientIDPath = clientPath + "/:id"
 
 type clientRoutes struct {
 	c usecase.Client
@@ -20,12 +24,64 @@ func newClientRoutes(handler *gin.RouterGroup, t usecase.Client) {
 
 	h := handler.Group("/client")
 	{
+		h.GET("/:id", r.Get)
 		h.PUT("/", r.Add)
 		h.PATCH("/", r.Patch)
 		h.DELETE("/", r.Delete)
+		h.POST("/restore", r.Restore)
 	}
 }
 
+// @Summary 	Get client
+// @Description Get client that isn't deleted. ETag is its version, it's passed in If-Match of changes.
+// @ID 			getClient
+// @Tags 		clients
+// @Accept 		json
+// @Produce 	json
+// @Param 		id path int true "Client ID"
+// @Success 	200 {object} entity.Client "Client received"
+// @Header 		200 {string} ETag "Version of client"
+// @Failure 	400 {object} errorResponse "Bad request, invalid ID"
+// @Failure 	404 {object} errorResponse "Client doesn't exist"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to receive client"
+// @Router 		/client/{id} [get]
+func (r *clientRoutes) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		slog.Warn("Unexpected request path",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid ID",
+		})
+		pushMetric(http.MethodGet, clientIDPath, http.StatusBadRequest)
+		return
+	}
+
+	client, err := r.c.Get(c.Request.Context(), &entity.Client{ID: id})
+	if err != nil {
+		code, msg := http.StatusInternalServerError, "Internal server error, failed to receive client"
+		if errors.Is(err, entity.ErrNotFound) {
+			code, msg = http.StatusNotFound, "Client doesn't exist"
+		}
+
+		slog.Info("Client reading failed",
+			slog.Int("Status code", code),
+			slog.String("ErrorMsg", err.Error()),
+			slog.Int64("ClientID", id))
+		c.AbortWithStatusJSON(code, errorResponse{ErrorMsg: msg})
+		pushMetric(http.MethodGet, clientIDPath, code)
+		return
+	}
+
+	slog.Info("Client reading succeeded",
+		slog.Int("Status code", http.StatusOK),
+		slog.Int64("ClientID", id))
+	c.Header("ETag", etag(client.Version))
+	c.JSON(http.StatusOK, client)
+	pushMetric(http.MethodGet, clientIDPath, http.StatusOK)
+}
+
 // @Summary 	Create a new client
 // @Description Create new client entity.
 // @ID 			createClient
@@ -88,15 +144,23 @@ func (r *clientRoutes) Add(c *gin.Context) {
 // @Tags 		clients
 // @Accept 		json
 // @Produce 	json
-// @Param 		client body entity.Client true "Client object to update"
+// @Param 		client body entity.Client true "Client object to update, version is required without If-Match"
+// @Param 		If-Match header string false "ETag of client, it overrides version of body"
 // @Success 	204 "Client updated successfully"
+// @Header 		204 {string} ETag "New version of client"
 // @Failure 	400 "Bad request, invalid JSON data"
+// @Failure 	404 "Client doesn't exist"
+// @Failure 	409 {object} entity.Client "Client is changed since version, current client is returned"
+// @Failure 	428 "Version is required"
 // @Failure 	500 "Internal server error, failed to update client"
 // @Router 		/client [patch]
 func (r *clientRoutes) Patch(c *gin.Context) {
 	var client entity.Client
 
 	err := c.ShouldBindJSON(&client)
+	if err == nil {
+		err = ifMatch(c, &client.Version)
+	}
 	if err != nil {
 		slog.Warn("Unexpected request body",
 			slog.Int("Status code", http.StatusBadRequest),
@@ -108,8 +172,10 @@ func (r *clientRoutes) Patch(c *gin.Context) {
 
 	err = r.c.Patch(c.Request.Context(), &client)
 	if err != nil {
+		code := changeStatus(err)
+
 		slog.Info("Client updating failed",
-			slog.Int("Status code", http.StatusInternalServerError),
+			slog.Int("Status code", code),
 			slog.String("ErrorMsg", err.Error()),
 			slog.Group("Client",
 				slog.Int64("PhoneNumber", client.PhoneNumber),
@@ -119,8 +185,8 @@ func (r *clientRoutes) Patch(c *gin.Context) {
 			),
 		)
 
-		c.AbortWithStatus(http.StatusInternalServerError)
-		pushMetric(http.MethodPatch, clientPath, http.StatusInternalServerError)
+		abortChange(c, code, err)
+		pushMetric(http.MethodPatch, clientPath, code)
 		return
 	}
 
@@ -134,25 +200,33 @@ func (r *clientRoutes) Patch(c *gin.Context) {
 		),
 	)
 
+	c.Header("ETag", etag(client.Version))
 	c.Status(http.StatusNoContent)
 	pushMetric(http.MethodPatch, clientPath, http.StatusNoContent)
 }
 
 // @Summary 	Delete existing client
-// @Description Delete client from db.
+// @Description Delete client, deleted client isn't included into audiences.
 // @ID 			deleteClient
 // @Tags 		clients
 // @Accept 		json
 // @Produce 	json
-// @Param 		client body entity.Client true "Client object to delete"
+// @Param 		client body entity.Client true "Client object to delete, version is required without If-Match"
+// @Param 		If-Match header string false "ETag of client, it overrides version of body"
 // @Success 	204 "Client deleted successfully"
 // @Failure 	400 "Bad request, invalid JSON data"
+// @Failure 	404 "Client doesn't exist"
+// @Failure 	409 {object} entity.Client "Client is changed since version, current client is returned"
+// @Failure 	428 "Version is required"
 // @Failure 	500 "Internal server error, failed to delete client"
 // @Router 		/client [delete]
 func (r *clientRoutes) Delete(c *gin.Context) {
 	var client entity.Client
 
 	err := c.ShouldBindJSON(&client)
+	if err == nil {
+		err = ifMatch(c, &client.Version)
+	}
 	if err != nil {
 		slog.Warn("Unexpected request body",
 			slog.Int("Status code", http.StatusBadRequest),
@@ -164,8 +238,10 @@ func (r *clientRoutes) Delete(c *gin.Context) {
 
 	err = r.c.Delete(c.Request.Context(), &client)
 	if err != nil {
+		code := changeStatus(err)
+
 		slog.Info("Client deletion failed",
-			slog.Int("Status code", http.StatusInternalServerError),
+			slog.Int("Status code", code),
 			slog.String("ErrorMsg", err.Error()),
 			slog.Group("Client",
 				slog.Int64("PhoneNumber", client.PhoneNumber),
@@ -175,8 +251,8 @@ func (r *clientRoutes) Delete(c *gin.Context) {
 			),
 		)
 
-		c.AbortWithStatus(http.StatusInternalServerError)
-		pushMetric(http.MethodDelete, clientPath, http.StatusInternalServerError)
+		abortChange(c, code, err)
+		pushMetric(http.MethodDelete, clientPath, code)
 		return
 	}
 
@@ -193,3 +269,51 @@ func (r *clientRoutes) Delete(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 	pushMetric(http.MethodDelete, clientPath, http.StatusNoContent)
 }
+
+// @Summary 	Restore deleted client
+// @Description Restore deleted client.
+// @ID 			restoreClient
+// @Tags 		clients
+// @Accept 		json
+// @Produce 	json
+// @Param 		client body entity.Client true "Client object to restore"
+// @Success 	204 "Client restored successfully"
+// @Failure 	400 "Bad request, invalid JSON data"
+// @Failure 	404 "Client isn't deleted"
+// @Failure 	500 "Internal server error, failed to restore client"
+// @Router 		/client/restore [post]
+func (r *clientRoutes) Restore(c *gin.Context) {
+	var client entity.Client
+
+	err := c.ShouldBindJSON(&client)
+	if err != nil {
+		slog.Warn("Unexpected request body",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatus(http.StatusBadRequest)
+		pushMetric(http.MethodPost, clientRestorePath, http.StatusBadRequest)
+		return
+	}
+
+	err = r.c.Restore(c.Request.Context(), &client)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, entity.ErrNotFound) {
+			code = http.StatusNotFound
+		}
+
+		slog.Info("Client restoring failed",
+			slog.Int("Status code", code),
+			slog.String("ErrorMsg", err.Error()),
+			slog.Int64("ClientID", client.ID))
+		c.AbortWithStatus(code)
+		pushMetric(http.MethodPost, clientRestorePath, code)
+		return
+	}
+
+	slog.Info("Client restoring succeeded",
+		slog.Int("Status code", http.StatusNoContent),
+		slog.Int64("ClientID", client.ID))
+	c.Status(http.StatusNoContent)
+	pushMetric(http.MethodPost, clientRestorePath, http.StatusNoContent)
+}
@@ -0,0 +1,262 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+const exportsPath = basePath + "/exports"
+const exportClientsPath = exportsPath + "/clients"
+const exportMessagesPath = exportsPath + "/messages"
+const exportIDPath = exportsPath + "/:id"
+const exportFilePath = exportIDPath + "/file"
+
+type exportRoutes struct {
+	e usecase.Export
+}
+
+func newExportRoutes(handler *gin.RouterGroup, e usecase.Export) {
+	r := &exportRoutes{e}
+
+	h := handler.Group("/exports")
+	{
+		h.GET("/clients", r.GetClients)
+		h.GET("/messages", r.GetMessages)
+		h.POST("/", r.Start)
+		h.GET("/:id", r.Get)
+		h.GET("/:id/file", r.GetFile)
+	}
+}
+
+// @Summary 	Export clients
+// @Description Stream clients of audience filter as CSV, NDJSON or Parquet. Deleted clients aren't exported.
+// @Description Rows are written while they're read from database, so response is cut if export fails midway.
+// @ID 			exportClients
+// @Tags 		exports
+// @Produce 	text/csv,application/x-ndjson,application/vnd.apache.parquet,application/gzip
+// @Param 		format query string false "Format of file, csv by default" Enums(csv, ndjson, parquet)
+// @Param 		compression query string false "Compression of file" Enums(gzip)
+// @Param 		filter_choice query string false "Audience filter like filter of mailing" Enums(tag, code)
+// @Param 		tag query string false "Tag of clients of tag filter" Enums(silver, gold, vip)
+// @Param 		mobile_operator_code query string false "Operator code of clients of code filter"
+// @Success 	200 {string} string "Exported clients"
+// @Failure 	400 {object} errorResponse "Bad request, invalid export"
+// @Router 		/exports/clients [get]
+func (r *exportRoutes) GetClients(c *gin.Context) {
+	r.stream(c, entity.ExportClients, exportClientsPath)
+}
+
+// @Summary 	Export messages
+// @Description Stream messages of mailing as CSV, NDJSON or Parquet.
+// @Description Rows are written while they're read from database, so response is cut if export fails midway.
+// @ID 			exportMessages
+// @Tags 		exports
+// @Produce 	text/csv,application/x-ndjson,application/vnd.apache.parquet,application/gzip
+// @Param 		mailing_id query int true "Mailing ID"
+// @Param 		format query string false "Format of file, csv by default" Enums(csv, ndjson, parquet)
+// @Param 		compression query string false "Compression of file" Enums(gzip)
+// @Param 		from query string false "Messages created from, RFC3339"
+// @Param 		to query string false "Messages created before, RFC3339"
+// @Param 		status query string false "Message status" Enums(delivered, failed, deferred)
+// @Success 	200 {string} string "Exported messages"
+// @Failure 	400 {object} errorResponse "Bad request, invalid export"
+// @Router 		/exports/messages [get]
+func (r *exportRoutes) GetMessages(c *gin.Context) {
+	r.stream(c, entity.ExportMessages, exportMessagesPath)
+}
+
+// @Summary 	Start export
+// @Description Export clients or messages to file in background. Request has fields of query of streaming export and kind of exported rows.
+// @Description File is available by ID of export when it's done.
+// @ID 			startExport
+// @Tags 		exports
+// @Accept 		json
+// @Produce 	json
+// @Param 		request body entity.ExportRequest true "Export request"
+// @Success 	202 {object} entity.ExportJob "Export started"
+// @Header 		202 {string} Location "Path of export"
+// @Failure 	400 {object} errorResponse "Bad request, invalid export"
+// @Failure 	503 {object} errorResponse "Export queue is full"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to start export"
+// @Router 		/exports [post]
+func (r *exportRoutes) Start(c *gin.Context) {
+	var req entity.ExportRequest
+
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		slog.Warn("Unexpected request body",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid export",
+		})
+		pushMetric(http.MethodPost, exportsPath, http.StatusBadRequest)
+		return
+	}
+
+	job, err := r.e.Start(c.Request.Context(), &req)
+	if err != nil {
+		code, msg := http.StatusInternalServerError, "Internal server error, failed to start export"
+		switch {
+		case errors.Is(err, entity.ErrInvalidExport):
+			code, msg = http.StatusBadRequest, "Bad request, invalid export"
+		case errors.Is(err, entity.ErrExportBusy):
+			code, msg = http.StatusServiceUnavailable, "Export queue is full"
+		}
+
+		slog.Info("Export starting failed",
+			slog.Int("Status code", code),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(code, errorResponse{ErrorMsg: msg})
+		pushMetric(http.MethodPost, exportsPath, code)
+		return
+	}
+
+	slog.Info("Export started",
+		slog.Int("Status code", http.StatusAccepted),
+		slog.Int64("JobID", job.ID),
+		slog.String("Kind", req.Kind))
+	c.Header("Location", fmt.Sprintf("%s/%d", exportsPath, job.ID))
+	c.JSON(http.StatusAccepted, job)
+	pushMetric(http.MethodPost, exportsPath, http.StatusAccepted)
+}
+
+// @Summary 	Get export
+// @Description Get status of export
+// @ID 			getExport
+// @Tags 		exports
+// @Accept 		json
+// @Produce 	json
+// @Param 		id path int true "Export ID"
+// @Success 	200 {object} entity.ExportJob "Export received"
+// @Failure 	400 {object} errorResponse "Bad request, invalid ID"
+// @Failure 	404 {object} errorResponse "Export doesn't exist"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to receive export"
+// @Router 		/exports/{id} [get]
+func (r *exportRoutes) Get(c *gin.Context) {
+	id, ok := exportID(c, exportIDPath)
+	if !ok {
+		return
+	}
+
+	job, err := r.e.Get(c.Request.Context(), &entity.ExportJob{ID: id})
+	if err != nil {
+		exportFailed(c, exportIDPath, id, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+	pushMetric(http.MethodGet, exportIDPath, http.StatusOK)
+}
+
+// @Summary 	Get export file
+// @Description Download file of finished export
+// @ID 			getExportFile
+// @Tags 		exports
+// @Produce 	text/csv,application/x-ndjson,application/vnd.apache.parquet,application/gzip
+// @Param 		id path int true "Export ID"
+// @Success 	200 {file} file "Exported file"
+// @Failure 	400 {object} errorResponse "Bad request, invalid ID"
+// @Failure 	404 {object} errorResponse "Export doesn't exist"
+// @Failure 	409 {object} errorResponse "Export isn't finished or failed"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to receive export"
+// @Router 		/exports/{id}/file [get]
+func (r *exportRoutes) GetFile(c *gin.Context) {
+	id, ok := exportID(c, exportFilePath)
+	if !ok {
+		return
+	}
+
+	job, err := r.e.Get(c.Request.Context(), &entity.ExportJob{ID: id})
+	if err != nil {
+		exportFailed(c, exportFilePath, id, err)
+		return
+	}
+
+	path, err := r.e.File(job)
+	if err != nil {
+		exportFailed(c, exportFilePath, id, err)
+		return
+	}
+
+	c.Header("Content-Type", job.Request.ContentType())
+	c.FileAttachment(path, job.Request.FileName())
+	pushMetric(http.MethodGet, exportFilePath, http.StatusOK)
+}
+
+// stream writes export of query to response. Status is sent before rows
+// are read, so failure of export cuts response and it's only logged.
+func (r *exportRoutes) stream(c *gin.Context, kind, path string) {
+	var req entity.ExportRequest
+
+	err := c.ShouldBindQuery(&req)
+	req.Kind = kind
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		slog.Warn("Unexpected request query",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid export",
+		})
+		pushMetric(http.MethodGet, path, http.StatusBadRequest)
+		return
+	}
+
+	c.Header("Content-Type", req.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, req.FileName()))
+	c.Status(http.StatusOK)
+
+	n, err := r.e.Write(c.Request.Context(), &req, c.Writer)
+	if err != nil {
+		slog.Error("Export streaming failed",
+			slog.String("Kind", kind),
+			slog.Int64("Rows", n),
+			slog.String("ErrorMsg", err.Error()))
+	}
+	pushMetric(http.MethodGet, path, http.StatusOK)
+}
+
+// exportID returns ID of path parameter, request is aborted if it's invalid
+func exportID(c *gin.Context, path string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		slog.Warn("Unexpected request path",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid ID",
+		})
+		pushMetric(http.MethodGet, path, http.StatusBadRequest)
+		return 0, false
+	}
+
+	return id, true
+}
+
+// exportFailed aborts request of export by its error
+func exportFailed(c *gin.Context, path string, id int64, err error) {
+	code, msg := http.StatusInternalServerError, "Internal server error, failed to receive export"
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		code, msg = http.StatusNotFound, "Export doesn't exist"
+	case errors.Is(err, entity.ErrExportNotReady):
+		code, msg = http.StatusConflict, "Export isn't finished or failed"
+	}
+
+	slog.Info("Export reading failed",
+		slog.Int("Status code", code),
+		slog.String("ErrorMsg", err.Error()),
+		slog.Int64("JobID", id))
+	c.AbortWithStatusJSON(code, errorResponse{ErrorMsg: msg})
+	pushMetric(http.MethodGet, path, code)
+}
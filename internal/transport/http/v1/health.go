@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/health"
+)
+
+const livezPath = "/livez"
+const readyzPath = "/readyz"
+
+type healthRoutes struct {
+	checker *health.Checker
+}
+
+func newHealthRoutes(handler *gin.Engine, checker *health.Checker) {
+	r := &healthRoutes{checker}
+
+	// /healthz is kept as liveness probe of older deployments
+	handler.GET("/healthz", r.Live)
+	handler.GET(livezPath, r.Live)
+	handler.GET(readyzPath, r.Ready)
+}
+
+// @Summary 	Liveness probe
+// @Description Process is alive and serves HTTP, dependencies aren't checked
+// @ID 			livez
+// @Tags 		health
+// @Success 	200 "Alive"
+// @Router 		/livez [get]
+func (r *healthRoutes) Live(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+// @Summary 	Readiness probe
+// @Description Check database, NATS connection, JetStream stream and scheduler of delayed messages.
+// @Description Every check reports its latency, optional checks don't affect readiness.
+// @ID 			readyz
+// @Tags 		health
+// @Produce 	json
+// @Success 	200 {object} health.Report "Ready"
+// @Failure 	503 {object} health.Report "Not ready, some of critical checks failed"
+// @Router 		/readyz [get]
+func (r *healthRoutes) Ready(c *gin.Context) {
+	report := r.checker.Check(c.Request.Context())
+	if !report.Ready() {
+		failed := make([]string, 0, len(report.Checks))
+		for name, res := range report.Checks {
+			if res.Status == health.StatusDown && !res.Optional {
+				failed = append(failed, name)
+			}
+		}
+
+		slog.Warn("Service isn't ready",
+			slog.Int("Status code", http.StatusServiceUnavailable),
+			slog.Any("Checks", failed))
+		c.JSON(http.StatusServiceUnavailable, report)
+		pushMetric(http.MethodGet, readyzPath, http.StatusServiceUnavailable)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+	pushMetric(http.MethodGet, readyzPath, http.StatusOK)
+}
@@ -0,0 +1,191 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+const importsPath = basePath + "/imports"
+const importIDPath = importsPath + "/:id"
+const importErrorsPath = importIDPath + "/errors"
+
+type importRoutes struct {
+	i usecase.Import
+}
+
+func newImportRoutes(handler *gin.RouterGroup, i usecase.Import) {
+	r := &importRoutes{i}
+
+	h := handler.Group("/imports")
+	{
+		h.POST("/", r.Start)
+		h.GET("/:id", r.Get)
+		h.GET("/:id/errors", r.GetErrors)
+	}
+}
+
+// @Summary 	Import clients
+// @Description Upload CSV or NDJSON file of clients. Rows are validated and upserted by phone number in background, progress is available by ID of import.
+// @Description CSV has header with columns phone_number, mobile_operator_code, tag and time_zone.
+// @ID 			startImport
+// @Tags 		imports
+// @Accept 		multipart/form-data
+// @Produce 	json
+// @Param 		file formData file true "CSV or NDJSON file of clients"
+// @Param 		format query string false "Format of file, it's taken from file extension by default" Enums(csv, ndjson)
+// @Success 	202 {object} entity.ImportJob "Import started"
+// @Header 		202 {string} Location "Path of import"
+// @Failure 	400 {object} errorResponse "Bad request, invalid file or format"
+// @Failure 	503 {object} errorResponse "Import queue is full"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to start import"
+// @Router 		/imports [post]
+func (r *importRoutes) Start(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		slog.Warn("Unexpected request body",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid file or format",
+		})
+		pushMetric(http.MethodPost, importsPath, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	format := c.Query("format")
+	if format == "" {
+		format = importFormat(header.Filename)
+	}
+
+	job, err := r.i.Start(c.Request.Context(), format, file)
+	if err != nil {
+		code, msg := http.StatusInternalServerError, "Internal server error, failed to start import"
+		switch {
+		case errors.Is(err, entity.ErrInvalidImport):
+			code, msg = http.StatusBadRequest, "Bad request, invalid file or format"
+		case errors.Is(err, entity.ErrImportBusy):
+			code, msg = http.StatusServiceUnavailable, "Import queue is full"
+		}
+
+		slog.Info("Import starting failed",
+			slog.Int("Status code", code),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(code, errorResponse{ErrorMsg: msg})
+		pushMetric(http.MethodPost, importsPath, code)
+		return
+	}
+
+	slog.Info("Import started",
+		slog.Int("Status code", http.StatusAccepted),
+		slog.Int64("JobID", job.ID),
+		slog.String("File", header.Filename))
+	c.Header("Location", fmt.Sprintf("%s/%d", importsPath, job.ID))
+	c.JSON(http.StatusAccepted, job)
+	pushMetric(http.MethodPost, importsPath, http.StatusAccepted)
+}
+
+// @Summary 	Get import
+// @Description Get status and progress of import
+// @ID 			getImport
+// @Tags 		imports
+// @Accept 		json
+// @Produce 	json
+// @Param 		id path int true "Import ID"
+// @Success 	200 {object} entity.ImportJob "Import received"
+// @Failure 	400 {object} errorResponse "Bad request, invalid ID"
+// @Failure 	404 {object} errorResponse "Import doesn't exist"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to receive import"
+// @Router 		/imports/{id} [get]
+func (r *importRoutes) Get(c *gin.Context) {
+	job, ok := r.job(c, importIDPath)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+	pushMetric(http.MethodGet, importIDPath, http.StatusOK)
+}
+
+// @Summary 	Get import error report
+// @Description Download CSV of invalid rows of import with columns line, field, value and message.
+// @Description Field is empty if row isn't parsed at all.
+// @ID 			getImportErrors
+// @Tags 		imports
+// @Produce 	text/csv
+// @Param 		id path int true "Import ID"
+// @Success 	200 {string} string "Error report"
+// @Failure 	400 {object} errorResponse "Bad request, invalid ID"
+// @Failure 	404 {object} errorResponse "Import doesn't exist"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to receive import"
+// @Router 		/imports/{id}/errors [get]
+func (r *importRoutes) GetErrors(c *gin.Context) {
+	job, ok := r.job(c, importErrorsPath)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import_%d_errors.csv"`, job.ID))
+	c.Status(http.StatusOK)
+
+	// Status is already sent, report is cut if it fails
+	if err := r.i.WriteReport(c.Request.Context(), job, c.Writer); err != nil {
+		slog.Error("Import report writing failed",
+			slog.Int64("JobID", job.ID),
+			slog.String("ErrorMsg", err.Error()))
+	}
+	pushMetric(http.MethodGet, importErrorsPath, http.StatusOK)
+}
+
+// job returns import of path parameter, request is aborted if it isn't received
+func (r *importRoutes) job(c *gin.Context, path string) (*entity.ImportJob, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		slog.Warn("Unexpected request path",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid ID",
+		})
+		pushMetric(http.MethodGet, path, http.StatusBadRequest)
+		return nil, false
+	}
+
+	job, err := r.i.Get(c.Request.Context(), &entity.ImportJob{ID: id})
+	if err != nil {
+		code, msg := http.StatusInternalServerError, "Internal server error, failed to receive import"
+		if errors.Is(err, entity.ErrNotFound) {
+			code, msg = http.StatusNotFound, "Import doesn't exist"
+		}
+
+		slog.Info("Import reading failed",
+			slog.Int("Status code", code),
+			slog.String("ErrorMsg", err.Error()),
+			slog.Int64("JobID", id))
+		c.AbortWithStatusJSON(code, errorResponse{ErrorMsg: msg})
+		pushMetric(http.MethodGet, path, code)
+		return nil, false
+	}
+
+	return job, true
+}
+
+// importFormat returns format of file by its extension, JSON Lines are NDJSON
+func importFormat(filename string) string {
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".jsonl", ".ndjson":
+		return entity.ImportNDJSON
+	default:
+		return strings.TrimPrefix(ext, ".")
+	}
+}
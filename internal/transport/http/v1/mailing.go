@@ -1,8 +1,11 @@
 package v1
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
@@ -10,7 +13,12 @@ import (
 )
 
 const mailingPath = basePath + "/mailing"
+const mailingListPath = mailingPath + "/"
 const mailingStatsPath = mailingPath + "/stats"
+const mailingSeriesPath = mailingPath + "/series"
+const mailingRestorePath = mailingPath + "/restore"
+const mailingArchivePath = mailingPath + "/archive"
+const mailingIDPath = mailingPath + "/:id"
 
 type mailingRoutes struct {
 	m usecase.Mailing
@@ -21,25 +29,109 @@ func newMailingRoutes(handler *gin.RouterGroup, m usecase.Mailing) {
 
 	h := handler.Group("/mailing")
 	{
+		h.GET("/", r.List)
 		h.GET("/stats", r.GetStats)
+		h.GET("/series", r.GetSeries)
+		h.GET("/:id", r.Get)
 		h.POST("/", r.ReadMessages)
 		h.PUT("/", r.Add)
 		h.PATCH("/", r.Patch)
 		h.DELETE("/", r.Delete)
+		h.POST("/restore", r.Restore)
+		h.POST("/archive", r.Archive)
 	}
 }
 
+// @Summary 	List mailings
+// @Description Get page of mailings. Next page is requested with next_cursor of the previous one.
+// @ID 			listMailings
+// @Tags 		mailings
+// @Accept 		json
+// @Produce 	json
+// @Param 		limit query int false "Page size, 50 by default, 500 at most"
+// @Param 		cursor query string false "next_cursor of the previous page"
+// @Param 		sort query string false "Sort field" Enums(id, datetime_start, datetime_end)
+// @Param 		order query string false "Sort order" Enums(asc, desc)
+// @Param 		from query string false "Mailings started from, RFC3339"
+// @Param 		to query string false "Mailings started before, RFC3339"
+// @Param 		status query string false "Mailing status" Enums(scheduled, active, finished)
+// @Param 		with_deleted query bool false "Deleted mailings are listed too"
+// @Success  	200 {object} entity.MailingsPage "Mailings received"
+// @Failure 	400 {object} errorResponse "Bad request, invalid filter"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to receive mailings"
+// @Router 		/mailing/ [get]
+func (r *mailingRoutes) List(c *gin.Context) {
+	var filter entity.MailingFilter
+
+	err := c.ShouldBindQuery(&filter)
+	if err == nil {
+		err = filter.Validate()
+	}
+	if err != nil {
+		slog.Warn("Unexpected request query",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid filter",
+		})
+		pushMetric(http.MethodGet, mailingListPath, http.StatusBadRequest)
+		return
+	}
+
+	mailings, err := r.m.GetMailings(c.Request.Context(), &filter)
+	if err != nil {
+		slog.Info("Mailings reading failed",
+			slog.Int("Status code", http.StatusInternalServerError),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{
+			ErrorMsg: "Internal server error, failed to receive mailings",
+		})
+		pushMetric(http.MethodGet, mailingListPath, http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("Mailings reading succeeded",
+		slog.Int("Status code", http.StatusOK))
+	c.JSON(http.StatusOK, mailings)
+	pushMetric(http.MethodGet, mailingListPath, http.StatusOK)
+}
+
 // @Summary 	Get MailingStats
-// @Description Get MailingStats about all Mailings.
+// @Description Get page of MailingStats. Next page is requested with next_cursor of the previous one.
 // @ID 			getStats
 // @Tags 		mailings
 // @Accept 		json
 // @Produce 	json
-// @Success  	200 {object} []entity.MailingStats "MailingStats received"
+// @Param 		limit query int false "Page size, 50 by default, 500 at most"
+// @Param 		cursor query string false "next_cursor of the previous page"
+// @Param 		sort query string false "Sort field" Enums(id, datetime_start, datetime_end)
+// @Param 		order query string false "Sort order" Enums(asc, desc)
+// @Param 		from query string false "Mailings started from, RFC3339"
+// @Param 		to query string false "Mailings started before, RFC3339"
+// @Param 		status query string false "Mailing status" Enums(scheduled, active, finished)
+// @Success  	200 {object} entity.MailingStatsPage "MailingStats received"
+// @Failure 	400 {object} errorResponse "Bad request, invalid filter"
 // @Failure 	500 {object} errorResponse "Internal server error, failed to receive stats"
 // @Router 		/mailing/stats [get]
 func (r *mailingRoutes) GetStats(c *gin.Context) {
-	stats, err := r.m.GetMailingStats(c.Request.Context())
+	var filter entity.MailingFilter
+
+	err := c.ShouldBindQuery(&filter)
+	if err == nil {
+		err = filter.Validate()
+	}
+	if err != nil {
+		slog.Warn("Unexpected request query",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid filter",
+		})
+		pushMetric(http.MethodGet, mailingStatsPath, http.StatusBadRequest)
+		return
+	}
+
+	stats, err := r.m.GetMailingStats(c.Request.Context(), &filter)
 	if err != nil {
 		slog.Info("MailingStats reading failed",
 			slog.Int("Status code", http.StatusInternalServerError),
@@ -57,39 +149,157 @@ func (r *mailingRoutes) GetStats(c *gin.Context) {
 	pushMetric(http.MethodGet, mailingStatsPath, http.StatusOK)
 }
 
+// @Summary 	Get delivery time series
+// @Description Get counters of sent, failed and deferred messages by time buckets
+// @Description of mailing or of all the mailings. Empty buckets are zero.
+// @ID 			getSeries
+// @Tags 		mailings
+// @Accept 		json
+// @Produce 	json
+// @Param 		mailing_id query int false "Mailing ID, all the mailings by default"
+// @Param 		from query string true "Messages created from, RFC3339"
+// @Param 		to query string true "Messages created before, RFC3339"
+// @Param 		bucket query string false "Bucket size, hour by default" Enums(minute, hour, day)
+// @Param 		tz query string false "IANA time zone of buckets, UTC by default"
+// @Success  	200 {object} entity.Series "Series received"
+// @Failure 	400 {object} errorResponse "Bad request, invalid filter"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to receive series"
+// @Router 		/mailing/series [get]
+func (r *mailingRoutes) GetSeries(c *gin.Context) {
+	var filter entity.SeriesFilter
+
+	err := c.ShouldBindQuery(&filter)
+	if err == nil {
+		err = filter.Validate()
+	}
+	if err != nil {
+		slog.Warn("Unexpected request query",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid filter",
+		})
+		pushMetric(http.MethodGet, mailingSeriesPath, http.StatusBadRequest)
+		return
+	}
+
+	series, err := r.m.GetSeries(c.Request.Context(), &filter)
+	if err != nil {
+		slog.Info("Series reading failed",
+			slog.Int("Status code", http.StatusInternalServerError),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{
+			ErrorMsg: "Internal server error, failed to receive series",
+		})
+		pushMetric(http.MethodGet, mailingSeriesPath, http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("Series reading succeeded",
+		slog.Int("Status code", http.StatusOK))
+	c.JSON(http.StatusOK, series)
+	pushMetric(http.MethodGet, mailingSeriesPath, http.StatusOK)
+}
+
+// @Summary 	Get mailing
+// @Description Get mailing that isn't deleted. ETag is its version, it's passed in If-Match of changes.
+// @ID 			getMailing
+// @Tags 		mailings
+// @Accept 		json
+// @Produce 	json
+// @Param 		id path int true "Mailing ID"
+// @Success 	200 {object} entity.Mailing "Mailing received"
+// @Header 		200 {string} ETag "Version of mailing"
+// @Failure 	400 {object} errorResponse "Bad request, invalid ID"
+// @Failure 	404 {object} errorResponse "Mailing doesn't exist"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to receive mailing"
+// @Router 		/mailing/{id} [get]
+func (r *mailingRoutes) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		slog.Warn("Unexpected request path",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+			ErrorMsg: "Bad request, invalid ID",
+		})
+		pushMetric(http.MethodGet, mailingIDPath, http.StatusBadRequest)
+		return
+	}
+
+	mailing, err := r.m.Get(c.Request.Context(), &entity.Mailing{ID: id})
+	if err != nil {
+		code, msg := http.StatusInternalServerError, "Internal server error, failed to receive mailing"
+		if errors.Is(err, entity.ErrNotFound) {
+			code, msg = http.StatusNotFound, "Mailing doesn't exist"
+		}
+
+		slog.Info("Mailing reading failed",
+			slog.Int("Status code", code),
+			slog.String("ErrorMsg", err.Error()),
+			slog.Int64("MailingID", id))
+		c.AbortWithStatusJSON(code, errorResponse{ErrorMsg: msg})
+		pushMetric(http.MethodGet, mailingIDPath, code)
+		return
+	}
+
+	slog.Info("Mailing reading succeeded",
+		slog.Int("Status code", http.StatusOK),
+		slog.Int64("MailingID", id))
+	c.Header("ETag", etag(mailing.Version))
+	c.JSON(http.StatusOK, mailing)
+	pushMetric(http.MethodGet, mailingIDPath, http.StatusOK)
+}
+
 // @Summary 	Post with Mailing
-// @Description Get Messages by existing mailing.
+// @Description Get page of Messages by existing mailing. Next page is requested with next_cursor of the previous one.
 // @ID 			getMessagesByMailing
 // @Tags 		mailings
 // @Accept 		json
 // @Produce 	json
 // @Param 		mailing body entity.Mailing true "Mailing object to select Messages by mailing's filters"
-// @Success  	200 {object} entity.Messages "Messages catched successfully"
-// @Failure 	400 {object} errorResponse "Bad request, invalid JSON data"
+// @Param 		limit query int false "Page size, 50 by default, 500 at most"
+// @Param 		cursor query string false "next_cursor of the previous page"
+// @Param 		sort query string false "Sort field" Enums(id, date_time_creation)
+// @Param 		order query string false "Sort order" Enums(asc, desc)
+// @Param 		from query string false "Messages created from, RFC3339"
+// @Param 		to query string false "Messages created before, RFC3339"
+// @Param 		status query string false "Delivery status" Enums(delivered, failed)
+// @Success  	200 {object} entity.MessagesPage "Messages catched successfully"
+// @Failure 	400 {object} errorResponse "Bad request, invalid JSON data or filter"
 // @Failure 	500 {object} errorResponse "Internal server error, failed to catch messages"
 // @Router 		/mailing [post]
 func (r *mailingRoutes) ReadMessages(c *gin.Context) {
-	var mailing entity.Mailing
+	var (
+		mailing entity.Mailing
+		filter  entity.MessageFilter
+	)
 
 	err := c.ShouldBindJSON(&mailing)
+	if err == nil {
+		err = c.ShouldBindQuery(&filter)
+	}
+	if err == nil {
+		err = filter.Validate()
+	}
 	if err != nil {
 		slog.Warn("Unexpected request body",
 			slog.Int("Status code", http.StatusBadRequest),
 			slog.String("ErrorMsg", err.Error()))
 		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
-			ErrorMsg: "Bad request, invalid JSON data",
+			ErrorMsg: "Bad request, invalid JSON data or filter",
 		})
 		pushMetric(http.MethodPost, mailingPath, http.StatusBadRequest)
 		return
 	}
+	filter.MailingID = mailing.ID
 
-	msgs, err := r.m.GetMessagesByMailing(c.Request.Context(), &mailing)
+	msgs, err := r.m.GetMessagesByMailing(c.Request.Context(), &filter)
 	if err != nil {
 		slog.Info("Messages reading failed",
 			slog.Int("Status code", http.StatusInternalServerError),
 			slog.String("ErrorMsg", err.Error()),
-			slog.Group("Mailing", mailing.ID, mailing.Tag, mailing.MobileOperator,
-				mailing.MessageText[0:40], mailing.DateTimeStart))
+			mailingGroup(&mailing))
 		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{
 			ErrorMsg: "Internal server error, failed to catch messages",
 		})
@@ -99,8 +309,7 @@ func (r *mailingRoutes) ReadMessages(c *gin.Context) {
 
 	slog.Info("Messages reading succeeded",
 		slog.Int("Status code", http.StatusOK),
-		slog.Group("Mailing", mailing.ID, mailing.Tag, mailing.MobileOperator,
-			mailing.MessageText[0:40], mailing.DateTimeStart))
+		mailingGroup(&mailing))
 	c.JSON(http.StatusOK, msgs)
 	pushMetric(http.MethodPost, mailingPath, http.StatusOK)
 }
@@ -134,8 +343,7 @@ func (r *mailingRoutes) Add(c *gin.Context) {
 		slog.Info("Mailing creation failed",
 			slog.Int("Status code", http.StatusInternalServerError),
 			slog.String("ErrorMsg", err.Error()),
-			slog.Group("Mailing", mailing.Tag, mailing.MobileOperator,
-				mailing.MessageText[0:40], mailing.DateTimeStart))
+			mailingGroup(&mailing))
 		c.AbortWithStatus(http.StatusInternalServerError)
 		pushMetric(http.MethodPut, mailingPath, http.StatusInternalServerError)
 		return
@@ -143,8 +351,7 @@ func (r *mailingRoutes) Add(c *gin.Context) {
 
 	slog.Info("Mailing creation succeeded",
 		slog.Int("Status code", http.StatusOK),
-		slog.Group("Mailing", mailing.Tag, mailing.MobileOperator,
-			mailing.MessageText[0:40], mailing.DateTimeStart))
+		mailingGroup(&mailing))
 	c.Status(http.StatusCreated)
 	pushMetric(http.MethodPut, mailingPath, http.StatusCreated)
 }
@@ -155,15 +362,23 @@ func (r *mailingRoutes) Add(c *gin.Context) {
 // @Tags 		mailings
 // @Accept 		json
 // @Produce 	json
-// @Param 		mailing body entity.Mailing true "Mailing object to update"
+// @Param 		mailing body entity.Mailing true "Mailing object to update, version is required without If-Match"
+// @Param 		If-Match header string false "ETag of mailing, it overrides version of body"
 // @Success 	204 "Mailing updated successfully"
+// @Header 		204 {string} ETag "New version of mailing"
 // @Failure 	400 "Bad request, invalid JSON data"
+// @Failure 	404 "Mailing doesn't exist"
+// @Failure 	409 {object} entity.Mailing "Mailing is changed since version, current mailing is returned"
+// @Failure 	428 "Version is required"
 // @Failure 	500 "Internal server error, failed to update mailing"
 // @Router 		/mailing [patch]
 func (r *mailingRoutes) Patch(c *gin.Context) {
 	var mailing entity.Mailing
 
 	err := c.ShouldBindJSON(&mailing)
+	if err == nil {
+		err = ifMatch(c, &mailing.Version)
+	}
 	if err != nil {
 		slog.Warn("Unexpected request body",
 			slog.Int("Status code", http.StatusBadRequest),
@@ -175,39 +390,47 @@ func (r *mailingRoutes) Patch(c *gin.Context) {
 
 	err = r.m.Patch(c.Request.Context(), &mailing)
 	if err != nil {
+		code := changeStatus(err)
+
 		slog.Info("Mailing updating failed",
-			slog.Int("Status code", http.StatusInternalServerError),
+			slog.Int("Status code", code),
 			slog.String("ErrorMsg", err.Error()),
-			slog.Group("Mailing", mailing.Tag, mailing.MobileOperator,
-				mailing.MessageText[0:40], mailing.DateTimeStart))
-		c.AbortWithStatus(http.StatusInternalServerError)
-		pushMetric(http.MethodPatch, mailingPath, http.StatusInternalServerError)
+			mailingGroup(&mailing))
+		abortChange(c, code, err)
+		pushMetric(http.MethodPatch, mailingPath, code)
 		return
 	}
 
 	slog.Info("Mailing updating succeeded",
 		slog.Int("Status code", http.StatusOK),
-		slog.Group("Mailing", mailing.Tag, mailing.MobileOperator,
-			mailing.MessageText[0:40], mailing.DateTimeStart))
+		mailingGroup(&mailing))
+	c.Header("ETag", etag(mailing.Version))
 	c.Status(http.StatusNoContent)
 	pushMetric(http.MethodPatch, mailingPath, http.StatusNoContent)
 }
 
 // @Summary 	Delete existing mailing
-// @Description Delete mailing from db.
+// @Description Delete mailing, its sending is stopped. Messages of mailing are kept.
 // @ID 			deleteMailing
 // @Tags 		mailings
 // @Accept 		json
 // @Produce 	json
-// @Param 		mailing body entity.Mailing true "Mailing object to delete"
+// @Param 		mailing body entity.Mailing true "Mailing object to delete, version is required without If-Match"
+// @Param 		If-Match header string false "ETag of mailing, it overrides version of body"
 // @Success 	204 "Mailing deleted successfully"
 // @Failure 	400 "Bad request, invalid JSON data"
+// @Failure 	404 "Mailing doesn't exist"
+// @Failure 	409 {object} entity.Mailing "Mailing is changed since version, current mailing is returned"
+// @Failure 	428 "Version is required"
 // @Failure 	500 "Internal server error, failed to delete mailing"
 // @Router 		/mailing [delete]
 func (r *mailingRoutes) Delete(c *gin.Context) {
 	var mailing entity.Mailing
 
 	err := c.ShouldBindJSON(&mailing)
+	if err == nil {
+		err = ifMatch(c, &mailing.Version)
+	}
 	if err != nil {
 		slog.Warn("Unexpected request body",
 			slog.Int("Status code", http.StatusBadRequest),
@@ -219,20 +442,143 @@ func (r *mailingRoutes) Delete(c *gin.Context) {
 
 	err = r.m.Delete(c.Request.Context(), &mailing)
 	if err != nil {
+		code := changeStatus(err)
+
 		slog.Info("Mailing deletion failed",
-			slog.Int("Status code", http.StatusInternalServerError),
+			slog.Int("Status code", code),
 			slog.String("ErrorMsg", err.Error()),
-			slog.Group("Mailing", mailing.Tag, mailing.MobileOperator,
-				mailing.MessageText[0:40], mailing.DateTimeStart))
-		c.AbortWithStatus(http.StatusInternalServerError)
-		pushMetric(http.MethodDelete, mailingPath, http.StatusInternalServerError)
+			mailingGroup(&mailing))
+		abortChange(c, code, err)
+		pushMetric(http.MethodDelete, mailingPath, code)
 		return
 	}
 
 	slog.Info("Mailing deletion succeeded",
 		slog.Int("Status code", http.StatusOK),
-		slog.Group("Mailing", mailing.Tag, mailing.MobileOperator,
-			mailing.MessageText[0:40], mailing.DateTimeStart))
+		mailingGroup(&mailing))
 	c.Status(http.StatusNoContent)
 	pushMetric(http.MethodDelete, mailingPath, http.StatusNoContent)
 }
+
+// @Summary 	Restore mailing
+// @Description Restore deleted or archived mailing with its messages. Stopped sending isn't resumed.
+// @ID 			restoreMailing
+// @Tags 		mailings
+// @Accept 		json
+// @Produce 	json
+// @Param 		mailing body entity.Mailing true "Mailing object to restore"
+// @Success 	204 "Mailing restored successfully"
+// @Failure 	400 "Bad request, invalid JSON data"
+// @Failure 	404 "Mailing is neither deleted nor archived"
+// @Failure 	500 "Internal server error, failed to restore mailing"
+// @Router 		/mailing/restore [post]
+func (r *mailingRoutes) Restore(c *gin.Context) {
+	var mailing entity.Mailing
+
+	err := c.ShouldBindJSON(&mailing)
+	if err != nil {
+		slog.Warn("Unexpected request body",
+			slog.Int("Status code", http.StatusBadRequest),
+			slog.String("ErrorMsg", err.Error()))
+		c.AbortWithStatus(http.StatusBadRequest)
+		pushMetric(http.MethodPost, mailingRestorePath, http.StatusBadRequest)
+		return
+	}
+
+	err = r.m.Restore(c.Request.Context(), &mailing)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, entity.ErrNotFound) {
+			code = http.StatusNotFound
+		}
+
+		slog.Info("Mailing restoring failed",
+			slog.Int("Status code", code),
+			slog.String("ErrorMsg", err.Error()),
+			mailingGroup(&mailing))
+		c.AbortWithStatus(code)
+		pushMetric(http.MethodPost, mailingRestorePath, code)
+		return
+	}
+
+	slog.Info("Mailing restoring succeeded",
+		slog.Int("Status code", http.StatusNoContent),
+		mailingGroup(&mailing))
+	c.Status(http.StatusNoContent)
+	pushMetric(http.MethodPost, mailingRestorePath, http.StatusNoContent)
+}
+
+type archiveRequest struct {
+	// Before is bound of mailings end, it's now by default
+	Before time.Time `json:"before"`
+}
+
+type archiveResponse struct {
+	Archived int `json:"archived"`
+}
+
+// @Summary 	Archive finished mailings
+// @Description Move mailings finished before time with their messages to archive.
+// @ID 			archiveMailings
+// @Tags 		mailings
+// @Accept 		json
+// @Produce 	json
+// @Param 		request body archiveRequest false "Bound of mailings end, now by default"
+// @Success 	200 {object} archiveResponse "Mailings archived"
+// @Failure 	400 {object} errorResponse "Bad request, invalid JSON data"
+// @Failure 	500 {object} errorResponse "Internal server error, failed to archive mailings"
+// @Router 		/mailing/archive [post]
+func (r *mailingRoutes) Archive(c *gin.Context) {
+	var req archiveRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			slog.Warn("Unexpected request body",
+				slog.Int("Status code", http.StatusBadRequest),
+				slog.String("ErrorMsg", err.Error()))
+			c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{
+				ErrorMsg: "Bad request, invalid JSON data",
+			})
+			pushMetric(http.MethodPost, mailingArchivePath, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Before.IsZero() {
+		req.Before = time.Now()
+	}
+
+	n, err := r.m.Archive(c.Request.Context(), req.Before)
+	if err != nil {
+		slog.Info("Mailings archiving failed",
+			slog.Int("Status code", http.StatusInternalServerError),
+			slog.String("ErrorMsg", err.Error()),
+			slog.Int("Archived", n))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{
+			ErrorMsg: "Internal server error, failed to archive mailings",
+		})
+		pushMetric(http.MethodPost, mailingArchivePath, http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("Mailings archiving succeeded",
+		slog.Int("Status code", http.StatusOK),
+		slog.Int("Archived", n))
+	c.JSON(http.StatusOK, archiveResponse{Archived: n})
+	pushMetric(http.MethodPost, mailingArchivePath, http.StatusOK)
+}
+
+// mailingGroup returns short log representation of mailing
+func mailingGroup(mailing *entity.Mailing) slog.Attr {
+	text := mailing.MessageText
+	if len(text) > 40 {
+		text = text[:40]
+	}
+
+	return slog.Group("Mailing",
+		slog.Int64("ID", mailing.ID),
+		slog.String("Tag", mailing.Tag),
+		slog.String("MobileOperator", mailing.MobileOperator),
+		slog.String("MessageText", text),
+		slog.Time("DateTimeStart", mailing.DateTimeStart),
+	)
+}
@@ -1,15 +1,17 @@
 package v1
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nats-io/nuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/health"
 
 	_ "gitlab.com/fluxx1on_group/event_message_service/docs"
 )
@@ -40,6 +42,13 @@ func pushMetric(method string, endpoint string, code int) {
 
 const basePath string = "/v1"
 
+// Headers of request identity. Request ID is generated if it's missing
+// and it's returned in response.
+const (
+	RequestIDHeader = "X-Request-Id"
+	ActorHeader     = "X-Actor"
+)
+
 // NewRouter -.
 // Swagger spec:
 // @title       Go Mailing Service
@@ -51,17 +60,27 @@ const basePath string = "/v1"
 
 // @host      	localhost:8080
 // @BasePath    /v1
-func NewRouter(handler *gin.Engine, client usecase.Client, mailing usecase.Mailing) {
+func NewRouter(
+	handler *gin.Engine,
+	client usecase.Client,
+	mailing usecase.Mailing,
+	audit usecase.Audit,
+	imports usecase.Import,
+	exports usecase.Export,
+	checker *health.Checker,
+) {
 	// Options
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
+	handler.Use(envelopeContext())
+	handler.Use(auditContext())
 
 	// Swagger
 	swaggerHandler := ginSwagger.WrapHandler(swaggerFiles.Handler)
 	handler.GET("/docs/*any", swaggerHandler)
 
-	// K8s probe
-	handler.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+	// K8s probes
+	newHealthRoutes(handler, checker)
 
 	// Prometheus metrics
 	handler.GET("/metrics", gin.WrapH(promhttp.Handler()))
@@ -73,5 +92,43 @@ func NewRouter(handler *gin.Engine, client usecase.Client, mailing usecase.Maili
 	{
 		newClientRoutes(h, client)
 		newMailingRoutes(h, mailing)
+		newAuditRoutes(h, audit)
+		newImportRoutes(h, imports)
+		newExportRoutes(h, exports)
+	}
+}
+
+// envelopeContext puts trace context and tenant of request into its context,
+// so they're carried in envelopes of published messages.
+func envelopeContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if trace := c.GetHeader(broker.TraceHeader); trace != "" {
+			ctx = broker.WithTrace(ctx, trace)
+		}
+		if tenant := c.GetHeader(broker.TenantHeader); tenant != "" {
+			ctx = broker.WithTenant(ctx, tenant)
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// auditContext puts actor and ID of request into its context,
+// so changes made by request are recorded with them.
+func auditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = nuid.Next()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := usecase.WithRequestID(c.Request.Context(), requestID)
+		ctx = usecase.WithActor(ctx, c.GetHeader(ActorHeader))
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
 	}
 }
@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// etag returns entity tag of version of entity
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatch sets version from If-Match header. Version of body is kept
+// if request doesn't have the header.
+func ifMatch(c *gin.Context, version *int64) error {
+	tag := c.GetHeader("If-Match")
+	if tag == "" {
+		return nil
+	}
+
+	v, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(tag, "W/"), `"`), 10, 64)
+	if err != nil || v <= 0 {
+		return fmt.Errorf("invalid If-Match %q", tag)
+	}
+	*version = v
+
+	return nil
+}
+
+// changeStatus returns status of failed change of entity
+func changeStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrVersionRequired):
+		return http.StatusPreconditionRequired
+	}
+
+	return http.StatusInternalServerError
+}
+
+// abortChange aborts failed change, conflict is answered with current state of entity
+func abortChange(c *gin.Context, code int, err error) {
+	var conflict *entity.ConflictError
+	if errors.As(err, &conflict) {
+		c.Header("ETag", etag(conflict.Version))
+		c.AbortWithStatusJSON(code, conflict.Current)
+		return
+	}
+
+	c.AbortWithStatus(code)
+}
@@ -0,0 +1,189 @@
+package nats_rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server"
+)
+
+// RequestIDHeader is shared by request and its response.
+// It's generated if request doesn't have one.
+const RequestIDHeader = "Request-Id"
+
+// ActorHeader identifies who makes request, it's anonymous if it's missing
+const ActorHeader = "Actor"
+
+const _requestTimeout = 10 * time.Second
+
+var RequestsTotalCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rpc_requests_total",
+		Help: "NATS RPC Responses",
+	},
+	[]string{"subject", "code"},
+)
+
+func pushMetric(subject string, code string) {
+	RequestsTotalCounter.With(
+		prometheus.Labels{
+			"subject": subject,
+			"code":    code,
+		},
+	).Inc()
+}
+
+// Request is JSON envelope of RPC request
+type Request struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// Response is JSON envelope of RPC response. Data is set on success and
+// Error on failure. Change of entity of stale version fails with code
+// conflict, then Data is also set to current state of entity.
+type Response struct {
+	Data  any    `json:"data,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// rpcHandler unmarshals data of request and returns data of response
+type rpcHandler func(ctx context.Context, data []byte) (any, error)
+
+// API serves request-reply subjects with the same use cases as HTTP API.
+// Subjects are prefixed, e.g. "rpc.v1.client.add".
+type API struct {
+	conn   *nats_server.Connection
+	prefix string
+	queue  string
+
+	routes map[string]rpcHandler
+	subs   []*nats.Subscription
+}
+
+func NewAPI(
+	conn *nats_server.Connection,
+	prefix, queue string,
+	client usecase.Client,
+	mailing usecase.Mailing,
+) *API {
+	a := &API{
+		conn:   conn,
+		prefix: prefix,
+		queue:  queue,
+		routes: make(map[string]rpcHandler),
+	}
+
+	prometheus.MustRegister(RequestsTotalCounter)
+
+	// Routers
+	{
+		newClientRPC(a, client)
+		newMailingRPC(a, mailing)
+	}
+
+	return a
+}
+
+func (a *API) handle(subj string, h rpcHandler) {
+	a.routes[a.prefix+"."+subj] = h
+}
+
+// Start subscribes to all the subjects. Instances with the same
+// queue share requests.
+func (a *API) Start() error {
+	for subj, h := range a.routes {
+		sub, err := a.conn.QueueSubscribe(subj, a.queue, a.serve(h))
+		if err != nil {
+			return err
+		}
+		a.subs = append(a.subs, sub)
+	}
+
+	return nil
+}
+
+// Shutdown stops accepting requests, requests being served are finished.
+func (a *API) Shutdown() error {
+	var errs []error
+	for _, sub := range a.subs {
+		errs = append(errs, sub.Drain())
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *API) serve(h rpcHandler) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		requestID := msg.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = nuid.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), _requestTimeout)
+		defer cancel()
+
+		// Trace context and tenant are carried to published messages
+		if trace := msg.Header.Get(broker.TraceHeader); trace != "" {
+			ctx = broker.WithTrace(ctx, trace)
+		}
+		if tenant := msg.Header.Get(broker.TenantHeader); tenant != "" {
+			ctx = broker.WithTenant(ctx, tenant)
+		}
+
+		// Actor and request ID are written to audit log of changes
+		ctx = usecase.WithRequestID(ctx, requestID)
+		ctx = usecase.WithActor(ctx, msg.Header.Get(ActorHeader))
+
+		var resp Response
+		var req Request
+		err := json.Unmarshal(msg.Data, &req)
+		if err != nil {
+			resp.Error = badRequest(err)
+		} else if data, hErr := h(ctx, req.Data); hErr != nil {
+			err = hErr
+			resp.Error = asError(err)
+			var conflict *entity.ConflictError
+			if errors.As(err, &conflict) {
+				resp.Data = conflict.Current
+			}
+		} else {
+			resp.Data = data
+		}
+
+		code := "ok"
+		if resp.Error != nil {
+			code = resp.Error.Code
+			slog.Info("RPC request failed",
+				slog.String("Subject", msg.Subject),
+				slog.String("RequestID", requestID),
+				slog.String("ErrorMsg", err.Error()))
+		}
+		pushMetric(msg.Subject, code)
+
+		body, err := json.Marshal(resp)
+		if err != nil {
+			slog.Error("RPC response marshal error",
+				slog.String("Subject", msg.Subject),
+				slog.String("ErrorMsg", err.Error()))
+			return
+		}
+
+		reply := nats.NewMsg(msg.Reply)
+		reply.Data = body
+		reply.Header.Set(RequestIDHeader, requestID)
+		if err = msg.RespondMsg(reply); err != nil {
+			slog.Error("RPC respond error",
+				slog.String("Subject", msg.Subject),
+				slog.String("ErrorMsg", err.Error()))
+		}
+	}
+}
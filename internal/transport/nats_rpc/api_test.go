@@ -0,0 +1,146 @@
+package nats_rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/nats-io/nats.go"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/transport/nats_rpc"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server"
+)
+
+// Mailings of fakeMailing by ID
+const (
+	missingID  = 404
+	brokenID   = 500
+	conflictID = 409
+)
+
+// fakeClient isn't used by tests, its methods aren't called
+type fakeClient struct {
+	usecase.Client
+}
+
+// fakeMailing answers by ID of mailing
+type fakeMailing struct {
+	usecase.Mailing
+}
+
+func (fakeMailing) Get(_ context.Context, m *entity.Mailing) (*entity.Mailing, error) {
+	switch m.ID {
+	case missingID:
+		return nil, fmt.Errorf("MailingUseCase - Get(): %w", entity.ErrNotFound)
+	case brokenID:
+		return nil, errors.New(`ERROR: relation "mailing" does not exist (SQLSTATE 42P01)`)
+	}
+
+	return &entity.Mailing{ID: m.ID, MessageText: "Hi", Version: 3}, nil
+}
+
+func (fakeMailing) Add(_ context.Context, m *entity.Mailing) error {
+	m.ID, m.Version = 7, 1
+	return nil
+}
+
+func (fakeMailing) Patch(_ context.Context, m *entity.Mailing) error {
+	if m.ID == conflictID {
+		return &entity.ConflictError{Version: 5, Current: &entity.Mailing{ID: m.ID, Version: 5}}
+	}
+	m.Version++
+
+	return nil
+}
+
+// response is Response with raw data
+type response struct {
+	Data  json.RawMessage `json:"data"`
+	Error *nats_rpc.Error `json:"error"`
+}
+
+func request(t *testing.T, conn *nats_server.Connection, subj, data, requestID string) (*response, string) {
+	t.Helper()
+
+	msg := nats.NewMsg("rpc.test." + subj)
+	msg.Data = []byte(data)
+	if requestID != "" {
+		msg.Header.Set(nats_rpc.RequestIDHeader, requestID)
+	}
+
+	reply, err := conn.RequestMsg(msg, 5*time.Second)
+	assert.Equal(t, err, nil)
+
+	var resp response
+	assert.Equal(t, json.Unmarshal(reply.Data, &resp), nil)
+
+	return &resp, reply.Header.Get(nats_rpc.RequestIDHeader)
+}
+
+func TestAPI(t *testing.T) {
+	ns, err := nats_server.StartEmbedded(nats_server.EmbeddedConfig{ServerName: "test", StoreDir: t.TempDir()})
+	assert.Equal(t, err, nil)
+	defer ns.Close()
+
+	conn, err := nats_server.OpenConnection(nats_server.Config{Embedded: ns})
+	assert.Equal(t, err, nil)
+	defer conn.Close(context.Background())
+
+	api := nats_rpc.NewAPI(conn, "rpc.test", "api", fakeClient{}, fakeMailing{})
+	assert.Equal(t, api.Start(), nil)
+	defer api.Shutdown()
+
+	// test 1: data of request is answered with data of use case, request ID is kept
+	{
+		resp, requestID := request(t, conn, "mailing.get", `{"data": {"id": 1}}`, "req-1")
+		assert.Equal(t, resp.Error, (*nats_rpc.Error)(nil))
+		assert.Equal(t, requestID, "req-1")
+
+		var m entity.Mailing
+		assert.Equal(t, json.Unmarshal(resp.Data, &m), nil)
+		assert.Equal(t, m.MessageText, "Hi")
+		assert.Equal(t, m.Version, int64(3))
+	}
+
+	// test 2: created mailing is answered with its ID and version, request ID is generated
+	{
+		resp, requestID := request(t, conn, "mailing.add", `{"data": {"message_text": "Hi"}}`, "")
+		assert.Equal(t, resp.Error, (*nats_rpc.Error)(nil))
+		assert.NotEqual(t, requestID, "")
+
+		var m entity.Mailing
+		assert.Equal(t, json.Unmarshal(resp.Data, &m), nil)
+		assert.Equal(t, m.ID, int64(7))
+		assert.Equal(t, m.Version, int64(1))
+	}
+
+	// test 3: broken envelope is bad request
+	{
+		resp, _ := request(t, conn, "mailing.get", `{"data": `, "")
+		assert.Equal(t, resp.Error.Code, nats_rpc.CodeBadRequest)
+	}
+
+	// test 4: errors of use case are answered with fixed messages
+	{
+		resp, _ := request(t, conn, "mailing.get", fmt.Sprintf(`{"data": {"id": %d}}`, missingID), "")
+		assert.Equal(t, *resp.Error, nats_rpc.Error{Code: nats_rpc.CodeNotFound, Message: "Entity doesn't exist"})
+
+		resp, _ = request(t, conn, "mailing.get", fmt.Sprintf(`{"data": {"id": %d}}`, brokenID), "")
+		assert.Equal(t, *resp.Error, nats_rpc.Error{Code: nats_rpc.CodeInternal, Message: "Internal server error"})
+	}
+
+	// test 5: conflict is answered with current state of entity
+	{
+		resp, _ := request(t, conn, "mailing.patch", fmt.Sprintf(`{"data": {"id": %d, "version": 4}}`, conflictID), "")
+		assert.Equal(t, resp.Error.Code, nats_rpc.CodeConflict)
+
+		var m entity.Mailing
+		assert.Equal(t, json.Unmarshal(resp.Data, &m), nil)
+		assert.Equal(t, m.Version, int64(5))
+	}
+}
@@ -0,0 +1,78 @@
+package nats_rpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+type clientRPC struct {
+	c usecase.Client
+}
+
+func newClientRPC(a *API, c usecase.Client) {
+	r := &clientRPC{c}
+
+	a.handle("client.get", r.Get)
+	a.handle("client.add", r.Add)
+	a.handle("client.patch", r.Patch)
+	a.handle("client.delete", r.Delete)
+	a.handle("client.restore", r.Restore)
+}
+
+func (r *clientRPC) Get(ctx context.Context, data []byte) (any, error) {
+	var client entity.Client
+	if err := json.Unmarshal(data, &client); err != nil {
+		return nil, badRequest(err)
+	}
+
+	return r.c.Get(ctx, &client)
+}
+
+// Add returns client with its ID and version
+func (r *clientRPC) Add(ctx context.Context, data []byte) (any, error) {
+	var client entity.Client
+	if err := json.Unmarshal(data, &client); err != nil {
+		return nil, badRequest(err)
+	}
+
+	if err := r.c.Add(ctx, &client); err != nil {
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// Patch returns client with its new version
+func (r *clientRPC) Patch(ctx context.Context, data []byte) (any, error) {
+	var client entity.Client
+	if err := json.Unmarshal(data, &client); err != nil {
+		return nil, badRequest(err)
+	}
+
+	if err := r.c.Patch(ctx, &client); err != nil {
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+func (r *clientRPC) Delete(ctx context.Context, data []byte) (any, error) {
+	var client entity.Client
+	if err := json.Unmarshal(data, &client); err != nil {
+		return nil, badRequest(err)
+	}
+
+	return nil, r.c.Delete(ctx, &client)
+}
+
+func (r *clientRPC) Restore(ctx context.Context, data []byte) (any, error) {
+	var client entity.Client
+	if err := json.Unmarshal(data, &client); err != nil {
+		return nil, badRequest(err)
+	}
+
+	return nil, r.c.Restore(ctx, &client)
+}
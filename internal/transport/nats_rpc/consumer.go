@@ -0,0 +1,212 @@
+package nats_rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server/mod"
+)
+
+const (
+	// leaseRetryDelay is delay of redelivery for mailing owned by another worker
+	leaseRetryDelay = 30 * time.Second
+
+	// upgradeRetryDelay is delay of redelivery for payload of newer version,
+	// it's left for upgraded instances during rolling upgrade
+	upgradeRetryDelay = time.Minute
+)
+
+type mailingConsumer struct {
+	c usecase.Consumer
+
+	// payloads are handlers by payload type
+	payloads map[string]mod.MsgTimeHandler
+}
+
+func newMailingConsumer(r map[string]mod.HandlerGroup, c usecase.Consumer, subjects ...string) {
+	m := &mailingConsumer{c: c}
+	m.payloads = map[string]mod.MsgTimeHandler{
+		entity.PayloadMailing:            m.sendGroup(),
+		entity.PayloadMailingWithClients: m.sendPool(),
+	}
+
+	if len(subjects) < 2 {
+		panic("not enough subjects to start nats router")
+	}
+	{
+		r[subjects[0]] = mod.HandlerGroup{Get: m.dispatch(entity.PayloadMailing), Clean: m.clean()}
+		r[subjects[1]] = mod.HandlerGroup{Get: m.dispatch(entity.PayloadMailingWithClients), Clean: m.clean()}
+	}
+}
+
+// dispatch passes message to handler of its payload type and version.
+// Messages without envelope are published before upgrade, their type
+// is legacy one of subject.
+func (m *mailingConsumer) dispatch(legacy string) mod.MsgTimeHandler {
+	return func(ctx context.Context, msg broker.Msg) (bool, mod.Task) {
+		env, err := broker.ParseEnvelope(msg.Header())
+		if err != nil {
+			slog.Error("Envelope parsing error",
+				slog.String("Subject", msg.Subject()),
+				slog.String("ErrorMsg", err.Error()))
+			m.clean()(msg)
+			return true, mod.Task{}
+		}
+		if env.Type == "" {
+			env.Type = legacy
+		}
+
+		handler, ok := m.payloads[env.Type]
+		if !ok {
+			slog.Error("Unknown payload type",
+				slog.String("Subject", msg.Subject()),
+				slog.String("MsgID", env.ID),
+				slog.String("Type", env.Type))
+			m.clean()(msg)
+			return true, mod.Task{}
+		}
+
+		if env.Version > entity.PayloadVersion {
+			slog.Warn("Unsupported payload version",
+				slog.String("Subject", msg.Subject()),
+				slog.String("MsgID", env.ID),
+				slog.String("Type", env.Type),
+				slog.Int("Version", env.Version))
+			if err = msg.NakWithDelay(upgradeRetryDelay); err != nil {
+				slog.Error("Internal unexpected error",
+					slog.String("Subject", msg.Subject()),
+					slog.String("ErrorMsg", err.Error()))
+			}
+			return true, mod.Task{}
+		}
+
+		return handler(env.Context(ctx), msg)
+	}
+}
+
+func (m *mailingConsumer) sendGroup() mod.MsgTimeHandler {
+	return func(ctx context.Context, msg broker.Msg) (bool, mod.Task) {
+		var rtask mod.Task
+
+		// Unmarshalling
+		var mailing entity.Mailing
+		err := mailing.UnmarshalJSON(msg.Data())
+		if err != nil {
+			slog.Error("Mailing unmarshal error",
+				slog.String("Subject", msg.Subject()),
+				slog.String("ErrorMsg", err.Error()))
+			m.clean()(msg)
+			return true, rtask
+		}
+
+		// Delay
+		rtask.SetIn(mailing.DateTimeStart, mailing.DateTimeEnd)
+		if rtask.In() != 0 {
+			rtask.Msg = msg
+			return false, rtask
+		}
+
+		// Consumption
+		s, err := m.c.ConsumeGroup(ctx, &mailing)
+		if errors.Is(err, usecase.ErrMailingDeleted) {
+			err = msg.Term()
+		} else if errors.Is(err, usecase.ErrMailingLeased) {
+			err = msg.NakWithDelay(leaseRetryDelay)
+		} else if err != nil {
+			err = msg.Nak()
+		} else {
+			err = msg.Ack()
+		}
+
+		if err != nil {
+			slog.Error("Internal unexpected error",
+				slog.String("Subject", msg.Subject()),
+				slog.String("ErrorMsg", err.Error()))
+			return true, rtask
+		}
+
+		if s != nil {
+			logStats(msg.Subject(), "Mailing stats", s)
+		}
+		return true, rtask
+	}
+}
+
+func (m *mailingConsumer) sendPool() mod.MsgTimeHandler {
+	return func(ctx context.Context, msg broker.Msg) (bool, mod.Task) {
+		var rtask mod.Task
+
+		// Unmarshalling
+		var mwc entity.MailingWithClients
+		err := mwc.UnmarshalJSON(msg.Data())
+		if err != nil {
+			slog.Error("Mailing unmarshal error",
+				slog.String("Subject", msg.Subject()),
+				slog.String("ErrorMsg", err.Error()))
+			m.clean()(msg)
+			return true, rtask
+		}
+
+		// Delay, deferred clients wait for their interval
+		start := mwc.Mailing.DateTimeStart
+		if mwc.NotBefore.After(start) {
+			start = mwc.NotBefore
+		}
+		rtask.SetIn(start, mwc.Mailing.DateTimeEnd)
+		if rtask.In() != 0 {
+			rtask.Msg = msg
+			return false, rtask
+		}
+
+		// Consumption
+		s, err := m.c.ConsumePool(ctx, &mwc)
+		if errors.Is(err, usecase.ErrMailingDeleted) {
+			err = msg.Term()
+		} else if errors.Is(err, usecase.ErrMailingLeased) {
+			err = msg.NakWithDelay(leaseRetryDelay)
+		} else if err != nil {
+			err = msg.Nak()
+		} else {
+			err = msg.Ack()
+		}
+
+		if err != nil {
+			slog.Error("Internal unexpected error",
+				slog.String("Subject", msg.Subject()),
+				slog.String("ErrorMsg", err.Error()))
+			return true, rtask
+		}
+
+		if s != nil {
+			logStats(msg.Subject(), "Intermediate stats", s)
+		}
+		return true, rtask
+	}
+}
+
+func (m *mailingConsumer) clean() mod.MsgTermHandler {
+	return func(msg broker.Msg) {
+		// Handled messages are already acknowledged
+		if err := msg.Term(); err != nil && !errors.Is(err, broker.ErrAcked) {
+			slog.Error("mailingConsumer - clean()", slog.String("ErrorMsg", err.Error()))
+		}
+	}
+}
+
+func logStats(subject string, group string, s *entity.MailingStats) {
+	slog.Info("Messages sended",
+		slog.String("Subject", subject),
+		slog.Group(group,
+			slog.Int64("MailingID", s.MailingID),
+			slog.Time("DateTimeStart", s.DateTimeStart),
+			slog.Time("DateTimeEnd", s.DateTimeEnd),
+			slog.Int("Succesed", s.Succesed),
+			slog.Int("Failed", s.Failed),
+		),
+	)
+}
@@ -0,0 +1,56 @@
+package nats_rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// Error codes of RPC responses
+const (
+	CodeBadRequest = "bad_request"
+	CodeInternal   = "internal"
+	CodeTimeout    = "timeout"
+	CodeNotFound   = "not_found"
+	CodeConflict   = "conflict"
+)
+
+// Error is typed error of RPC response
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func badRequest(err error) *Error {
+	return &Error{Code: CodeBadRequest, Message: err.Error()}
+}
+
+// asError converts handler error to RPC one. Errors of use cases are
+// answered with fixed messages, their text is only logged.
+func asError(err error) *Error {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Code: CodeTimeout, Message: "Request timed out"}
+	case errors.Is(err, entity.ErrInvalidFilter):
+		return &Error{Code: CodeBadRequest, Message: "Invalid filter"}
+	case errors.Is(err, entity.ErrVersionRequired):
+		return &Error{Code: CodeBadRequest, Message: "Version is required"}
+	case errors.Is(err, entity.ErrNotFound):
+		return &Error{Code: CodeNotFound, Message: "Entity doesn't exist"}
+	case errors.Is(err, entity.ErrConflict):
+		return &Error{Code: CodeConflict, Message: "Version is stale"}
+	}
+
+	return &Error{Code: CodeInternal, Message: "Internal server error"}
+}
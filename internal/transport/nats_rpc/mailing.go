@@ -2,136 +2,155 @@ package nats_rpc
 
 import (
 	"context"
-	"errors"
-	"log/slog"
+	"encoding/json"
+	"time"
 
-	"github.com/nats-io/nats.go"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
-	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server/mod"
 )
 
-type mailingConsumer struct {
-	c usecase.Consumer
+type mailingRPC struct {
+	m usecase.Mailing
+}
 
-	ctx context.Context
+func newMailingRPC(a *API, m usecase.Mailing) {
+	r := &mailingRPC{m}
+
+	a.handle("mailing.get", r.Get)
+	a.handle("mailing.add", r.Add)
+	a.handle("mailing.patch", r.Patch)
+	a.handle("mailing.delete", r.Delete)
+	a.handle("mailing.restore", r.Restore)
+	a.handle("mailing.archive", r.Archive)
+	a.handle("mailing.list", r.List)
+	a.handle("mailing.stats", r.GetStats)
+	a.handle("mailing.series", r.GetSeries)
+	a.handle("mailing.messages", r.ReadMessages)
 }
 
-func newMailingConsumer(r map[string]mod.HandlerGroup, c usecase.Consumer, subjects ...string) {
-	m := &mailingConsumer{
-		c:   c,
-		ctx: context.Background(),
+func (r *mailingRPC) Get(ctx context.Context, data []byte) (any, error) {
+	var mailing entity.Mailing
+	if err := json.Unmarshal(data, &mailing); err != nil {
+		return nil, badRequest(err)
 	}
 
-	if len(subjects) < 2 {
-		panic("not enough subjects to start nats router")
+	return r.m.Get(ctx, &mailing)
+}
+
+// Add returns mailing with its ID and version
+func (r *mailingRPC) Add(ctx context.Context, data []byte) (any, error) {
+	var mailing entity.Mailing
+	if err := json.Unmarshal(data, &mailing); err != nil {
+		return nil, badRequest(err)
 	}
-	{
-		r[subjects[0]] = mod.HandlerGroup{Get: m.sendGroup(), Clean: m.clean()}
-		r[subjects[1]] = mod.HandlerGroup{Get: m.sendPool(), Clean: m.clean()}
+
+	if err := r.m.Add(ctx, &mailing); err != nil {
+		return nil, err
 	}
+
+	return &mailing, nil
 }
 
-func (m *mailingConsumer) sendGroup() mod.MsgTimeHandler {
-	return func(msg *nats.Msg) (bool, mod.Task) {
-		var rtask mod.Task
-
-		// Unmarshalling
-		var mailing entity.Mailing
-		err := mailing.UnmarshalJSON(msg.Data)
-		if err != nil {
-			slog.Error("Mailing unmarshal error",
-				slog.String("Subject", msg.Subject),
-				slog.String("ErrorMsg", err.Error()))
-			m.clean()(msg)
-			return true, rtask
-		}
+// Patch returns mailing with its new version
+func (r *mailingRPC) Patch(ctx context.Context, data []byte) (any, error) {
+	var mailing entity.Mailing
+	if err := json.Unmarshal(data, &mailing); err != nil {
+		return nil, badRequest(err)
+	}
 
-		// Delay
-		rtask.SetIn(mailing.DateTimeStart, mailing.DateTimeEnd)
-		if rtask.In() != 0 {
-			rtask.Msg = msg
-			return false, rtask
-		}
+	if err := r.m.Patch(ctx, &mailing); err != nil {
+		return nil, err
+	}
 
-		// Consumption
-		s, err := m.c.ConsumeGroup(m.ctx, &mailing)
-		if errors.Is(err, errors.New(usecase.DeletedError)) {
-			err = msg.Term()
-		} else if err != nil {
-			err = msg.Nak()
-		} else {
-			err = msg.Ack()
-		}
+	return &mailing, nil
+}
 
-		if err != nil {
-			slog.Error("Internal unexpected error",
-				slog.String("Subject", msg.Subject),
-				slog.String("ErrorMsg", err.Error()))
-		}
+func (r *mailingRPC) Delete(ctx context.Context, data []byte) (any, error) {
+	var mailing entity.Mailing
+	if err := json.Unmarshal(data, &mailing); err != nil {
+		return nil, badRequest(err)
+	}
 
-		slog.Info("Messages sended",
-			slog.String("Subject", msg.Subject),
-			slog.Group(
-				"Mailing stats",
-				s.MailingID, s.DateTimeStart, s.DateTimeEnd, s.Succesed, s.Failed),
-		)
-		return true, rtask
+	return nil, r.m.Delete(ctx, &mailing)
+}
+
+func (r *mailingRPC) Restore(ctx context.Context, data []byte) (any, error) {
+	var mailing entity.Mailing
+	if err := json.Unmarshal(data, &mailing); err != nil {
+		return nil, badRequest(err)
 	}
+
+	return nil, r.m.Restore(ctx, &mailing)
 }
 
-func (m *mailingConsumer) sendPool() mod.MsgTimeHandler {
-	return func(msg *nats.Msg) (bool, mod.Task) {
-		var rtask mod.Task
-
-		// Unmarshalling
-		var mwc entity.MailingWithClients
-		err := mwc.UnmarshalJSON(msg.Data)
-		if err != nil {
-			slog.Error("Mailing unmarshal error",
-				slog.String("Subject", msg.Subject),
-				slog.String("ErrorMsg", err.Error()))
-			m.clean()(msg)
-			return true, rtask
+// Archive reads optional bound of mailings end, it's now by default
+func (r *mailingRPC) Archive(ctx context.Context, data []byte) (any, error) {
+	var req struct {
+		Before time.Time `json:"before"`
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, badRequest(err)
 		}
+	}
+	if req.Before.IsZero() {
+		req.Before = time.Now()
+	}
 
-		// Delay
-		rtask.SetIn(mwc.Mailing.DateTimeStart, mwc.Mailing.DateTimeEnd)
-		if rtask.In() != 0 {
-			rtask.Msg = msg
-			return false, rtask
-		}
+	n, err := r.m.Archive(ctx, req.Before)
+	if err != nil {
+		return nil, err
+	}
 
-		// Consumption
-		s, err := m.c.ConsumePool(m.ctx, &mwc)
-		if errors.Is(err, errors.New(usecase.DeletedError)) {
-			err = msg.Term()
-		} else if err != nil {
-			err = msg.Nak()
-		} else {
-			err = msg.Ack()
+	return map[string]int{"archived": n}, nil
+}
+
+// List reads optional MailingFilter, the first page is returned without it
+func (r *mailingRPC) List(ctx context.Context, data []byte) (any, error) {
+	var filter entity.MailingFilter
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &filter); err != nil {
+			return nil, badRequest(err)
 		}
+	}
+
+	return r.m.GetMailings(ctx, &filter)
+}
 
-		if err != nil {
-			slog.Error("Internal unexpected error",
-				slog.String("Subject", msg.Subject),
-				slog.String("ErrorMsg", err.Error()))
+// GetStats reads optional MailingFilter, the first page is returned without it
+func (r *mailingRPC) GetStats(ctx context.Context, data []byte) (any, error) {
+	var filter entity.MailingFilter
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &filter); err != nil {
+			return nil, badRequest(err)
 		}
+	}
+
+	return r.m.GetMailingStats(ctx, &filter)
+}
 
-		slog.Info("Messages sended",
-			slog.String("Subject", msg.Subject),
-			slog.Group(
-				"Intermediate stats",
-				s.MailingID, s.DateTimeStart, s.DateTimeEnd, s.Succesed, s.Failed),
-		)
-		return true, rtask
+func (r *mailingRPC) GetSeries(ctx context.Context, data []byte) (any, error) {
+	var filter entity.SeriesFilter
+	if err := json.Unmarshal(data, &filter); err != nil {
+		return nil, badRequest(err)
 	}
+
+	return r.m.GetSeries(ctx, &filter)
 }
 
-func (m *mailingConsumer) clean() mod.MsgTermHandler {
-	return func(msg *nats.Msg) {
-		if err := msg.Term(); err != nil {
-			slog.Error("mailingConsumer - clean()", slog.String("ErrorMsg", err.Error()))
-		}
+// ReadMessages reads mailing and MessageFilter of its messages from the same object
+func (r *mailingRPC) ReadMessages(ctx context.Context, data []byte) (any, error) {
+	var (
+		mailing entity.Mailing
+		filter  entity.MessageFilter
+	)
+	if err := json.Unmarshal(data, &mailing); err != nil {
+		return nil, badRequest(err)
 	}
+	if err := json.Unmarshal(data, &filter); err != nil {
+		return nil, badRequest(err)
+	}
+	filter.MailingID = mailing.ID
+
+	return r.m.GetMessagesByMailing(ctx, &filter)
 }
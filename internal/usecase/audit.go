@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+type actorKey struct{}
+
+// WithActor returns ctx of request made by actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns actor of ctx, it's anonymous by default
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+
+	return entity.AnonymousActor
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns ctx of request with ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns ID of request of ctx, it's empty if it's unknown
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// record writes change of entity to audit log. It's called in transaction
+// of change, so change isn't made if it isn't recorded.
+func record(ctx context.Context, repo AuditRepo, name, action string, id int64, before, after any) error {
+	diff, err := entity.Diff(before, after)
+	if err != nil {
+		return err
+	}
+
+	return repo.Create(ctx, &entity.AuditRecord{
+		CreatedAt: time.Now().UTC(),
+		Actor:     ActorFrom(ctx),
+		Action:    action,
+		Entity:    name,
+		EntityID:  id,
+		Diff:      diff,
+		RequestID: RequestIDFrom(ctx),
+	})
+}
+
+type AuditUseCase struct {
+	repo AuditRepo
+}
+
+func NewAudit(repo AuditRepo) *AuditUseCase {
+	return &AuditUseCase{repo}
+}
+
+// GetRecords returns page of audit records by filter
+func (u *AuditUseCase) GetRecords(ctx context.Context, filter *entity.AuditFilter) (*entity.AuditPage, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("AuditUseCase - GetRecords(): %w", err)
+	}
+
+	records, next, err := u.repo.ReadPage(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("AuditUseCase - GetRecords(): %w", err)
+	}
+
+	if records == nil {
+		records = []*entity.AuditRecord{}
+	}
+
+	return &entity.AuditPage{Items: records, NextCursor: next}, nil
+}
@@ -2,42 +2,153 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
 )
 
 type ClientUseCase struct {
-	repo ClientRepo
+	repo   ClientRepo
+	audit  AuditRepo
+	events EventPublisher
+	tx     TxManager
 }
 
-func NewClient(repo ClientRepo) *ClientUseCase {
-	return &ClientUseCase{repo}
+func NewClient(repo ClientRepo, audit AuditRepo, events EventPublisher, tx TxManager) *ClientUseCase {
+	return &ClientUseCase{
+		repo:   repo,
+		audit:  audit,
+		events: events,
+		tx:     tx,
+	}
 }
 
 func (u *ClientUseCase) Add(ctx context.Context, client *entity.Client) error {
-	err := u.repo.Create(ctx, client)
+	err := u.tx.Do(ctx, func(ctx context.Context) error {
+		if err := u.repo.Create(ctx, client); err != nil {
+			return err
+		}
+
+		return record(ctx, u.audit, entity.AuditClient, entity.AuditCreate, client.ID, nil, client)
+	})
 	if err != nil {
 		return fmt.Errorf("ClientUseCase - Add(): %w", err)
 	}
 
+	emit(ctx, u.events, entity.EventClientCreated, eventKey(client.ID), client)
+
 	return nil
 }
 
+// Get returns client that isn't deleted
+func (u *ClientUseCase) Get(ctx context.Context, client *entity.Client) (*entity.Client, error) {
+	c, err := u.repo.Read(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("ClientUseCase - Get(): %w", err)
+	}
+
+	return c, nil
+}
+
+// Patch updates set fields of client of its version, version of client
+// is set to the new one. entity.ErrNotFound is returned if client doesn't
+// exist, *entity.ConflictError if it's changed since version.
 func (u *ClientUseCase) Patch(ctx context.Context, client *entity.Client) error {
-	err := u.repo.Update(ctx, client)
+	if client.Version == 0 {
+		return fmt.Errorf("ClientUseCase - Patch(): %w", entity.ErrVersionRequired)
+	}
+
+	err := u.tx.Do(ctx, func(ctx context.Context) error {
+		before, err := u.repo.Read(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		if err = u.repo.Update(ctx, client); errors.Is(err, entity.ErrConflict) {
+			return u.conflict(ctx, client)
+		} else if err != nil {
+			return err
+		}
+
+		after, err := u.repo.Read(ctx, client)
+		if err != nil {
+			return err
+		}
+		client.Version = after.Version
+
+		return record(ctx, u.audit, entity.AuditClient, entity.AuditUpdate, client.ID, before, after)
+	})
 	if err != nil {
 		return fmt.Errorf("ClientUseCase - Patch(): %w", err)
 	}
 
+	emit(ctx, u.events, entity.EventClientUpdated, eventKey(client.ID, client.Version), client)
+
 	return nil
 }
 
+// Delete marks client of its version deleted. entity.ErrNotFound is returned
+// if client doesn't exist or it's already deleted, *entity.ConflictError
+// if it's changed since version.
 func (u *ClientUseCase) Delete(ctx context.Context, client *entity.Client) error {
-	err := u.repo.Delete(ctx, client)
+	if client.Version == 0 {
+		return fmt.Errorf("ClientUseCase - Delete(): %w", entity.ErrVersionRequired)
+	}
+
+	err := u.tx.Do(ctx, func(ctx context.Context) error {
+		before, err := u.repo.Read(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		if err = u.repo.Delete(ctx, client); errors.Is(err, entity.ErrConflict) {
+			return u.conflict(ctx, client)
+		} else if err != nil {
+			return err
+		}
+
+		return record(ctx, u.audit, entity.AuditClient, entity.AuditDelete, client.ID, before, nil)
+	})
 	if err != nil {
 		return fmt.Errorf("ClientUseCase - Delete(): %w", err)
 	}
 
+	emit(ctx, u.events, entity.EventClientDeleted, eventKey(client.ID, client.Version), client)
+
+	return nil
+}
+
+func (u *ClientUseCase) Restore(ctx context.Context, client *entity.Client) error {
+	err := u.tx.Do(ctx, func(ctx context.Context) error {
+		if err := u.repo.Restore(ctx, client); err != nil {
+			return err
+		}
+
+		after, err := u.repo.Read(ctx, client)
+		if err != nil {
+			return err
+		}
+		client.Version = after.Version
+
+		return record(ctx, u.audit, entity.AuditClient, entity.AuditRestore, client.ID, nil, after)
+	})
+	if err != nil {
+		return fmt.Errorf("ClientUseCase - Restore(): %w", err)
+	}
+
+	emit(ctx, u.events, entity.EventClientRestored, eventKey(client.ID, client.Version), client)
+
 	return nil
 }
+
+// conflict returns current state of client changed by another request,
+// entity.ErrNotFound is returned if it's deleted
+func (u *ClientUseCase) conflict(ctx context.Context, client *entity.Client) error {
+	current, err := u.repo.Read(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	return &entity.ConflictError{Version: current.Version, Current: current}
+}
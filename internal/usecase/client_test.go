@@ -0,0 +1,95 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+func TestClientPatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockClientRepo(ctrl)
+	audit := NewMockAuditRepo(ctrl)
+	events := NewMockEventPublisher(ctrl)
+	u := usecase.NewClient(repo, audit, events, noTx{})
+
+	c := &entity.Client{ID: 1, Tag: "vip", Version: 1}
+	ctx := usecase.WithRequestID(usecase.WithActor(context.Background(), "alice"), "req-1")
+
+	// test 1: changed fields are recorded with actor and request of ctx
+	{
+		gomock.InOrder(
+			repo.EXPECT().Read(gomock.Any(), c).Return(&entity.Client{ID: 1, Tag: "gold", TimeZone: 12, Version: 1}, nil),
+			repo.EXPECT().Update(gomock.Any(), c).Return(nil),
+			repo.EXPECT().Read(gomock.Any(), c).Return(&entity.Client{ID: 1, Tag: "vip", TimeZone: 12, Version: 2}, nil),
+		)
+
+		var rec *entity.AuditRecord
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, r *entity.AuditRecord) error {
+				rec = r
+				return nil
+			})
+		events.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+		assert.Equal(t, u.Patch(ctx, c), nil)
+		assert.Equal(t, rec.Actor, "alice")
+		assert.Equal(t, rec.RequestID, "req-1")
+		assert.Equal(t, rec.Action, entity.AuditUpdate)
+		assert.Equal(t, rec.Entity, entity.AuditClient)
+		assert.Equal(t, len(rec.Diff), 2)
+		assert.Equal(t, string(rec.Diff["tag"].Before), `"gold"`)
+		assert.Equal(t, string(rec.Diff["tag"].After), `"vip"`)
+		assert.Equal(t, c.Version, int64(2))
+	}
+
+	// test 2: change of stale version is rejected with current state, change without version is
+	{
+		stale := &entity.Client{ID: 1, Tag: "silver", Version: 1}
+		current := &entity.Client{ID: 1, Tag: "vip", Version: 2}
+		gomock.InOrder(
+			repo.EXPECT().Read(gomock.Any(), stale).Return(current, nil),
+			repo.EXPECT().Update(gomock.Any(), stale).Return(entity.ErrConflict),
+			repo.EXPECT().Read(gomock.Any(), stale).Return(current, nil),
+		)
+
+		var conflict *entity.ConflictError
+		err := u.Patch(context.Background(), stale)
+		assert.Equal(t, errors.As(err, &conflict), true)
+		assert.Equal(t, conflict.Current, current)
+		assert.Equal(t, errors.Is(err, entity.ErrConflict), true)
+
+		err = u.Delete(context.Background(), &entity.Client{ID: 1})
+		assert.Equal(t, errors.Is(err, entity.ErrVersionRequired), true)
+	}
+
+	// test 3: client that doesn't exist isn't updated
+	{
+		repo.EXPECT().Read(gomock.Any(), c).Return(nil, entity.ErrNotFound)
+
+		err := u.Patch(context.Background(), c)
+		assert.Equal(t, errors.Is(err, entity.ErrNotFound), true)
+	}
+
+	// test 4: failure of recording is returned from transaction, so creation is rolled back
+	{
+		tx := NewMockTxManager(ctrl)
+		u := usecase.NewClient(repo, audit, events, tx)
+
+		errAudit := errors.New("audit down")
+		tx.EXPECT().Do(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, f func(context.Context) error) error {
+				return f(ctx)
+			})
+		repo.EXPECT().Create(gomock.Any(), c).Return(nil)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errAudit)
+
+		err := u.Add(context.Background(), c)
+		assert.Equal(t, errors.Is(err, errAudit), true)
+	}
+}
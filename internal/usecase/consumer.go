@@ -4,62 +4,108 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
 )
 
 const DeletedError string = "Entity deleted error"
 
+var (
+	ErrMailingDeleted = errors.New(DeletedError)
+
+	// ErrMailingLeased is returned when mailing is processed by another worker
+	ErrMailingLeased = errors.New("mailing is leased by another worker")
+)
+
+// _defaultChunkSize is number of clients in one chunk of mailing audience
+const _defaultChunkSize = 1000
+
 type ConsumerUseCase struct {
 	msg      MessageRepo
 	cli      ClientRepo
 	mail     MailingRepo
+	chunks   ChunkRepo
+	lease    LeaseRepo
 	sender   Sender
 	producer AdditionalProducer
+	events   EventPublisher
+
+	chunkSize int
 }
 
 func NewConsumer(
 	msgRepo MessageRepo,
 	cliRepo ClientRepo,
 	mailRepo MailingRepo,
+	chunkRepo ChunkRepo,
+	leaseRepo LeaseRepo,
 	sender Sender,
 	producer AdditionalProducer,
+	events EventPublisher,
+	chunkSize int,
 ) *ConsumerUseCase {
+	if chunkSize <= 0 {
+		chunkSize = _defaultChunkSize
+	}
+
 	return &ConsumerUseCase{
-		msg:      msgRepo,
-		cli:      cliRepo,
-		mail:     mailRepo,
-		sender:   sender,
-		producer: producer,
+		msg:       msgRepo,
+		cli:       cliRepo,
+		mail:      mailRepo,
+		chunks:    chunkRepo,
+		lease:     leaseRepo,
+		sender:    sender,
+		producer:  producer,
+		events:    events,
+		chunkSize: chunkSize,
 	}
 }
 
+// ConsumeGroup fans mailing audience out. Clients are read by chunks
+// and every chunk is published as MailingWithClients, so chunks are sent,
+// retried and parallelized independently by ConsumePool.
+//
+// Interrupted fan-out is resumed from the last created chunk.
 func (u *ConsumerUseCase) ConsumeGroup(ctx context.Context, mailing *entity.Mailing) (
 	*entity.MailingStats, error,
 ) {
-	var try = 0
+	release, err := u.acquire(ctx, mailing, 0)
+	if err != nil {
+		return nil, fmt.Errorf("ConsumerUseCase - ConsumeGroup() - acquire(): %w", err)
+	}
+	defer release()
 
-	err := u.checkMailing(ctx, mailing)
+	// Mailing of message could be changed since it was published
+	mailing, err = u.checkMailing(ctx, mailing)
 	if err != nil {
-		return nil, fmt.Errorf("ConsumerUseCase - ConsumeGroup() - checkMailing(): %w",
-			errors.New(DeletedError))
+		return nil, fmt.Errorf("ConsumerUseCase - ConsumeGroup() - checkMailing(): %w", err)
 	}
 
-	clients, err := u.cli.ReadByFilter(ctx, mailing)
+	chunks, err := u.chunks.ReadByMailing(ctx, mailing)
 	if err != nil {
 		return nil, fmt.Errorf("ConsumerUseCase - ConsumeGroup(): %w", err)
 	}
 
-	reserveClients := u.sendToClients(ctx, mailing, clients, try)
-	if len(reserveClients) > 0 {
-		err = u.publishReserved(ctx, &entity.MailingWithClients{
-			Mailing: mailing,
-			Clients: reserveClients,
-			Try:     try,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("ConsumerUseCase - ConsumeGroup() - publishReserved(): %w", err)
-		}
+	if len(chunks) == 0 {
+		emit(ctx, u.events, entity.EventMailingStarted, eventKey(mailing.ID), mailing)
+	}
+
+	total, err := u.fanOut(ctx, mailing, chunks)
+	if err != nil {
+		return nil, fmt.Errorf("ConsumerUseCase - ConsumeGroup() - fanOut(): %w", err)
+	}
+
+	err = u.chunks.Seal(ctx, mailing, total)
+	if err != nil {
+		return nil, fmt.Errorf("ConsumerUseCase - ConsumeGroup(): %w", err)
+	}
+
+	// Chunks could be done before fan-out is sealed
+	completed, err := u.chunks.Completed(ctx, mailing)
+	if err != nil {
+		return nil, fmt.Errorf("ConsumerUseCase - ConsumeGroup(): %w", err)
 	}
 
 	stats, err := u.mail.ReadWithMessages(ctx, mailing)
@@ -67,55 +113,215 @@ func (u *ConsumerUseCase) ConsumeGroup(ctx context.Context, mailing *entity.Mail
 		return nil, fmt.Errorf("ConsumerUseCase - ConsumeGroup(): %w", err)
 	}
 
+	u.emitProgress(ctx, mailing, 0, 0, stats, 0, completed)
+
 	return stats, nil
 }
 
+// ConsumePool sends mailing to clients of chunk. Clients that weren't
+// sent are published again with the same chunk, chunk is done otherwise.
 func (u *ConsumerUseCase) ConsumePool(ctx context.Context, mwc *entity.MailingWithClients) (
 	*entity.MailingStats, error,
 ) {
-	err := u.checkMailing(ctx, mwc.Mailing)
+	release, err := u.acquire(ctx, mwc.Mailing, mwc.Chunk)
 	if err != nil {
-		return nil, fmt.Errorf("ConsumerUseCase - ConsumePool() - checkMailing(): %w",
-			errors.New(DeletedError))
+		return nil, fmt.Errorf("ConsumerUseCase - ConsumePool() - acquire(): %w", err)
 	}
+	defer release()
 
-	clients, err := u.cli.ReadByFilter(ctx, mwc.Mailing)
+	// Clients are sent the current mailing, not the one of message
+	mailing, err := u.checkMailing(ctx, mwc.Mailing)
 	if err != nil {
-		return nil, fmt.Errorf("ConsumerUseCase - ConsumePool(): %w", err)
+		return nil, fmt.Errorf("ConsumerUseCase - ConsumePool() - checkMailing(): %w", err)
 	}
 
-	reserveClients := u.sendToClients(ctx, mwc.Mailing, clients, mwc.Try)
+	// Clients reserved before chunked fan-out don't have chunk
+	var chunk *entity.MailingChunk
+	if mwc.Chunk > 0 {
+		chunk, err = u.chunks.Read(ctx, &entity.MailingChunk{MailingID: mailing.ID, Seq: mwc.Chunk})
+		if err != nil {
+			return nil, fmt.Errorf("ConsumerUseCase - ConsumePool(): %w", err)
+		}
+
+		// Redelivered message of done chunk
+		if chunk.Status == entity.ChunkDone {
+			return nil, nil
+		}
+	}
+
+	reserveClients, notBefore := u.sendToClients(ctx, mailing, mwc.Clients, mwc.Try)
+
+	// Sending could be interrupted by shutdown, so checkpoint is made anyway
+	ctx = context.WithoutCancel(ctx)
+
+	completed := chunk == nil
 	if len(reserveClients) > 0 {
 		err = u.publishReserved(ctx, &entity.MailingWithClients{
-			Mailing: mwc.Mailing,
-			Clients: reserveClients,
-			Try:     mwc.Try + 1,
+			Mailing:   mailing,
+			Clients:   reserveClients,
+			Try:       mwc.Try + 1,
+			Chunk:     mwc.Chunk,
+			NotBefore: notBefore,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("ConsumerUseCase - ConsumePool() - publishReserved(): %w", err)
 		}
+	} else if chunk != nil {
+		chunk.Status = entity.ChunkDone
+		if err = u.chunks.SetStatus(ctx, chunk); err != nil {
+			return nil, fmt.Errorf("ConsumerUseCase - ConsumePool(): %w", err)
+		}
+
+		completed, err = u.chunks.Completed(ctx, mailing)
+		if err != nil {
+			return nil, fmt.Errorf("ConsumerUseCase - ConsumePool(): %w", err)
+		}
 	}
 
-	stats, err := u.mail.ReadWithMessages(ctx, mwc.Mailing)
+	stats, err := u.mail.ReadWithMessages(ctx, mailing)
 	if err != nil {
 		return nil, fmt.Errorf("ConsumerUseCase - ConsumePool(): %w", err)
 	}
 
+	u.emitProgress(ctx, mailing, mwc.Chunk, mwc.Try, stats, len(reserveClients), completed)
+
 	return stats, nil
 }
 
+// fanOut creates chunks of mailing audience with keyset pagination
+// over client IDs and publishes them. Chunks created by interrupted
+// fan-out are published if they weren't. It returns chunks count.
+func (u *ConsumerUseCase) fanOut(
+	ctx context.Context, mailing *entity.Mailing, chunks entity.MailingChunks,
+) (int, error) {
+	last := &entity.MailingChunk{MailingID: mailing.ID}
+	for _, chunk := range chunks {
+		if chunk.Status == entity.ChunkCreated {
+			if err := u.publishChunk(ctx, mailing, chunk, nil); err != nil {
+				return 0, err
+			}
+		}
+		last = chunk
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		clients, err := u.cli.ReadPage(ctx, mailing, last.LastID, u.chunkSize)
+		if err != nil {
+			return 0, err
+		}
+		if len(clients) == 0 {
+			break
+		}
+
+		chunk := &entity.MailingChunk{
+			MailingID: mailing.ID,
+			Seq:       last.Seq + 1,
+			AfterID:   last.LastID,
+			LastID:    clients[len(clients)-1].ID,
+			Status:    entity.ChunkCreated,
+		}
+		if err = u.chunks.Create(ctx, chunk); err != nil {
+			return 0, err
+		}
+		if err = u.publishChunk(ctx, mailing, chunk, clients); err != nil {
+			return 0, err
+		}
+		last = chunk
+
+		if len(clients) < u.chunkSize {
+			break
+		}
+	}
+
+	return last.Seq, nil
+}
+
+// publishChunk publishes clients of chunk and marks it published.
+// If clients is nil they're read within chunk bounds.
+func (u *ConsumerUseCase) publishChunk(
+	ctx context.Context, mailing *entity.Mailing, chunk *entity.MailingChunk, clients entity.Clients,
+) error {
+	if clients == nil {
+		var err error
+		if clients, err = u.chunkClients(ctx, mailing, chunk); err != nil {
+			return err
+		}
+	}
+
+	err := u.producer.Publish(ctx, &entity.MailingWithClients{
+		Mailing: mailing,
+		Clients: clients,
+		Chunk:   chunk.Seq,
+	})
+	if err != nil {
+		return err
+	}
+
+	chunk.Status = entity.ChunkPublished
+
+	return u.chunks.SetStatus(ctx, chunk)
+}
+
+// chunkClients reads audience of chunk within bounds saved at fan-out.
+// Audience could change since then, so it's read by pages until LastID
+// and never crosses bounds of the next chunk.
+func (u *ConsumerUseCase) chunkClients(
+	ctx context.Context, mailing *entity.Mailing, chunk *entity.MailingChunk,
+) (entity.Clients, error) {
+	var clients entity.Clients
+	for afterID := chunk.AfterID; afterID < chunk.LastID; {
+		page, err := u.cli.ReadPage(ctx, mailing, afterID, u.chunkSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, client := range page {
+			if client.ID > chunk.LastID {
+				return clients, nil
+			}
+			clients = append(clients, client)
+		}
+
+		if len(page) < u.chunkSize {
+			break
+		}
+		afterID = page[len(page)-1].ID
+	}
+
+	return clients, nil
+}
+
 // sendToClients tryes to Send() mailing to clients and create
-// new messages in DB for each
+// new messages in DB for each. Clients outside of mailing interval
+// are written as deferred messages once per try, clients whose
+// interval is passed aren't resent.
 //
-// sendToClients return aborted messages to resend it later
+// sendToClients return aborted messages to resend it later and time
+// of resending. It's zero if some of clients could be resent at once,
+// otherwise it's when interval of the first deferred client opens.
+// If ctx is cancelled all the clients left are returned as aborted.
 func (u *ConsumerUseCase) sendToClients(
 	ctx context.Context, mailing *entity.Mailing, clients entity.Clients, try int,
-) entity.Clients {
-	var reserveClients entity.Clients = make(entity.Clients, 0)
+) (entity.Clients, time.Time) {
+	var (
+		reserveClients entity.Clients = make(entity.Clients, 0)
+		notBefore      time.Time
+		resendNow      bool
+	)
 
-	for _, client := range clients {
+	for i, client := range clients {
 		var deliveryStatus bool = true
 
+		if ctx.Err() != nil {
+			reserveClients = append(reserveClients, clients[i:]...)
+			resendNow = true
+			break
+		}
+
 		if client.CheckTimeZone(mailing.IntervalStart, mailing.IntervalEnd) {
 			err := u.sender.Send(ctx, &entity.SendRequest{
 				ID:    client.ID,
@@ -125,6 +331,7 @@ func (u *ConsumerUseCase) sendToClients(
 			if err != nil {
 				deliveryStatus = false
 				reserveClients = append(reserveClients, client)
+				resendNow = true
 			}
 
 			msg := &entity.Message{
@@ -133,30 +340,97 @@ func (u *ConsumerUseCase) sendToClients(
 				MailingID:      mailing.ID,
 				ClientID:       client.ID,
 			}
-			_ = u.msg.Create(ctx, msg)
+			u.createMessage(ctx, msg)
+
+			key := eventKey(mailing.ID, client.ID, int64(try))
+			if deliveryStatus {
+				emit(ctx, u.events, entity.EventMessageSent, key, msg)
+			} else {
+				emit(ctx, u.events, entity.EventMessageFailed, key, msg)
+			}
 
 		} else {
-			reserveClients = append(reserveClients, client)
+			u.createMessage(ctx, &entity.Message{
+				Try:       try,
+				Deferred:  true,
+				MailingID: mailing.ID,
+				ClientID:  client.ID,
+			})
+
+			opens, _ := client.Window(mailing.IntervalStart, mailing.IntervalEnd)
+			if time.Now().Before(opens) {
+				reserveClients = append(reserveClients, client)
+				if notBefore.IsZero() || opens.Before(notBefore) {
+					notBefore = opens
+				}
+			}
 		}
 	}
 
-	return reserveClients
+	if resendNow {
+		notBefore = time.Time{}
+	}
+
+	return reserveClients, notBefore
 }
 
-// checkMailing return error if mailing was deleted.
-//
-// Also it revert mailing with updated attrs (if these were updated)
-func (u *ConsumerUseCase) checkMailing(ctx context.Context, mailing *entity.Mailing) error {
-	receivedM, err := u.mail.Read(ctx, mailing)
+// createMessage writes message of client. Client is already sent or
+// deferred, so failure is only logged.
+func (u *ConsumerUseCase) createMessage(ctx context.Context, msg *entity.Message) {
+	err := u.msg.Create(context.WithoutCancel(ctx), msg)
 	if err != nil {
-		return err
+		slog.Error("Message writing failed",
+			slog.Int64("MailingID", msg.MailingID),
+			slog.Int64("ClientID", msg.ClientID),
+			slog.Int("Try", msg.Try),
+			slog.String("ErrorMsg", err.Error()))
 	}
+}
 
-	if &receivedM != &mailing {
-		mailing = receivedM
+// acquire takes lease of mailing chunk, so only one worker sends it at a time.
+// ErrMailingLeased is returned if chunk is owned by another worker.
+func (u *ConsumerUseCase) acquire(ctx context.Context, mailing *entity.Mailing, chunk int) (func(), error) {
+	release, ok, err := u.lease.Acquire(ctx, mailing, chunk)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrMailingLeased
 	}
 
-	return nil
+	return release, nil
+}
+
+// checkMailing returns the current state of mailing, so changes made
+// after message was published are sent. ErrMailingDeleted is returned
+// if mailing was deleted, other errors of reading are returned as is,
+// so message is retried.
+func (u *ConsumerUseCase) checkMailing(ctx context.Context, mailing *entity.Mailing) (*entity.Mailing, error) {
+	current, err := u.mail.Read(ctx, mailing)
+	if errors.Is(err, entity.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrMailingDeleted, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if current.DeletedAt != nil {
+		return nil, ErrMailingDeleted
+	}
+
+	return current, nil
+}
+
+// emitProgress emits mailing.paused if some clients of chunk try are
+// deferred and mailing.completed if all the chunks of mailing are done.
+func (u *ConsumerUseCase) emitProgress(
+	ctx context.Context, mailing *entity.Mailing, chunk, try int, stats *entity.MailingStats,
+	reserved int, completed bool,
+) {
+	if reserved > 0 {
+		emit(ctx, u.events, entity.EventMailingPaused, eventKey(mailing.ID, int64(chunk), int64(try)), stats)
+	} else if completed {
+		emit(ctx, u.events, entity.EventMailingCompleted, eventKey(mailing.ID), stats)
+	}
 }
 
 // publishReserver produce mwc to Nats to resend aborted messages
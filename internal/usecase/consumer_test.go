@@ -0,0 +1,174 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+func TestConsumerCheckMailing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mailingRepo := NewMockMailingRepo(ctrl)
+	leaseRepo := NewMockLeaseRepo(ctrl)
+	u := usecase.NewConsumer(NewMockMessageRepo(ctrl), NewMockClientRepo(ctrl), mailingRepo, NewMockChunkRepo(ctrl),
+		leaseRepo, NewMockSender(ctrl), NewMockAdditionalProducer(ctrl), NewMockEventPublisher(ctrl), 1)
+
+	m := &entity.Mailing{ID: 1}
+	leaseRepo.EXPECT().Acquire(gomock.Any(), m, 0).Return(func() {}, true, nil).AnyTimes()
+
+	// test 1: missing mailing is deleted one, its message is terminated
+	{
+		mailingRepo.EXPECT().Read(gomock.Any(), m).Return(nil, entity.ErrNotFound)
+
+		_, err := u.ConsumeGroup(context.Background(), m)
+		assert.Equal(t, errors.Is(err, usecase.ErrMailingDeleted), true)
+	}
+
+	// test 2: failure of reading isn't deletion, so message is retried
+	{
+		mailingRepo.EXPECT().Read(gomock.Any(), m).Return(nil, errors.New("conn lost"))
+
+		_, err := u.ConsumeGroup(context.Background(), m)
+		assert.NotEqual(t, err, nil)
+		assert.Equal(t, errors.Is(err, usecase.ErrMailingDeleted), false)
+	}
+}
+
+func TestConsumerUpdatedMailing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mailingRepo := NewMockMailingRepo(ctrl)
+	messageRepo := NewMockMessageRepo(ctrl)
+	leaseRepo := NewMockLeaseRepo(ctrl)
+	sender := NewMockSender(ctrl)
+	events := NewMockEventPublisher(ctrl)
+	u := usecase.NewConsumer(messageRepo, NewMockClientRepo(ctrl), mailingRepo, NewMockChunkRepo(ctrl),
+		leaseRepo, sender, NewMockAdditionalProducer(ctrl), events, 1)
+
+	now := time.Now()
+	published := &entity.Mailing{ID: 1, MessageText: "old", IntervalStart: now.Add(-time.Hour), IntervalEnd: now.Add(time.Hour)}
+	updated := *published
+	updated.MessageText = "new"
+
+	leaseRepo.EXPECT().Acquire(gomock.Any(), published, 0).Return(func() {}, true, nil)
+	events.EXPECT().Publish(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+	// test 1: mailing changed after message was published is sent as it is now
+	{
+		mailingRepo.EXPECT().Read(gomock.Any(), published).Return(&updated, nil)
+		sender.EXPECT().Send(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *entity.SendRequest) error {
+				assert.Equal(t, req.Text, "new")
+				return nil
+			})
+		messageRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		mailingRepo.EXPECT().ReadWithMessages(gomock.Any(), &updated).Return(&entity.MailingStats{MailingID: 1}, nil)
+
+		_, err := u.ConsumePool(context.Background(), &entity.MailingWithClients{
+			Mailing: published,
+			Clients: entity.Clients{{ID: 1}},
+			Try:     1,
+		})
+		assert.Equal(t, err, nil)
+	}
+}
+
+func TestConsumerDeferred(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mailingRepo := NewMockMailingRepo(ctrl)
+	messageRepo := NewMockMessageRepo(ctrl)
+	leaseRepo := NewMockLeaseRepo(ctrl)
+	producer := NewMockAdditionalProducer(ctrl)
+	events := NewMockEventPublisher(ctrl)
+	u := usecase.NewConsumer(messageRepo, NewMockClientRepo(ctrl), mailingRepo, NewMockChunkRepo(ctrl),
+		leaseRepo, NewMockSender(ctrl), producer, events, 1)
+
+	now := time.Now()
+	m := &entity.Mailing{ID: 1, IntervalStart: now.Add(time.Hour), IntervalEnd: now.Add(2 * time.Hour)}
+	waiting := &entity.Client{ID: 1}
+	// Interval of UTC+3 is passed
+	missed := &entity.Client{ID: 2, TimeZone: 12}
+
+	leaseRepo.EXPECT().Acquire(gomock.Any(), m, 0).Return(func() {}, true, nil)
+	mailingRepo.EXPECT().Read(gomock.Any(), m).Return(m, nil)
+	mailingRepo.EXPECT().ReadWithMessages(gomock.Any(), m).Return(&entity.MailingStats{MailingID: 1}, nil)
+
+	// test 1: deferral is written once, waiting client is resent when its interval opens
+	{
+		events.EXPECT().Publish(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, e *entity.Event) error {
+				// ID of event doesn't change if chunk try is redelivered
+				assert.Equal(t, e.ID, "mailing.paused:1:0:1")
+				return nil
+			})
+		messageRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(2).
+			DoAndReturn(func(_ context.Context, msg *entity.Message) error {
+				assert.Equal(t, msg.Deferred, true)
+				return errors.New("conn lost")
+			})
+		producer.EXPECT().Publish(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, mwc *entity.MailingWithClients) error {
+				assert.Equal(t, mwc.Clients, entity.Clients{waiting})
+				assert.Equal(t, mwc.Try, 2)
+				assert.Equal(t, mwc.NotBefore.Equal(m.IntervalStart), true)
+				return nil
+			})
+
+		_, err := u.ConsumePool(context.Background(), &entity.MailingWithClients{
+			Mailing: m,
+			Clients: entity.Clients{waiting, missed},
+			Try:     1,
+		})
+		assert.Equal(t, err, nil)
+	}
+}
+
+func TestConsumerChunkBounds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mailingRepo := NewMockMailingRepo(ctrl)
+	clientRepo := NewMockClientRepo(ctrl)
+	chunkRepo := NewMockChunkRepo(ctrl)
+	leaseRepo := NewMockLeaseRepo(ctrl)
+	producer := NewMockAdditionalProducer(ctrl)
+	events := NewMockEventPublisher(ctrl)
+	u := usecase.NewConsumer(NewMockMessageRepo(ctrl), clientRepo, mailingRepo, chunkRepo,
+		leaseRepo, NewMockSender(ctrl), producer, events, 2)
+
+	m := &entity.Mailing{ID: 1}
+	chunk := &entity.MailingChunk{MailingID: 1, Seq: 1, AfterID: 0, LastID: 10, Status: entity.ChunkCreated}
+
+	leaseRepo.EXPECT().Acquire(gomock.Any(), m, 0).Return(func() {}, true, nil)
+	mailingRepo.EXPECT().Read(gomock.Any(), m).Return(m, nil)
+	mailingRepo.EXPECT().ReadWithMessages(gomock.Any(), m).Return(&entity.MailingStats{MailingID: 1}, nil)
+	events.EXPECT().Publish(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+	// test 1: chunk created by interrupted fan-out is read within its bounds even if it has more clients now
+	{
+		chunkRepo.EXPECT().ReadByMailing(gomock.Any(), m).Return(entity.MailingChunks{chunk}, nil)
+		gomock.InOrder(
+			clientRepo.EXPECT().ReadPage(gomock.Any(), m, int64(0), 2).
+				Return(entity.Clients{{ID: 1}, {ID: 4}}, nil),
+			clientRepo.EXPECT().ReadPage(gomock.Any(), m, int64(4), 2).
+				Return(entity.Clients{{ID: 7}, {ID: 11}}, nil),
+			// Fan-out goes on after the last chunk
+			clientRepo.EXPECT().ReadPage(gomock.Any(), m, int64(10), 2).Return(nil, nil),
+		)
+		producer.EXPECT().Publish(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, mwc *entity.MailingWithClients) error {
+				assert.Equal(t, mwc.Clients, entity.Clients{{ID: 1}, {ID: 4}, {ID: 7}})
+				assert.Equal(t, mwc.Chunk, 1)
+				return nil
+			})
+		chunkRepo.EXPECT().SetStatus(gomock.Any(), chunk).Return(nil)
+		chunkRepo.EXPECT().Seal(gomock.Any(), m, 1).Return(nil)
+		chunkRepo.EXPECT().Completed(gomock.Any(), m).Return(false, nil)
+
+		_, err := u.ConsumeGroup(context.Background(), m)
+		assert.Equal(t, err, nil)
+	}
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// newEvent returns event which ID is made of its type and key, so event
+// emitted again by redelivered message has the same ID
+func newEvent(typ string, key string, data interface{}) *entity.Event {
+	return &entity.Event{
+		ID:         typ + ":" + key,
+		Type:       typ,
+		Version:    entity.EventVersion,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// eventKey joins IDs of change, e.g. ID and version of entity
+func eventKey(ids ...int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// emit publishes domain event of change identified by key. Change is already
+// made when event is emitted, so use case doesn't fail if event isn't published.
+func emit(ctx context.Context, p EventPublisher, typ string, key string, data interface{}) {
+	event := newEvent(typ, key, data)
+
+	err := p.Publish(context.WithoutCancel(ctx), event)
+	if err != nil {
+		slog.Warn("Event publishing failed",
+			slog.String("ID", event.ID),
+			slog.String("Type", event.Type),
+			slog.String("ErrorMsg", err.Error()))
+	}
+}
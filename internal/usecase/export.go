@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// ExportUseCase exports clients and messages as CSV, NDJSON or Parquet.
+// Rows are encoded while repository reads them, so export isn't buffered.
+// Async jobs are queued and run one by one by Run, their files are
+// written to dir. Jobs are kept pending in repository until they're run,
+// so Recover queues them again after restart.
+type ExportUseCase struct {
+	repo     ExportRepo
+	clients  ClientRepo
+	messages MessageRepo
+	dir      string
+
+	queue chan *entity.ExportJob
+	now   func() time.Time
+}
+
+// NewExport - queue bounds number of waiting jobs
+func NewExport(repo ExportRepo, clients ClientRepo, messages MessageRepo, dir string, queue int) *ExportUseCase {
+	return &ExportUseCase{
+		repo:     repo,
+		clients:  clients,
+		messages: messages,
+		dir:      dir,
+		queue:    make(chan *entity.ExportJob, queue),
+		now:      time.Now,
+	}
+}
+
+// Write streams rows of request to w and returns their number. Nothing is
+// written if request is invalid, output is cut if export fails later.
+func (u *ExportUseCase) Write(ctx context.Context, req *entity.ExportRequest, w io.Writer) (int64, error) {
+	if err := req.Validate(); err != nil {
+		return 0, fmt.Errorf("ExportUseCase - Write(): %w", err)
+	}
+
+	n, err := u.write(ctx, req, w)
+	if err != nil {
+		return n, fmt.Errorf("ExportUseCase - Write(): %w", err)
+	}
+
+	return n, nil
+}
+
+// Start queues job of request. entity.ErrExportBusy is returned if queue is full.
+func (u *ExportUseCase) Start(ctx context.Context, req *entity.ExportRequest) (*entity.ExportJob, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("ExportUseCase - Start(): %w", err)
+	}
+
+	job := &entity.ExportJob{
+		Request:   *req,
+		Status:    entity.ExportPending,
+		CreatedAt: u.now().UTC(),
+	}
+	if err := u.repo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("ExportUseCase - Start(): %w", err)
+	}
+
+	select {
+	case u.queue <- job:
+	default:
+		u.finish(ctx, job, entity.ErrExportBusy)
+		return nil, fmt.Errorf("ExportUseCase - Start(): %w", entity.ErrExportBusy)
+	}
+
+	return job, nil
+}
+
+// Get returns job with its status
+func (u *ExportUseCase) Get(ctx context.Context, job *entity.ExportJob) (*entity.ExportJob, error) {
+	j, err := u.repo.Read(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("ExportUseCase - Get(): %w", err)
+	}
+
+	return j, nil
+}
+
+// File returns path of file of job received by Get, entity.ErrExportNotReady
+// is returned if job isn't done
+func (u *ExportUseCase) File(job *entity.ExportJob) (string, error) {
+	if job.Status != entity.ExportDone {
+		return "", fmt.Errorf("ExportUseCase - File(): job %d is %s: %w", job.ID, job.Status, entity.ErrExportNotReady)
+	}
+
+	return filepath.Join(u.dir, job.File), nil
+}
+
+// Recover queues jobs left pending by previous run and fails the ones
+// interrupted while running, it must be called before Start and Run.
+// Pending jobs which don't fit in queue are failed with entity.ErrExportBusy.
+func (u *ExportUseCase) Recover(ctx context.Context) error {
+	jobs, err := u.repo.ReadUnfinished(ctx)
+	if err != nil {
+		return fmt.Errorf("ExportUseCase - Recover(): %w", err)
+	}
+
+	for _, job := range jobs {
+		if job.Status == entity.ExportRunning {
+			u.finish(ctx, job, entity.ErrExportInterrupted)
+			continue
+		}
+
+		select {
+		case u.queue <- job:
+		default:
+			u.finish(ctx, job, entity.ErrExportBusy)
+		}
+	}
+
+	// Partial files of interrupted jobs
+	tmps, _ := filepath.Glob(filepath.Join(u.dir, "export.*.tmp"))
+	for _, tmp := range tmps {
+		os.Remove(tmp)
+	}
+
+	return nil
+}
+
+// Run runs queued jobs until ctx is done. Jobs left in queue stay pending
+// until Recover queues them again.
+func (u *ExportUseCase) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-u.queue:
+			if err := u.Export(ctx, job); err != nil {
+				slog.Error("Export failed",
+					slog.Int64("JobID", job.ID),
+					slog.String("ErrorMsg", err.Error()))
+			} else {
+				slog.Info("Export finished",
+					slog.Int64("JobID", job.ID),
+					slog.Int64("Rows", job.Rows),
+					slog.String("File", job.File))
+			}
+		}
+	}
+}
+
+// Export runs queued job. File is written under temporary name and
+// renamed when it's complete, so file of job is never partial.
+func (u *ExportUseCase) Export(ctx context.Context, job *entity.ExportJob) error {
+	job.Status = entity.ExportRunning
+	err := u.repo.Update(ctx, job)
+	if err == nil {
+		err = u.exportFile(ctx, job)
+	}
+
+	u.finish(context.WithoutCancel(ctx), job, err)
+	if err != nil {
+		return fmt.Errorf("ExportUseCase - Export(): %w", err)
+	}
+
+	return nil
+}
+
+// exportFile writes rows of job to file of dir and sets its name to job
+func (u *ExportUseCase) exportFile(ctx context.Context, job *entity.ExportJob) error {
+	if err := os.MkdirAll(u.dir, 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(u.dir, "export.*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	job.Rows, err = u.write(ctx, &job.Request, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("export_%d_%s", job.ID, job.Request.FileName())
+	if err = os.Rename(f.Name(), filepath.Join(u.dir, name)); err != nil {
+		return err
+	}
+	job.File = name
+
+	return nil
+}
+
+// finish sets final status of job, err fails it
+func (u *ExportUseCase) finish(ctx context.Context, job *entity.ExportJob, err error) {
+	finishedAt := u.now().UTC()
+	job.FinishedAt = &finishedAt
+	job.Status = entity.ExportDone
+	if err != nil {
+		job.Status = entity.ExportFailed
+		job.Error = err.Error()
+	}
+
+	if err = u.repo.Update(ctx, job); err != nil {
+		slog.Error("Export job update failed",
+			slog.Int64("JobID", job.ID),
+			slog.String("ErrorMsg", err.Error()))
+	}
+}
+
+// write encodes rows of valid request to w
+func (u *ExportUseCase) write(ctx context.Context, req *entity.ExportRequest, w io.Writer) (int64, error) {
+	if req.Kind == entity.ExportClients {
+		return exportRows(req, w, clientHeader, clientRecord, func(f func(*entity.Client) error) error {
+			return u.clients.Export(ctx, req.Audience(), f)
+		})
+	}
+
+	return exportRows(req, w, messageHeader, messageRecord, func(f func(*entity.Message) error) error {
+		return u.messages.Export(ctx, req.Messages(), f)
+	})
+}
+
+// exportRows encodes rows passed to f by export and returns their number
+func exportRows[T any](
+	req *entity.ExportRequest, w io.Writer, header []string, record func(*T) []string,
+	export func(f func(*T) error) error,
+) (int64, error) {
+	enc := exportEncoder(req, w, header, record)
+
+	var n int64
+	err := export(func(v *T) error {
+		n++
+		return enc.Encode(v)
+	})
+	if err != nil {
+		return n, err
+	}
+
+	return n, enc.Close()
+}
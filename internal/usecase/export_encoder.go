@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// exportRowGroup bounds rows of Parquet row group buffered in memory
+const exportRowGroup = 10000
+
+// encoder writes exported rows of one format
+type encoder[T any] interface {
+	Encode(*T) error
+	// Close flushes buffered rows, underlying writer isn't closed
+	Close() error
+}
+
+// newEncoder returns encoder of format, header and record are columns and
+// fields of CSV. Parquet columns are taken from parquet tags of T.
+func newEncoder[T any](format string, w io.Writer, header []string, record func(*T) []string) encoder[T] {
+	switch format {
+	case entity.ExportNDJSON:
+		return &ndjsonEncoder[T]{json.NewEncoder(w)}
+	case entity.ExportParquet:
+		return &parquetEncoder[T]{parquet.NewGenericWriter[T](w, parquet.MaxRowsPerRowGroup(exportRowGroup))}
+	}
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write(header)
+
+	return &csvEncoder[T]{w: cw, record: record}
+}
+
+type csvEncoder[T any] struct {
+	w      *csv.Writer
+	record func(*T) []string
+}
+
+func (e *csvEncoder[T]) Encode(v *T) error {
+	return e.w.Write(e.record(v))
+}
+
+func (e *csvEncoder[T]) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ndjsonEncoder writes JSON object per line
+type ndjsonEncoder[T any] struct {
+	e *json.Encoder
+}
+
+func (e *ndjsonEncoder[T]) Encode(v *T) error {
+	return e.e.Encode(v)
+}
+
+func (e *ndjsonEncoder[T]) Close() error {
+	return nil
+}
+
+// parquetEncoder writes row groups of exportRowGroup rows, footer is
+// written by Close
+type parquetEncoder[T any] struct {
+	w *parquet.GenericWriter[T]
+}
+
+func (e *parquetEncoder[T]) Encode(v *T) error {
+	_, err := e.w.Write([]T{*v})
+	return err
+}
+
+func (e *parquetEncoder[T]) Close() error {
+	return e.w.Close()
+}
+
+// gzipEncoder compresses output of encoder, gzip stream is closed after it
+type gzipEncoder[T any] struct {
+	encoder[T]
+	gz *gzip.Writer
+}
+
+func (e *gzipEncoder[T]) Close() error {
+	if err := e.encoder.Close(); err != nil {
+		return err
+	}
+
+	return e.gz.Close()
+}
+
+// exportEncoder returns encoder of request format and compression
+func exportEncoder[T any](req *entity.ExportRequest, w io.Writer, header []string, record func(*T) []string) encoder[T] {
+	if req.Compression != entity.ExportGzip {
+		return newEncoder(req.Format, w, header, record)
+	}
+
+	gz := gzip.NewWriter(w)
+	return &gzipEncoder[T]{encoder: newEncoder(req.Format, gz, header, record), gz: gz}
+}
+
+// CSV columns are named like JSON fields
+var (
+	clientHeader = []string{"id", "phone_number", "mobile_operator_code", "tag", "time_zone", "version"}
+
+	messageHeader = []string{
+		"id", "date_time_creation", "try", "delivery_status", "deferred", "mailing_id", "client_id",
+	}
+)
+
+func clientRecord(c *entity.Client) []string {
+	return []string{
+		strconv.FormatInt(c.ID, 10),
+		strconv.FormatInt(c.PhoneNumber, 10),
+		strconv.Itoa(c.MobileOperator),
+		c.Tag,
+		strconv.Itoa(c.TimeZone),
+		strconv.FormatInt(c.Version, 10),
+	}
+}
+
+func messageRecord(m *entity.Message) []string {
+	return []string{
+		strconv.FormatInt(m.ID, 10),
+		m.DateTimeCreation.UTC().Format(time.RFC3339Nano),
+		strconv.Itoa(m.Try),
+		strconv.FormatBool(m.DeliveryStatus),
+		strconv.FormatBool(m.Deferred),
+		strconv.FormatInt(m.MailingID, 10),
+		strconv.FormatInt(m.ClientID, 10),
+	}
+}
@@ -0,0 +1,222 @@
+package usecase_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/parquet-go/parquet-go"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+func TestExportWrite(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	clients := NewMockClientRepo(ctrl)
+	messages := NewMockMessageRepo(ctrl)
+	u := usecase.NewExport(nil, clients, messages, t.TempDir(), 1)
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ms := entity.Messages{
+		{ID: 1, DateTimeCreation: created, Try: 1, DeliveryStatus: true, MailingID: 7, ClientID: 3},
+		{ID: 2, DateTimeCreation: created, Try: 2, Deferred: true, MailingID: 7, ClientID: 4},
+	}
+	exportMessages := func(_ context.Context, _ *entity.MessageFilter, f func(*entity.Message) error) error {
+		for _, m := range ms {
+			if err := f(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// test 1: clients of audience are written as gzipped CSV
+	{
+		clients.EXPECT().Export(gomock.Any(), &entity.Mailing{FilterChoice: "tag", Tag: "vip"}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *entity.Mailing, f func(*entity.Client) error) error {
+				return f(&entity.Client{ID: 1, PhoneNumber: 79000000001, MobileOperator: 900, Tag: "vip", TimeZone: 3, Version: 2})
+			})
+
+		req := &entity.ExportRequest{Kind: entity.ExportClients, Compression: entity.ExportGzip, FilterChoice: "tag", Tag: "vip"}
+		var buf bytes.Buffer
+		n, err := u.Write(ctx, req, &buf)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, n, int64(1))
+		assert.Equal(t, req.FileName(), "clients.csv.gz")
+
+		gz, err := gzip.NewReader(&buf)
+		assert.Equal(t, err, nil)
+		data, err := io.ReadAll(gz)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, string(data), "id,phone_number,mobile_operator_code,tag,time_zone,version\n"+
+			"1,79000000001,900,vip,3,2\n")
+	}
+
+	// test 2: messages are written as NDJSON and Parquet
+	{
+		messages.EXPECT().Export(gomock.Any(), &entity.MessageFilter{MailingID: 7}, gomock.Any()).
+			Times(2).DoAndReturn(exportMessages)
+
+		var buf bytes.Buffer
+		n, err := u.Write(ctx, &entity.ExportRequest{Kind: entity.ExportMessages, Format: entity.ExportNDJSON, MailingID: 7}, &buf)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, n, int64(2))
+		assert.Equal(t, bytes.Count(buf.Bytes(), []byte("\n")), 2)
+
+		buf.Reset()
+		_, err = u.Write(ctx, &entity.ExportRequest{Kind: entity.ExportMessages, Format: entity.ExportParquet, MailingID: 7}, &buf)
+		assert.Equal(t, err, nil)
+
+		rows, err := parquet.Read[entity.Message](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(rows), 2)
+		assert.Equal(t, rows[1].Deferred, true)
+		assert.Equal(t, rows[1].DateTimeCreation.Equal(created), true)
+	}
+
+	// test 3: nothing is written for invalid request
+	{
+		var buf bytes.Buffer
+		_, err := u.Write(ctx, &entity.ExportRequest{Kind: entity.ExportMessages, Format: "xml", MailingID: 7}, &buf)
+		assert.Equal(t, errors.Is(err, entity.ErrInvalidExport), true)
+
+		_, err = u.Write(ctx, &entity.ExportRequest{Kind: entity.ExportMessages}, &buf)
+		assert.Equal(t, errors.Is(err, entity.ErrInvalidExport), true)
+		assert.Equal(t, buf.Len(), 0)
+	}
+}
+
+func TestExportJob(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	repo := NewMockExportRepo(ctrl)
+	clients := NewMockClientRepo(ctrl)
+	u := usecase.NewExport(repo, clients, nil, t.TempDir(), 1)
+
+	jobs := make(map[int64]entity.ExportJob)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, job *entity.ExportJob) error {
+			job.ID = int64(len(jobs) + 1)
+			jobs[job.ID] = *job
+			return nil
+		})
+	repo.EXPECT().Update(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, job *entity.ExportJob) error {
+			jobs[job.ID] = *job
+			return nil
+		})
+	repo.EXPECT().Read(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, job *entity.ExportJob) (*entity.ExportJob, error) {
+			j := jobs[job.ID]
+			return &j, nil
+		})
+
+	req := &entity.ExportRequest{Kind: entity.ExportClients, Format: entity.ExportNDJSON}
+
+	// test 1: file isn't ready until job is run, queue is bounded
+	job, err := u.Start(ctx, req)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, job.Status, entity.ExportPending)
+
+	_, err = u.File(job)
+	assert.Equal(t, errors.Is(err, entity.ErrExportNotReady), true)
+
+	_, err = u.Start(ctx, req)
+	assert.Equal(t, errors.Is(err, entity.ErrExportBusy), true)
+	assert.Equal(t, jobs[2].Status, entity.ExportFailed)
+
+	// test 2: file of job is written to directory
+	{
+		clients.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *entity.Mailing, f func(*entity.Client) error) error {
+				return f(&entity.Client{ID: 1, PhoneNumber: 79000000001, Tag: "gold"})
+			})
+
+		assert.Equal(t, u.Export(ctx, job), nil)
+		assert.Equal(t, job.Status, entity.ExportDone)
+		assert.Equal(t, job.Rows, int64(1))
+		assert.Equal(t, job.File, "export_1_clients.ndjson")
+
+		j, err := u.Get(ctx, job)
+		assert.Equal(t, err, nil)
+		path, err := u.File(j)
+		assert.Equal(t, err, nil)
+		data, err := os.ReadFile(path)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, bytes.HasPrefix(data, []byte(`{"id":1,"phone_number":79000000001`)), true)
+	}
+
+	// test 3: failed job leaves no file
+	{
+		job := &entity.ExportJob{ID: 3, Request: *req, Status: entity.ExportPending}
+		jobs[job.ID] = *job
+
+		clients.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("connection lost"))
+
+		assert.NotEqual(t, u.Export(ctx, job), nil)
+		assert.Equal(t, job.Status, entity.ExportFailed)
+		assert.Equal(t, job.File, "")
+
+		_, err := u.File(job)
+		assert.Equal(t, errors.Is(err, entity.ErrExportNotReady), true)
+	}
+}
+
+func TestExportRecover(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	repo := NewMockExportRepo(ctrl)
+	clients := NewMockClientRepo(ctrl)
+	dir := t.TempDir()
+	u := usecase.NewExport(repo, clients, nil, dir, 1)
+
+	req := entity.ExportRequest{Kind: entity.ExportClients, Format: entity.ExportNDJSON}
+	var (
+		running = &entity.ExportJob{ID: 1, Request: req, Status: entity.ExportRunning}
+		pending = &entity.ExportJob{ID: 2, Request: req, Status: entity.ExportPending}
+		extra   = &entity.ExportJob{ID: 3, Request: req, Status: entity.ExportPending}
+	)
+	assert.Equal(t, os.WriteFile(filepath.Join(dir, "export.1.tmp"), []byte("{}"), 0o644), nil)
+
+	// test 1: running job is failed with its partial file, pending one is queued, the one over queue size is failed
+	{
+		repo.EXPECT().ReadUnfinished(gomock.Any()).Return([]*entity.ExportJob{running, pending, extra}, nil)
+		repo.EXPECT().Update(gomock.Any(), running).Return(nil)
+		repo.EXPECT().Update(gomock.Any(), extra).Return(nil)
+
+		assert.Equal(t, u.Recover(ctx), nil)
+		assert.Equal(t, running.Status, entity.ExportFailed)
+		assert.Equal(t, running.Error, entity.ErrExportInterrupted.Error())
+		assert.Equal(t, pending.Status, entity.ExportPending)
+		assert.Equal(t, extra.Status, entity.ExportFailed)
+		assert.Equal(t, extra.Error, entity.ErrExportBusy.Error())
+
+		left, _ := filepath.Glob(filepath.Join(dir, "*"))
+		assert.Equal(t, len(left), 0)
+	}
+
+	// test 2: queued job is run
+	{
+		ctx, cancel := context.WithCancel(ctx)
+		clients.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		repo.EXPECT().Update(gomock.Any(), pending).Return(nil)
+		repo.EXPECT().Update(gomock.Any(), pending).
+			DoAndReturn(func(context.Context, *entity.ExportJob) error {
+				cancel()
+				return nil
+			})
+
+		u.Run(ctx)
+		assert.Equal(t, pending.Status, entity.ExportDone)
+		assert.Equal(t, pending.File, "export_2_clients.ndjson")
+	}
+}
@@ -30,7 +30,7 @@ func New() *Sender {
 	}
 }
 
-func (s *Sender) Send(_ context.Context, body *entity.SendRequest) error {
+func (s *Sender) Send(ctx context.Context, body *entity.SendRequest) error {
 	// Marshalling
 	reqBody, err := body.MarshalJSON()
 	if err != nil {
@@ -38,7 +38,7 @@ func (s *Sender) Send(_ context.Context, body *entity.SendRequest) error {
 	}
 
 	// Request building
-	req, err := http.NewRequest(http.MethodPost, msg_url(body.ID), bytes.NewReader(reqBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg_url(body.ID), bytes.NewReader(reqBody))
 	if err != nil {
 		return s.sendErr("Request building", err)
 	}
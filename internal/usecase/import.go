@@ -0,0 +1,400 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// reportPage is number of errors read at once by WriteReport
+const reportPage = 1000
+
+// ImportUseCase imports clients from uploaded files. Upload is saved to
+// dir and its job is queued, jobs are run one by one by Run. Valid rows are
+// upserted by batches and progress of job is saved after every batch.
+//
+// Jobs are kept pending in repository until they're run, so Recover
+// queues them again after restart.
+//
+// Imported clients aren't written to audit log one by one, every upserted
+// batch is recorded with actor and request which started job instead.
+type ImportUseCase struct {
+	repo      ImportRepo
+	clients   ClientRepo
+	audit     AuditRepo
+	tx        TxManager
+	dir       string
+	batch     int
+	maxErrors int
+
+	queue chan *entity.ImportJob
+	now   func() time.Time
+}
+
+// NewImport - batch is number of rows upserted at once, only maxErrors
+// invalid rows of job are reported. queue bounds number of waiting jobs.
+func NewImport(
+	repo ImportRepo, clients ClientRepo, audit AuditRepo, tx TxManager, dir string, batch, maxErrors, queue int,
+) *ImportUseCase {
+	return &ImportUseCase{
+		repo:      repo,
+		clients:   clients,
+		audit:     audit,
+		tx:        tx,
+		dir:       dir,
+		batch:     batch,
+		maxErrors: maxErrors,
+		queue:     make(chan *entity.ImportJob, queue),
+		now:       time.Now,
+	}
+}
+
+// Start saves upload of format and queues its job. entity.ErrImportBusy
+// is returned if queue is full.
+func (u *ImportUseCase) Start(ctx context.Context, format string, upload io.Reader) (*entity.ImportJob, error) {
+	if format != entity.ImportCSV && format != entity.ImportNDJSON {
+		return nil, fmt.Errorf("ImportUseCase - Start(): %w: format %q", entity.ErrInvalidImport, format)
+	}
+
+	tmp, err := u.save(upload)
+	if err != nil {
+		return nil, fmt.Errorf("ImportUseCase - Start() - save(): %w", err)
+	}
+
+	job := &entity.ImportJob{
+		Format:    format,
+		Status:    entity.ImportPending,
+		Actor:     ActorFrom(ctx),
+		RequestID: RequestIDFrom(ctx),
+		CreatedAt: u.now().UTC(),
+	}
+	if err = u.repo.Create(ctx, job); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("ImportUseCase - Start(): %w", err)
+	}
+
+	if err = os.Rename(tmp, u.path(job)); err != nil {
+		os.Remove(tmp)
+		u.finish(ctx, job, err)
+		return nil, fmt.Errorf("ImportUseCase - Start(): %w", err)
+	}
+
+	select {
+	case u.queue <- job:
+	default:
+		os.Remove(u.path(job))
+		u.finish(ctx, job, entity.ErrImportBusy)
+		return nil, fmt.Errorf("ImportUseCase - Start(): %w", entity.ErrImportBusy)
+	}
+
+	return job, nil
+}
+
+// Get returns job with its progress
+func (u *ImportUseCase) Get(ctx context.Context, job *entity.ImportJob) (*entity.ImportJob, error) {
+	j, err := u.repo.Read(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("ImportUseCase - Get(): %w", err)
+	}
+
+	return j, nil
+}
+
+// WriteReport writes invalid rows of job to w as CSV with header
+func (u *ImportUseCase) WriteReport(ctx context.Context, job *entity.ImportJob, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"line", "field", "value", "message"})
+
+	var afterID int64
+	for {
+		errs, err := u.repo.ReadErrors(ctx, job, afterID, reportPage)
+		if err != nil {
+			return fmt.Errorf("ImportUseCase - WriteReport(): %w", err)
+		}
+
+		for _, e := range errs {
+			_ = cw.Write([]string{strconv.FormatInt(e.Line, 10), e.Field, e.Value, e.Message})
+		}
+		cw.Flush()
+		if err = cw.Error(); err != nil {
+			return fmt.Errorf("ImportUseCase - WriteReport(): %w", err)
+		}
+
+		if len(errs) < reportPage {
+			return nil
+		}
+		afterID = errs[len(errs)-1].ID
+	}
+}
+
+// Recover queues jobs left pending by previous run and fails the ones
+// interrupted while running, it must be called before Start and Run.
+// Pending jobs which don't fit in queue are failed with entity.ErrImportBusy.
+func (u *ImportUseCase) Recover(ctx context.Context) error {
+	jobs, err := u.repo.ReadUnfinished(ctx)
+	if err != nil {
+		return fmt.Errorf("ImportUseCase - Recover(): %w", err)
+	}
+
+	for _, job := range jobs {
+		if job.Status == entity.ImportRunning {
+			os.Remove(u.path(job))
+			u.finish(ctx, job, entity.ErrImportInterrupted)
+			continue
+		}
+
+		select {
+		case u.queue <- job:
+		default:
+			os.Remove(u.path(job))
+			u.finish(ctx, job, entity.ErrImportBusy)
+		}
+	}
+
+	// Uploads saved before their jobs were created
+	tmps, _ := filepath.Glob(filepath.Join(u.dir, "upload.*.tmp"))
+	for _, tmp := range tmps {
+		os.Remove(tmp)
+	}
+
+	return nil
+}
+
+// Run runs queued jobs until ctx is done. Jobs left in queue stay pending
+// until Recover queues them again.
+func (u *ImportUseCase) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-u.queue:
+			if err := u.Import(ctx, job); err != nil {
+				slog.Error("Import failed",
+					slog.Int64("JobID", job.ID),
+					slog.String("ErrorMsg", err.Error()))
+			} else {
+				slog.Info("Import finished",
+					slog.Int64("JobID", job.ID),
+					slog.Int64("Imported", job.Imported),
+					slog.Int64("Failed", job.Failed))
+			}
+		}
+	}
+}
+
+// Import runs queued job, its upload is removed afterwards. Job is failed
+// if upload can't be read or rows can't be upserted, rows upserted before
+// are kept.
+func (u *ImportUseCase) Import(ctx context.Context, job *entity.ImportJob) error {
+	path := u.path(job)
+	defer os.Remove(path)
+
+	// Batches are audited as changes of request which started job
+	ctx = WithRequestID(WithActor(ctx, job.Actor), job.RequestID)
+
+	job.Status = entity.ImportRunning
+	err := u.repo.Update(ctx, job)
+	if err == nil {
+		err = u.importFile(ctx, job, path)
+	}
+
+	u.finish(context.WithoutCancel(ctx), job, err)
+	if err != nil {
+		return fmt.Errorf("ImportUseCase - Import(): %w", err)
+	}
+
+	return nil
+}
+
+// importFile upserts valid rows of file and saves invalid ones
+func (u *ImportUseCase) importFile(ctx context.Context, job *entity.ImportJob, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := newImportRows(job.Format, f)
+	if err != nil {
+		return err
+	}
+
+	var (
+		batch   entity.Clients
+		phones  = make(map[int64]int)
+		invalid []*entity.ImportRowError
+		saved   int
+	)
+	flush := func() error {
+		if len(invalid) > 0 {
+			if err := u.repo.CreateErrors(ctx, invalid); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) > 0 {
+			n, err := u.upsert(ctx, job, batch)
+			if err != nil {
+				return err
+			}
+			job.Imported += n
+		}
+
+		batch, invalid = batch[:0], invalid[:0]
+		clear(phones)
+
+		return u.repo.Update(ctx, job)
+	}
+
+	for {
+		client, line, errs, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		job.Total++
+
+		if client != nil {
+			errs = append(errs, fieldErrors(client)...)
+		}
+		if len(errs) > 0 {
+			job.Failed++
+			for _, e := range errs {
+				if saved == u.maxErrors {
+					break
+				}
+				e.JobID, e.Line = job.ID, line
+				invalid = append(invalid, e)
+				saved++
+			}
+		} else if i, ok := phones[client.PhoneNumber]; ok {
+			// Phone numbers of batch are unique, the last row wins
+			batch[i] = client
+		} else {
+			phones[client.PhoneNumber] = len(batch)
+			batch = append(batch, client)
+		}
+
+		if len(batch) == u.batch || len(invalid) >= u.batch {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+// upsert upserts batch of job and records it to audit log in one transaction
+func (u *ImportUseCase) upsert(ctx context.Context, job *entity.ImportJob, batch entity.Clients) (int64, error) {
+	var n int64
+	err := u.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if n, err = u.clients.Upsert(ctx, batch); err != nil {
+			return err
+		}
+
+		phones := make([]int64, len(batch))
+		for i, c := range batch {
+			phones[i] = c.PhoneNumber
+		}
+
+		return record(ctx, u.audit, entity.AuditImport, entity.AuditUpsert, job.ID, nil, importBatch{
+			Imported:     n,
+			PhoneNumbers: phones,
+		})
+	})
+
+	return n, err
+}
+
+// importBatch is audited upsert of batch of import job
+type importBatch struct {
+	Imported     int64   `json:"imported"`
+	PhoneNumbers []int64 `json:"phone_numbers"`
+}
+
+// finish sets final status of job, err fails it
+func (u *ImportUseCase) finish(ctx context.Context, job *entity.ImportJob, err error) {
+	finishedAt := u.now().UTC()
+	job.FinishedAt = &finishedAt
+	job.Status = entity.ImportDone
+	if err != nil {
+		job.Status = entity.ImportFailed
+		job.Error = err.Error()
+	}
+
+	if err = u.repo.Update(ctx, job); err != nil {
+		slog.Error("Import job update failed",
+			slog.Int64("JobID", job.ID),
+			slog.String("ErrorMsg", err.Error()))
+	}
+}
+
+// save writes upload to temporary file of dir and returns its path
+func (u *ImportUseCase) save(upload io.Reader) (string, error) {
+	if err := os.MkdirAll(u.dir, 0o755); err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(u.dir, "upload.*.tmp")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(f, upload)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// path returns path of upload of job
+func (u *ImportUseCase) path(job *entity.ImportJob) string {
+	return filepath.Join(u.dir, fmt.Sprintf("import_%d.%s", job.ID, job.Format))
+}
+
+// fieldErrors returns invalid fields of client as errors of its row
+func fieldErrors(c *entity.Client) []*entity.ImportRowError {
+	var errs []*entity.ImportRowError
+	for _, e := range c.Validate() {
+		errs = append(errs, &entity.ImportRowError{
+			Field:   e.Field,
+			Value:   clientField(c, e.Field),
+			Message: e.Message,
+		})
+	}
+
+	return errs
+}
+
+func clientField(c *entity.Client, field string) string {
+	switch field {
+	case "phone_number":
+		return strconv.FormatInt(c.PhoneNumber, 10)
+	case "mobile_operator_code":
+		return strconv.Itoa(c.MobileOperator)
+	case "tag":
+		return c.Tag
+	case "time_zone":
+		return strconv.Itoa(c.TimeZone)
+	}
+
+	return ""
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// maxLine bounds line of NDJSON file
+const maxLine = 1 << 20
+
+// importRows reads clients from rows of import file
+type importRows interface {
+	// Next returns client of the next row and its line, errs are fields
+	// which can't be parsed. Client is nil if row isn't parsed at all.
+	// io.EOF is returned after the last row.
+	Next() (client *entity.Client, line int64, errs []*entity.ImportRowError, err error)
+}
+
+// importColumns are columns of CSV, they're named like JSON fields of client
+var importColumns = []string{"phone_number", "mobile_operator_code", "tag", "time_zone"}
+
+func newImportRows(format string, r io.Reader) (importRows, error) {
+	switch format {
+	case entity.ImportCSV:
+		return newCSVRows(r)
+	case entity.ImportNDJSON:
+		return newNDJSONRows(r), nil
+	}
+
+	return nil, fmt.Errorf("%w: format %q", entity.ErrInvalidImport, format)
+}
+
+// csvRows reads CSV with header, order of columns is arbitrary and
+// unknown columns are ignored
+type csvRows struct {
+	r    *csv.Reader
+	cols map[string]int
+}
+
+func newCSVRows(r io.Reader) (*csvRows, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %w", entity.ErrInvalidImport, err)
+	}
+
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("%w: column %q is missing", entity.ErrInvalidImport, name)
+		}
+	}
+
+	return &csvRows{r: cr, cols: cols}, nil
+}
+
+func (r *csvRows) Next() (*entity.Client, int64, []*entity.ImportRowError, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, int64(parseErr.StartLine), []*entity.ImportRowError{{Message: parseErr.Err.Error()}}, nil
+		}
+		return nil, 0, nil, err
+	}
+	line, _ := r.r.FieldPos(0)
+
+	var (
+		c    entity.Client
+		errs []*entity.ImportRowError
+	)
+	field := func(name string) string {
+		if i := r.cols[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	parse := func(name string, bits int) int64 {
+		value := field(name)
+		n, err := strconv.ParseInt(value, 10, bits)
+		if err != nil {
+			errs = append(errs, &entity.ImportRowError{Field: name, Value: value, Message: "must be integer"})
+		}
+		return n
+	}
+
+	c.PhoneNumber = parse("phone_number", 64)
+	c.MobileOperator = int(parse("mobile_operator_code", 32))
+	c.Tag = field("tag")
+	c.TimeZone = int(parse("time_zone", 32))
+
+	if len(errs) > 0 {
+		return nil, int64(line), errs, nil
+	}
+
+	return &c, int64(line), nil, nil
+}
+
+// ndjsonRows reads JSON object of client per line, empty lines are skipped
+type ndjsonRows struct {
+	s    *bufio.Scanner
+	line int64
+}
+
+func newNDJSONRows(r io.Reader) *ndjsonRows {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+	return &ndjsonRows{s: s}
+}
+
+func (r *ndjsonRows) Next() (*entity.Client, int64, []*entity.ImportRowError, error) {
+	for r.s.Scan() {
+		r.line++
+
+		data := bytes.TrimSpace(r.s.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var c entity.Client
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, r.line, []*entity.ImportRowError{{Message: err.Error()}}, nil
+		}
+
+		// Only imported fields are taken
+		return &entity.Client{
+			PhoneNumber:    c.PhoneNumber,
+			MobileOperator: c.MobileOperator,
+			Tag:            c.Tag,
+			TimeZone:       c.TimeZone,
+		}, r.line, nil, nil
+	}
+
+	if err := r.s.Err(); err != nil {
+		return nil, r.line, nil, err
+	}
+
+	return nil, r.line, nil, io.EOF
+}
@@ -0,0 +1,193 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	repo := NewMockImportRepo(ctrl)
+	clients := NewMockClientRepo(ctrl)
+	audit := NewMockAuditRepo(ctrl)
+
+	var lastID int64
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, job *entity.ImportJob) error {
+			lastID++
+			job.ID = lastID
+			return nil
+		})
+	repo.EXPECT().Update(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+	// test 1: valid rows are upserted, the last row of phone wins, invalid rows are reported,
+	// batch is audited with actor and request which started job
+	{
+		u := usecase.NewImport(repo, clients, audit, noTx{}, t.TempDir(), 10, 10, 1)
+
+		upload := "tag,phone_number,time_zone,mobile_operator_code\n" +
+			"gold,79000000001,12,900\n" +
+			"bronze,79000000002,12,900\n" +
+			"vip,79000000001,0,901\n" +
+			"gold,abc,1,900\n"
+
+		job, err := u.Start(usecase.WithRequestID(usecase.WithActor(ctx, "alice"), "req-1"),
+			entity.ImportCSV, strings.NewReader(upload))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, job.Status, entity.ImportPending)
+		assert.Equal(t, job.Actor, "alice")
+
+		var reported []*entity.ImportRowError
+		repo.EXPECT().CreateErrors(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, errs []*entity.ImportRowError) error {
+				reported = append(reported, errs...)
+				return nil
+			})
+		clients.EXPECT().Upsert(gomock.Any(), entity.Clients{
+			{PhoneNumber: 79000000001, MobileOperator: 901, Tag: "vip", TimeZone: 0},
+		}).Return(int64(1), nil)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, r *entity.AuditRecord) error {
+				assert.Equal(t, r.Actor, "alice")
+				assert.Equal(t, r.RequestID, "req-1")
+				assert.Equal(t, r.Entity, entity.AuditImport)
+				assert.Equal(t, r.Action, entity.AuditUpsert)
+				assert.Equal(t, r.EntityID, job.ID)
+				assert.Equal(t, string(r.Diff["phone_numbers"].After), "[79000000001]")
+				return nil
+			})
+
+		assert.Equal(t, u.Import(ctx, job), nil)
+		assert.Equal(t, job.Status, entity.ImportDone)
+		assert.Equal(t, job.Total, int64(4))
+		assert.Equal(t, job.Imported, int64(1))
+		assert.Equal(t, job.Failed, int64(2))
+
+		assert.Equal(t, len(reported), 2)
+		assert.Equal(t, reported[0].Line, int64(3))
+		assert.Equal(t, reported[0].Field, "tag")
+		assert.Equal(t, reported[1].Line, int64(5))
+		assert.Equal(t, reported[1].Value, "abc")
+	}
+
+	// test 2: NDJSON is upserted by batches, only maxErrors errors are reported
+	{
+		u := usecase.NewImport(repo, clients, audit, noTx{}, t.TempDir(), 1, 1, 1)
+
+		upload := `{"phone_number": 79000000001, "mobile_operator_code": 900, "tag": "gold", "time_zone": 12}
+
+{"phone_number": "79000000002"}
+{"phone_number": 79000000003, "mobile_operator_code": 900, "tag": "silver", "time_zone": -4}
+{"phone_number": 1, "mobile_operator_code": 1, "tag": "silver", "time_zone": 0}
+`
+		job, err := u.Start(ctx, entity.ImportNDJSON, strings.NewReader(upload))
+		assert.Equal(t, err, nil)
+
+		repo.EXPECT().CreateErrors(gomock.Any(), gomock.Len(1)).Return(nil)
+		clients.EXPECT().Upsert(gomock.Any(), gomock.Len(1)).Times(2).Return(int64(1), nil)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(2).Return(nil)
+
+		assert.Equal(t, u.Import(ctx, job), nil)
+		assert.Equal(t, job.Total, int64(4))
+		assert.Equal(t, job.Imported, int64(2))
+		assert.Equal(t, job.Failed, int64(2))
+	}
+
+	// test 3: unknown format isn't started, job of CSV without column is failed, queue is bounded
+	{
+		u := usecase.NewImport(repo, clients, audit, noTx{}, t.TempDir(), 10, 10, 1)
+
+		_, err := u.Start(ctx, "xml", strings.NewReader(""))
+		assert.Equal(t, errors.Is(err, entity.ErrInvalidImport), true)
+
+		job, err := u.Start(ctx, entity.ImportCSV, strings.NewReader("phone_number,tag\n"))
+		assert.Equal(t, err, nil)
+
+		_, err = u.Start(ctx, entity.ImportCSV, strings.NewReader("phone_number,tag\n"))
+		assert.Equal(t, errors.Is(err, entity.ErrImportBusy), true)
+
+		err = u.Import(ctx, job)
+		assert.Equal(t, errors.Is(err, entity.ErrInvalidImport), true)
+		assert.Equal(t, job.Status, entity.ImportFailed)
+		assert.Equal(t, job.Error, `invalid import: column "mobile_operator_code" is missing`)
+	}
+}
+
+func TestImportRecover(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	repo := NewMockImportRepo(ctrl)
+	dir := t.TempDir()
+	u := usecase.NewImport(repo, NewMockClientRepo(ctrl), NewMockAuditRepo(ctrl), noTx{}, dir, 10, 10, 1)
+
+	var (
+		running = &entity.ImportJob{ID: 1, Format: entity.ImportCSV, Status: entity.ImportRunning}
+		pending = &entity.ImportJob{ID: 2, Format: entity.ImportCSV, Status: entity.ImportPending}
+		extra   = &entity.ImportJob{ID: 3, Format: entity.ImportCSV, Status: entity.ImportPending}
+	)
+	for _, name := range []string{"import_1.csv", "import_2.csv", "import_3.csv", "upload.1.tmp"} {
+		assert.Equal(t, os.WriteFile(filepath.Join(dir, name), []byte("phone_number,tag\n"), 0o644), nil)
+	}
+
+	// test 1: running job is failed, pending one is queued, the one over queue size is failed
+	{
+		repo.EXPECT().ReadUnfinished(gomock.Any()).Return([]*entity.ImportJob{running, pending, extra}, nil)
+		repo.EXPECT().Update(gomock.Any(), running).Return(nil)
+		repo.EXPECT().Update(gomock.Any(), extra).Return(nil)
+
+		assert.Equal(t, u.Recover(ctx), nil)
+		assert.Equal(t, running.Status, entity.ImportFailed)
+		assert.Equal(t, running.Error, entity.ErrImportInterrupted.Error())
+		assert.Equal(t, pending.Status, entity.ImportPending)
+		assert.Equal(t, extra.Status, entity.ImportFailed)
+		assert.Equal(t, extra.Error, entity.ErrImportBusy.Error())
+
+		left, _ := filepath.Glob(filepath.Join(dir, "*"))
+		assert.Equal(t, left, []string{filepath.Join(dir, "import_2.csv")})
+	}
+
+	// test 2: queued job is run, its upload is still there
+	{
+		ctx, cancel := context.WithCancel(ctx)
+		repo.EXPECT().Update(gomock.Any(), pending).Return(nil)
+		repo.EXPECT().Update(gomock.Any(), pending).
+			DoAndReturn(func(context.Context, *entity.ImportJob) error {
+				cancel()
+				return nil
+			})
+
+		u.Run(ctx)
+		assert.Equal(t, pending.Status, entity.ImportFailed)
+
+		assert.Equal(t, pending.Error, `invalid import: column "mobile_operator_code" is missing`)
+	}
+}
+
+func TestImportReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockImportRepo(ctrl)
+	u := usecase.NewImport(repo, nil, nil, noTx{}, t.TempDir(), 10, 10, 1)
+	job := &entity.ImportJob{ID: 1}
+
+	repo.EXPECT().ReadErrors(gomock.Any(), job, int64(0), gomock.Any()).Return([]*entity.ImportRowError{
+		{ID: 1, Line: 3, Field: "tag", Value: "bronze", Message: "must be silver, gold or vip"},
+		{ID: 2, Line: 5, Message: `bare " in non-quoted-field`},
+	}, nil)
+
+	var buf bytes.Buffer
+	assert.Equal(t, u.WriteReport(context.Background(), job, &buf), nil)
+	assert.Equal(t, buf.String(), "line,field,value,message\n"+
+		"3,tag,bronze,\"must be silver, gold or vip\"\n"+
+		"5,,,\"bare \"\" in non-quoted-field\"\n")
+}
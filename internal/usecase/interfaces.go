@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
 )
@@ -12,19 +14,49 @@ import (
 type (
 	// Client -
 	Client interface {
+		Get(context.Context, *entity.Client) (*entity.Client, error)
 		Add(context.Context, *entity.Client) error
 		Patch(context.Context, *entity.Client) error
 		Delete(context.Context, *entity.Client) error
+		Restore(context.Context, *entity.Client) error
 	}
 
 	// Mailing -
 	Mailing interface {
+		Get(context.Context, *entity.Mailing) (*entity.Mailing, error)
 		Add(context.Context, *entity.Mailing) error
 		Patch(context.Context, *entity.Mailing) error
 		Delete(context.Context, *entity.Mailing) error
+		Restore(context.Context, *entity.Mailing) error
+		// Archive moves mailings finished before time to archive, it returns their number
+		Archive(ctx context.Context, before time.Time) (int, error)
+
+		GetMailings(context.Context, *entity.MailingFilter) (*entity.MailingsPage, error)
+		GetMailingStats(context.Context, *entity.MailingFilter) (*entity.MailingStatsPage, error)
+		GetMessagesByMailing(context.Context, *entity.MessageFilter) (*entity.MessagesPage, error)
+		GetSeries(context.Context, *entity.SeriesFilter) (*entity.Series, error)
+	}
+
+	// Audit - log of changes of clients and mailings
+	Audit interface {
+		GetRecords(context.Context, *entity.AuditFilter) (*entity.AuditPage, error)
+	}
 
-		GetMailingStats(context.Context) ([]*entity.MailingStats, error)
-		GetMessagesByMailing(context.Context, *entity.Mailing) (entity.Messages, error)
+	// Import - async import of clients from uploaded files
+	Import interface {
+		Start(ctx context.Context, format string, upload io.Reader) (*entity.ImportJob, error)
+		Get(context.Context, *entity.ImportJob) (*entity.ImportJob, error)
+		WriteReport(context.Context, *entity.ImportJob, io.Writer) error
+	}
+
+	// Export - streaming and async export of clients and messages
+	Export interface {
+		// Write - streams export of request to writer, it returns number of rows
+		Write(context.Context, *entity.ExportRequest, io.Writer) (int64, error)
+		Start(context.Context, *entity.ExportRequest) (*entity.ExportJob, error)
+		Get(context.Context, *entity.ExportJob) (*entity.ExportJob, error)
+		// File - path of file of finished job
+		File(*entity.ExportJob) (string, error)
 	}
 
 	Consumer interface {
@@ -35,33 +67,124 @@ type (
 
 // Repositories
 type (
+	// TxManager - runs f in one transaction, repositories called with ctx of f
+	// take part in it. Nested calls use savepoints.
+	TxManager interface {
+		Do(ctx context.Context, f func(ctx context.Context) error) error
+	}
+
 	// ClientRepo -
 	ClientRepo interface {
 		Create(context.Context, *entity.Client) error
+		// Update and Delete change client only of its version, otherwise entity.ErrConflict
 		Update(context.Context, *entity.Client) error
+		// Delete - soft deletion, Restore - undo it
 		Delete(context.Context, *entity.Client) error
+		Restore(context.Context, *entity.Client) error
 
 		Read(context.Context, *entity.Client) (*entity.Client, error)
-		ReadByFilter(context.Context, *entity.Mailing) (entity.Clients, error)
+		// ReadPage - keyset page of audience: up to limit clients with ID > afterID
+		ReadPage(ctx context.Context, mailing *entity.Mailing, afterID int64, limit int) (entity.Clients, error)
+
+		// Upsert - bulk creation, clients of taken phone numbers are updated instead.
+		// Phone numbers of clients must be unique. It returns number of upserted clients.
+		Upsert(context.Context, entity.Clients) (int64, error)
+		// Export - calls f for every client of audience ordered by ID while rows are read
+		Export(ctx context.Context, mailing *entity.Mailing, f func(*entity.Client) error) error
 	}
 
 	// MailingRepo -
 	MailingRepo interface {
 		Create(context.Context, *entity.Mailing) error
+		// Update and Delete change mailing only of its version, otherwise entity.ErrConflict
 		Update(context.Context, *entity.Mailing) error
+		// Delete - soft deletion, Restore - undo it or return mailing from archive
 		Delete(context.Context, *entity.Mailing) error
+		Restore(context.Context, *entity.Mailing) error
+		// Archive - moves up to limit mailings finished before time with messages to archive,
+		// it returns IDs of archived mailings
+		Archive(ctx context.Context, before time.Time, limit int) ([]int64, error)
 
 		ReadWithMessages(context.Context, *entity.Mailing) (*entity.MailingStats, error)
+		// ReadStatsPage - stats of keyset page of mailings and cursor of the next one
+		ReadStatsPage(context.Context, *entity.MailingFilter) ([]*entity.MailingStats, string, error)
 		Read(context.Context, *entity.Mailing) (*entity.Mailing, error)
-		ReadAll(context.Context) (entity.Mailings, error)
+		// ReadPage - keyset page of mailings and cursor of the next one
+		ReadPage(context.Context, *entity.MailingFilter) (entity.Mailings, string, error)
 	}
 
 	// MessageRepo -
 	MessageRepo interface {
 		Create(context.Context, *entity.Message) error
 
-		ReadByMailing(context.Context, *entity.Mailing) (entity.Messages, error)
+		// ReadByMailing - keyset page of mailing messages and cursor of the next one
+		ReadByMailing(context.Context, *entity.MessageFilter) (entity.Messages, string, error)
+		// ReadSeries - counters of messages by time buckets, empty buckets are omitted
+		ReadSeries(context.Context, *entity.SeriesFilter) ([]*entity.SeriesPoint, error)
 		Read(context.Context, *entity.Message) (*entity.Message, error)
+		// Export - calls f for every message of filter ordered by ID while rows are read
+		Export(ctx context.Context, filter *entity.MessageFilter, f func(*entity.Message) error) error
+	}
+
+	// ChunkRepo - progress of mailing audience chunks
+	ChunkRepo interface {
+		Create(context.Context, *entity.MailingChunk) error
+		SetStatus(context.Context, *entity.MailingChunk) error
+
+		Read(context.Context, *entity.MailingChunk) (*entity.MailingChunk, error)
+		ReadByMailing(context.Context, *entity.Mailing) (entity.MailingChunks, error)
+
+		// Seal marks fan-out finished, Completed is true when all the chunks are done
+		Seal(ctx context.Context, mailing *entity.Mailing, chunks int) error
+		Completed(context.Context, *entity.Mailing) (bool, error)
+	}
+
+	// PartitionRepo - monthly partitions of messages
+	PartitionRepo interface {
+		// Create - partitions of months starting from month of time
+		Create(ctx context.Context, from time.Time, months int) error
+		List(context.Context) (entity.Partitions, error)
+		Export(context.Context, *entity.Partition, io.Writer) error
+		Drop(context.Context, *entity.Partition) error
+
+		// Lock - only one instance enforces retention at a time
+		Lock(context.Context) (release func(), ok bool, err error)
+	}
+
+	// AuditRepo - append-only log of changes
+	AuditRepo interface {
+		Create(context.Context, *entity.AuditRecord) error
+		// ReadPage - keyset page of records and cursor of the next one
+		ReadPage(context.Context, *entity.AuditFilter) ([]*entity.AuditRecord, string, error)
+	}
+
+	// ImportRepo - jobs of client import and their invalid rows
+	ImportRepo interface {
+		Create(context.Context, *entity.ImportJob) error
+		// Update - status, counters and error of job
+		Update(context.Context, *entity.ImportJob) error
+		Read(context.Context, *entity.ImportJob) (*entity.ImportJob, error)
+		// ReadUnfinished - pending and running jobs ordered by ID
+		ReadUnfinished(context.Context) ([]*entity.ImportJob, error)
+
+		CreateErrors(context.Context, []*entity.ImportRowError) error
+		// ReadErrors - up to limit errors of job with ID > afterID ordered by ID
+		ReadErrors(ctx context.Context, job *entity.ImportJob, afterID int64, limit int) ([]*entity.ImportRowError, error)
+	}
+
+	// ExportRepo - jobs of async export
+	ExportRepo interface {
+		Create(context.Context, *entity.ExportJob) error
+		// Update - status, number of rows, file and error of job
+		Update(context.Context, *entity.ExportJob) error
+		Read(context.Context, *entity.ExportJob) (*entity.ExportJob, error)
+		// ReadUnfinished - pending and running jobs ordered by ID
+		ReadUnfinished(context.Context) ([]*entity.ExportJob, error)
+	}
+
+	// LeaseRepo - per-chunk ownership between workers, chunk 0 is mailing fan-out
+	LeaseRepo interface {
+		Acquire(ctx context.Context, mailing *entity.Mailing, chunk int) (release func(), ok bool, err error)
 	}
 )
 
@@ -76,6 +199,11 @@ type (
 	AdditionalProducer interface {
 		Publish(context.Context, *entity.MailingWithClients) error
 	}
+
+	// EventPublisher - domain events for downstream consumers
+	EventPublisher interface {
+		Publish(context.Context, *entity.Event) error
+	}
 )
 
 // External API
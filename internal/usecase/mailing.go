@@ -2,41 +2,106 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
 )
 
+// _archiveBatch is number of mailings archived in one transaction
+const _archiveBatch = 100
+
 type MailingUseCase struct {
 	repo     MailingRepo
 	msgRepo  MessageRepo
+	audit    AuditRepo
 	producer GeneralProducer
+	events   EventPublisher
+	tx       TxManager
 }
 
-func NewMailing(repo MailingRepo, msgRepo MessageRepo, producer GeneralProducer) *MailingUseCase {
+func NewMailing(
+	repo MailingRepo, msgRepo MessageRepo, audit AuditRepo, producer GeneralProducer, events EventPublisher, tx TxManager,
+) *MailingUseCase {
 	return &MailingUseCase{
 		repo:     repo,
 		msgRepo:  msgRepo,
+		audit:    audit,
 		producer: producer,
+		events:   events,
+		tx:       tx,
 	}
 }
 
+// Add creates mailing and publishes it after transaction is committed,
+// so consumer never reads mailing that isn't committed. Mailing is
+// withdrawn if publishing fails, so it isn't left unpublished.
 func (u *MailingUseCase) Add(ctx context.Context, mailing *entity.Mailing) error {
-	err := u.repo.Create(ctx, mailing)
+	err := u.tx.Do(ctx, func(ctx context.Context) error {
+		if err := u.repo.Create(ctx, mailing); err != nil {
+			return err
+		}
+
+		return record(ctx, u.audit, entity.AuditMailing, entity.AuditCreate, mailing.ID, nil, mailing)
+	})
 	if err != nil {
 		return fmt.Errorf("MailingUseCase - Add(): %w", err)
 	}
 
-	err = u.producer.Publish(ctx, mailing)
-	if err != nil {
+	if err = u.producer.Publish(ctx, mailing); err != nil {
+		if wErr := u.withdraw(context.WithoutCancel(ctx), mailing); wErr != nil {
+			slog.Error("Unpublished mailing withdrawal failed",
+				slog.Int64("MailingID", mailing.ID),
+				slog.String("ErrorMsg", wErr.Error()))
+		}
 		return fmt.Errorf("MailingUseCase - Add(): %w", err)
 	}
 
+	emit(ctx, u.events, entity.EventMailingCreated, eventKey(mailing.ID), mailing)
+
 	return nil
 }
 
+// Get returns mailing that isn't deleted
+func (u *MailingUseCase) Get(ctx context.Context, mailing *entity.Mailing) (*entity.Mailing, error) {
+	m, err := u.repo.Read(ctx, mailing)
+	if err != nil {
+		return nil, fmt.Errorf("MailingUseCase - Get(): %w", err)
+	}
+
+	return m, nil
+}
+
+// Patch updates set fields of mailing of its version, version of mailing
+// is set to the new one. entity.ErrNotFound is returned if mailing doesn't
+// exist, *entity.ConflictError if it's changed since version.
 func (u *MailingUseCase) Patch(ctx context.Context, mailing *entity.Mailing) error {
-	err := u.repo.Update(ctx, mailing)
+	if mailing.Version == 0 {
+		return fmt.Errorf("MailingUseCase - Patch(): %w", entity.ErrVersionRequired)
+	}
+
+	err := u.tx.Do(ctx, func(ctx context.Context) error {
+		before, err := u.repo.Read(ctx, mailing)
+		if err != nil {
+			return err
+		}
+
+		if err = u.repo.Update(ctx, mailing); errors.Is(err, entity.ErrConflict) {
+			return u.conflict(ctx, mailing)
+		} else if err != nil {
+			return err
+		}
+
+		after, err := u.repo.Read(ctx, mailing)
+		if err != nil {
+			return err
+		}
+		mailing.Version = after.Version
+
+		return record(ctx, u.audit, entity.AuditMailing, entity.AuditUpdate, mailing.ID, before, after)
+	})
 	if err != nil {
 		return fmt.Errorf("MailingUseCase - Patch(): %w", err)
 	}
@@ -44,8 +109,28 @@ func (u *MailingUseCase) Patch(ctx context.Context, mailing *entity.Mailing) err
 	return nil
 }
 
+// Delete marks mailing of its version deleted, so its sending is stopped.
+// entity.ErrNotFound is returned if mailing doesn't exist or it's already
+// deleted, *entity.ConflictError if it's changed since version.
 func (u *MailingUseCase) Delete(ctx context.Context, mailing *entity.Mailing) error {
-	err := u.repo.Create(ctx, mailing)
+	if mailing.Version == 0 {
+		return fmt.Errorf("MailingUseCase - Delete(): %w", entity.ErrVersionRequired)
+	}
+
+	err := u.tx.Do(ctx, func(ctx context.Context) error {
+		before, err := u.repo.Read(ctx, mailing)
+		if err != nil {
+			return err
+		}
+
+		if err = u.repo.Delete(ctx, mailing); errors.Is(err, entity.ErrConflict) {
+			return u.conflict(ctx, mailing)
+		} else if err != nil {
+			return err
+		}
+
+		return record(ctx, u.audit, entity.AuditMailing, entity.AuditDelete, mailing.ID, before, nil)
+	})
 	if err != nil {
 		return fmt.Errorf("MailingUseCase - Delete(): %w", err)
 	}
@@ -53,37 +138,152 @@ func (u *MailingUseCase) Delete(ctx context.Context, mailing *entity.Mailing) er
 	return nil
 }
 
-func (u *MailingUseCase) GetMailingStats(ctx context.Context) ([]*entity.MailingStats, error) {
-	var (
-		stats []*entity.MailingStats
-	)
+// Restore returns deleted or archived mailing. Sending stopped by
+// deletion isn't resumed.
+func (u *MailingUseCase) Restore(ctx context.Context, mailing *entity.Mailing) error {
+	err := u.tx.Do(ctx, func(ctx context.Context) error {
+		if err := u.repo.Restore(ctx, mailing); err != nil {
+			return err
+		}
+
+		after, err := u.repo.Read(ctx, mailing)
+		if err != nil {
+			return err
+		}
 
-	allMailings, err := u.repo.ReadAll(ctx)
+		return record(ctx, u.audit, entity.AuditMailing, entity.AuditRestore, mailing.ID, nil, after)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("MailingUseCase - GetMailingStats(): %w", err)
+		return fmt.Errorf("MailingUseCase - Restore(): %w", err)
+	}
+
+	return nil
+}
+
+// withdraw deletes created mailing which wasn't published
+func (u *MailingUseCase) withdraw(ctx context.Context, mailing *entity.Mailing) error {
+	return u.tx.Do(ctx, func(ctx context.Context) error {
+		if err := u.repo.Delete(ctx, mailing); err != nil {
+			return err
+		}
+
+		return record(ctx, u.audit, entity.AuditMailing, entity.AuditDelete, mailing.ID, mailing, nil)
+	})
+}
+
+// conflict returns current state of mailing changed by another request,
+// entity.ErrNotFound is returned if it's deleted
+func (u *MailingUseCase) conflict(ctx context.Context, mailing *entity.Mailing) error {
+	current, err := u.repo.Read(ctx, mailing)
+	if err != nil {
+		return err
 	}
 
-	for _, mailing := range allMailings {
-		stat, err := u.repo.ReadWithMessages(ctx, mailing)
+	return &entity.ConflictError{Version: current.Version, Current: current}
+}
+
+// Archive moves mailings finished before time to archive by batches,
+// so every transaction stays short. Every archived mailing is recorded
+// to audit log in transaction of its batch.
+func (u *MailingUseCase) Archive(ctx context.Context, before time.Time) (int, error) {
+	var total int
+	for {
+		var n int
+		err := u.tx.Do(ctx, func(ctx context.Context) error {
+			ids, err := u.repo.Archive(ctx, before, _archiveBatch)
+			if err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				if err = record(ctx, u.audit, entity.AuditMailing, entity.AuditArchive, id, nil, nil); err != nil {
+					return err
+				}
+			}
+			n = len(ids)
+
+			return nil
+		})
 		if err != nil {
-			return nil, fmt.Errorf("MailingUseCase - GetMailingStats(): %w", err)
+			return total, fmt.Errorf("MailingUseCase - Archive(): %w", err)
 		}
 
-		stats = append(stats, stat)
+		total += n
+		if n < _archiveBatch {
+			return total, nil
+		}
+	}
+}
+
+// GetMailings returns page of mailings
+func (u *MailingUseCase) GetMailings(ctx context.Context, filter *entity.MailingFilter) (
+	*entity.MailingsPage, error,
+) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("MailingUseCase - GetMailings(): %w", err)
+	}
+
+	mailings, next, err := u.repo.ReadPage(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("MailingUseCase - GetMailings(): %w", err)
+	}
+
+	if mailings == nil {
+		mailings = entity.Mailings{}
+	}
+
+	return &entity.MailingsPage{Items: mailings, NextCursor: next}, nil
+}
+
+// GetMailingStats returns stats of page of mailings
+func (u *MailingUseCase) GetMailingStats(ctx context.Context, filter *entity.MailingFilter) (
+	*entity.MailingStatsPage, error,
+) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("MailingUseCase - GetMailingStats(): %w", err)
+	}
+
+	stats, next, err := u.repo.ReadStatsPage(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("MailingUseCase - GetMailingStats(): %w", err)
 	}
 
-	return stats, nil
+	if stats == nil {
+		stats = []*entity.MailingStats{}
+	}
+
+	return &entity.MailingStatsPage{Items: stats, NextCursor: next}, nil
 }
 
-func (u *MailingUseCase) GetMessagesByMailing(ctx context.Context, mailing *entity.Mailing) (
-	entity.Messages, error,
+func (u *MailingUseCase) GetMessagesByMailing(ctx context.Context, filter *entity.MessageFilter) (
+	*entity.MessagesPage, error,
 ) {
-	var err error
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("MailingUseCase - GetMessageByMailing(): %w", err)
+	}
 
-	msgs, err := u.msgRepo.ReadByMailing(ctx, mailing)
+	msgs, next, err := u.msgRepo.ReadByMailing(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("MailingUseCase - GetMessageByMailing(): %w", err)
 	}
 
-	return msgs, nil
+	if msgs == nil {
+		msgs = entity.Messages{}
+	}
+
+	return &entity.MessagesPage{Items: msgs, NextCursor: next}, nil
+}
+
+// GetSeries returns delivery counters of mailing or of all the mailings by time buckets
+func (u *MailingUseCase) GetSeries(ctx context.Context, filter *entity.SeriesFilter) (*entity.Series, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("MailingUseCase - GetSeries(): %w", err)
+	}
+
+	points, err := u.msgRepo.ReadSeries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("MailingUseCase - GetSeries(): %w", err)
+	}
+
+	return entity.NewSeries(filter, points), nil
 }
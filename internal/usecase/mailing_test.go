@@ -0,0 +1,178 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+// noTx runs function without transaction
+type noTx struct{}
+
+func (noTx) Do(ctx context.Context, f func(ctx context.Context) error) error {
+	return f(ctx)
+}
+
+func TestMailingAdd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockMailingRepo(ctrl)
+	producer := NewMockGeneralProducer(ctrl)
+	tx := NewMockTxManager(ctrl)
+	audit := NewMockAuditRepo(ctrl)
+	u := usecase.NewMailing(repo, NewMockMessageRepo(ctrl), audit, producer, NewMockEventPublisher(ctrl), tx)
+
+	m := &entity.Mailing{ID: 1}
+	var inTx bool
+	tx.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, f func(context.Context) error) error {
+			inTx = true
+			defer func() { inTx = false }()
+			return f(ctx)
+		})
+
+	// test 1: mailing is published after commit, unpublished mailing is withdrawn
+	{
+		repo.EXPECT().Create(gomock.Any(), m).Return(nil)
+		producer.EXPECT().Publish(gomock.Any(), m).
+			DoAndReturn(func(context.Context, *entity.Mailing) error {
+				assert.Equal(t, inTx, false)
+				return errors.New("broker down")
+			})
+		repo.EXPECT().Delete(gomock.Any(), m).Return(nil)
+		gomock.InOrder(
+			audit.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil),
+			audit.EXPECT().Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, r *entity.AuditRecord) error {
+					assert.Equal(t, r.Action, entity.AuditDelete)
+					return nil
+				}),
+		)
+
+		assert.NotEqual(t, u.Add(context.Background(), m), nil)
+	}
+
+	// test 2: mailing isn't published if it's not created
+	{
+		repo.EXPECT().Create(gomock.Any(), m).Return(errors.New("conn lost"))
+
+		assert.NotEqual(t, u.Add(context.Background(), m), nil)
+	}
+}
+
+func TestMailingList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockMailingRepo(ctrl)
+	u := usecase.NewMailing(repo, NewMockMessageRepo(ctrl), NewMockAuditRepo(ctrl),
+		NewMockGeneralProducer(ctrl), NewMockEventPublisher(ctrl), noTx{})
+
+	// test 1: mailings aren't read by invalid filter
+	{
+		_, err := u.GetMailings(context.Background(), &entity.MailingFilter{Page: entity.Page{Sort: "text"}})
+		assert.Equal(t, errors.Is(err, entity.ErrInvalidFilter), true)
+	}
+
+	// test 2: empty page has empty items and cursor of repo
+	{
+		repo.EXPECT().ReadPage(gomock.Any(), gomock.Any()).Return(nil, "next", nil)
+
+		page, err := u.GetMailings(context.Background(), &entity.MailingFilter{})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, page.Items, entity.Mailings{})
+		assert.Equal(t, page.NextCursor, "next")
+	}
+}
+
+func TestMailingDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockMailingRepo(ctrl)
+	audit := NewMockAuditRepo(ctrl)
+	u := usecase.NewMailing(repo, NewMockMessageRepo(ctrl), audit, NewMockGeneralProducer(ctrl), NewMockEventPublisher(ctrl), noTx{})
+
+	m := &entity.Mailing{ID: 1, Version: 1}
+
+	// test 1: mailing is deleted, not created, deletion is recorded
+	{
+		repo.EXPECT().Read(gomock.Any(), m).Return(&entity.Mailing{ID: 1, Tag: "gold", Version: 1}, nil)
+		repo.EXPECT().Delete(gomock.Any(), m).Return(nil)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, r *entity.AuditRecord) error {
+				assert.Equal(t, r.Action, entity.AuditDelete)
+				assert.Equal(t, string(r.Diff["tag"].After), "null")
+				return nil
+			})
+		assert.Equal(t, u.Delete(context.Background(), m), nil)
+	}
+
+	// test 2: restoring of mailing that isn't deleted fails with ErrNotFound
+	{
+		repo.EXPECT().Restore(gomock.Any(), m).Return(entity.ErrNotFound)
+		err := u.Restore(context.Background(), m)
+		assert.Equal(t, errors.Is(err, entity.ErrNotFound), true)
+	}
+}
+
+func TestMailingArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockMailingRepo(ctrl)
+	audit := NewMockAuditRepo(ctrl)
+	u := usecase.NewMailing(repo, NewMockMessageRepo(ctrl), audit, NewMockGeneralProducer(ctrl),
+		NewMockEventPublisher(ctrl), noTx{})
+
+	before := time.Now()
+	batch := func(first, n int) []int64 {
+		ids := make([]int64, n)
+		for i := range ids {
+			ids[i] = int64(first + i)
+		}
+		return ids
+	}
+
+	// test 1: batches are archived until the last incomplete one, every mailing is audited
+	{
+		gomock.InOrder(
+			repo.EXPECT().Archive(gomock.Any(), before, 100).Return(batch(1, 100), nil),
+			repo.EXPECT().Archive(gomock.Any(), before, 100).Return(batch(101, 100), nil),
+			repo.EXPECT().Archive(gomock.Any(), before, 100).Return(batch(201, 7), nil),
+		)
+
+		var audited []int64
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(207).
+			DoAndReturn(func(_ context.Context, r *entity.AuditRecord) error {
+				assert.Equal(t, r.Entity, entity.AuditMailing)
+				assert.Equal(t, r.Action, entity.AuditArchive)
+				audited = append(audited, r.EntityID)
+				return nil
+			})
+
+		n, err := u.Archive(context.Background(), before)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, n, 207)
+		assert.Equal(t, audited, batch(1, 207))
+	}
+
+	// test 2: number of archived mailings is returned with error, failed audit fails its batch
+	{
+		gomock.InOrder(
+			repo.EXPECT().Archive(gomock.Any(), before, 100).Return(batch(1, 100), nil),
+			repo.EXPECT().Archive(gomock.Any(), before, 100).Return(nil, errors.New("conn lost")),
+		)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(100).Return(nil)
+
+		n, err := u.Archive(context.Background(), before)
+		assert.NotEqual(t, err, nil)
+		assert.Equal(t, n, 100)
+
+		repo.EXPECT().Archive(gomock.Any(), before, 100).Return(batch(1, 7), nil)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("conn lost"))
+
+		n, err = u.Archive(context.Background(), before)
+		assert.NotEqual(t, err, nil)
+		assert.Equal(t, n, 0)
+	}
+}
@@ -6,7 +6,9 @@ package usecase_test
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "gitlab.com/fluxx1on_group/event_message_service/internal/entity"
@@ -63,6 +65,21 @@ func (mr *MockClientMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), arg0, arg1)
 }
 
+// Get mocks base method.
+func (m *MockClient) Get(arg0 context.Context, arg1 *entity.Client) (*entity.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockClientMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), arg0, arg1)
+}
+
 // Patch mocks base method.
 func (m *MockClient) Patch(arg0 context.Context, arg1 *entity.Client) error {
 	m.ctrl.T.Helper()
@@ -77,6 +94,20 @@ func (mr *MockClientMockRecorder) Patch(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockClient)(nil).Patch), arg0, arg1)
 }
 
+// Restore mocks base method.
+func (m *MockClient) Restore(arg0 context.Context, arg1 *entity.Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockClientMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockClient)(nil).Restore), arg0, arg1)
+}
+
 // MockMailing is a mock of Mailing interface.
 type MockMailing struct {
 	ctrl     *gomock.Controller
@@ -114,6 +145,21 @@ func (mr *MockMailingMockRecorder) Add(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockMailing)(nil).Add), arg0, arg1)
 }
 
+// Archive mocks base method.
+func (m *MockMailing) Archive(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockMailingMockRecorder) Archive(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockMailing)(nil).Archive), ctx, before)
+}
+
 // Delete mocks base method.
 func (m *MockMailing) Delete(arg0 context.Context, arg1 *entity.Mailing) error {
 	m.ctrl.T.Helper()
@@ -128,26 +174,56 @@ func (mr *MockMailingMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMailing)(nil).Delete), arg0, arg1)
 }
 
+// Get mocks base method.
+func (m *MockMailing) Get(arg0 context.Context, arg1 *entity.Mailing) (*entity.Mailing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Mailing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockMailingMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMailing)(nil).Get), arg0, arg1)
+}
+
 // GetMailingStats mocks base method.
-func (m *MockMailing) GetMailingStats(arg0 context.Context) ([]*entity.MailingStats, error) {
+func (m *MockMailing) GetMailingStats(arg0 context.Context, arg1 *entity.MailingFilter) (*entity.MailingStatsPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMailingStats", arg0)
-	ret0, _ := ret[0].([]*entity.MailingStats)
+	ret := m.ctrl.Call(m, "GetMailingStats", arg0, arg1)
+	ret0, _ := ret[0].(*entity.MailingStatsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMailingStats indicates an expected call of GetMailingStats.
-func (mr *MockMailingMockRecorder) GetMailingStats(arg0 interface{}) *gomock.Call {
+func (mr *MockMailingMockRecorder) GetMailingStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMailingStats", reflect.TypeOf((*MockMailing)(nil).GetMailingStats), arg0, arg1)
+}
+
+// GetMailings mocks base method.
+func (m *MockMailing) GetMailings(arg0 context.Context, arg1 *entity.MailingFilter) (*entity.MailingsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMailings", arg0, arg1)
+	ret0, _ := ret[0].(*entity.MailingsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMailings indicates an expected call of GetMailings.
+func (mr *MockMailingMockRecorder) GetMailings(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMailingStats", reflect.TypeOf((*MockMailing)(nil).GetMailingStats), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMailings", reflect.TypeOf((*MockMailing)(nil).GetMailings), arg0, arg1)
 }
 
 // GetMessagesByMailing mocks base method.
-func (m *MockMailing) GetMessagesByMailing(arg0 context.Context, arg1 *entity.Mailing) (entity.Messages, error) {
+func (m *MockMailing) GetMessagesByMailing(arg0 context.Context, arg1 *entity.MessageFilter) (*entity.MessagesPage, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetMessagesByMailing", arg0, arg1)
-	ret0, _ := ret[0].(entity.Messages)
+	ret0, _ := ret[0].(*entity.MessagesPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -158,6 +234,21 @@ func (mr *MockMailingMockRecorder) GetMessagesByMailing(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesByMailing", reflect.TypeOf((*MockMailing)(nil).GetMessagesByMailing), arg0, arg1)
 }
 
+// GetSeries mocks base method.
+func (m *MockMailing) GetSeries(arg0 context.Context, arg1 *entity.SeriesFilter) (*entity.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeries", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeries indicates an expected call of GetSeries.
+func (mr *MockMailingMockRecorder) GetSeries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeries", reflect.TypeOf((*MockMailing)(nil).GetSeries), arg0, arg1)
+}
+
 // Patch mocks base method.
 func (m *MockMailing) Patch(arg0 context.Context, arg1 *entity.Mailing) error {
 	m.ctrl.T.Helper()
@@ -172,6 +263,208 @@ func (mr *MockMailingMockRecorder) Patch(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockMailing)(nil).Patch), arg0, arg1)
 }
 
+// Restore mocks base method.
+func (m *MockMailing) Restore(arg0 context.Context, arg1 *entity.Mailing) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockMailingMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockMailing)(nil).Restore), arg0, arg1)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// GetRecords mocks base method.
+func (m *MockAudit) GetRecords(arg0 context.Context, arg1 *entity.AuditFilter) (*entity.AuditPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecords", arg0, arg1)
+	ret0, _ := ret[0].(*entity.AuditPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecords indicates an expected call of GetRecords.
+func (mr *MockAuditMockRecorder) GetRecords(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecords", reflect.TypeOf((*MockAudit)(nil).GetRecords), arg0, arg1)
+}
+
+// MockImport is a mock of Import interface.
+type MockImport struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportMockRecorder
+}
+
+// MockImportMockRecorder is the mock recorder for MockImport.
+type MockImportMockRecorder struct {
+	mock *MockImport
+}
+
+// NewMockImport creates a new mock instance.
+func NewMockImport(ctrl *gomock.Controller) *MockImport {
+	mock := &MockImport{ctrl: ctrl}
+	mock.recorder = &MockImportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImport) EXPECT() *MockImportMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockImport) Get(arg0 context.Context, arg1 *entity.ImportJob) (*entity.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*entity.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockImportMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockImport)(nil).Get), arg0, arg1)
+}
+
+// Start mocks base method.
+func (m *MockImport) Start(ctx context.Context, format string, upload io.Reader) (*entity.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, format, upload)
+	ret0, _ := ret[0].(*entity.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockImportMockRecorder) Start(ctx, format, upload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockImport)(nil).Start), ctx, format, upload)
+}
+
+// WriteReport mocks base method.
+func (m *MockImport) WriteReport(arg0 context.Context, arg1 *entity.ImportJob, arg2 io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteReport", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteReport indicates an expected call of WriteReport.
+func (mr *MockImportMockRecorder) WriteReport(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteReport", reflect.TypeOf((*MockImport)(nil).WriteReport), arg0, arg1, arg2)
+}
+
+// MockExport is a mock of Export interface.
+type MockExport struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportMockRecorder
+}
+
+// MockExportMockRecorder is the mock recorder for MockExport.
+type MockExportMockRecorder struct {
+	mock *MockExport
+}
+
+// NewMockExport creates a new mock instance.
+func NewMockExport(ctrl *gomock.Controller) *MockExport {
+	mock := &MockExport{ctrl: ctrl}
+	mock.recorder = &MockExportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExport) EXPECT() *MockExportMockRecorder {
+	return m.recorder
+}
+
+// File mocks base method.
+func (m *MockExport) File(arg0 *entity.ExportJob) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "File", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// File indicates an expected call of File.
+func (mr *MockExportMockRecorder) File(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "File", reflect.TypeOf((*MockExport)(nil).File), arg0)
+}
+
+// Get mocks base method.
+func (m *MockExport) Get(arg0 context.Context, arg1 *entity.ExportJob) (*entity.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*entity.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExportMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExport)(nil).Get), arg0, arg1)
+}
+
+// Start mocks base method.
+func (m *MockExport) Start(arg0 context.Context, arg1 *entity.ExportRequest) (*entity.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0, arg1)
+	ret0, _ := ret[0].(*entity.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockExportMockRecorder) Start(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockExport)(nil).Start), arg0, arg1)
+}
+
+// Write mocks base method.
+func (m *MockExport) Write(arg0 context.Context, arg1 *entity.ExportRequest, arg2 io.Writer) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockExportMockRecorder) Write(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockExport)(nil).Write), arg0, arg1, arg2)
+}
+
 // MockConsumer is a mock of Consumer interface.
 type MockConsumer struct {
 	ctrl     *gomock.Controller
@@ -225,6 +518,43 @@ func (mr *MockConsumerMockRecorder) ConsumePool(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumePool", reflect.TypeOf((*MockConsumer)(nil).ConsumePool), arg0, arg1)
 }
 
+// MockTxManager is a mock of TxManager interface.
+type MockTxManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxManagerMockRecorder
+}
+
+// MockTxManagerMockRecorder is the mock recorder for MockTxManager.
+type MockTxManagerMockRecorder struct {
+	mock *MockTxManager
+}
+
+// NewMockTxManager creates a new mock instance.
+func NewMockTxManager(ctrl *gomock.Controller) *MockTxManager {
+	mock := &MockTxManager{ctrl: ctrl}
+	mock.recorder = &MockTxManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTxManager) EXPECT() *MockTxManagerMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockTxManager) Do(ctx context.Context, f func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, f)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockTxManagerMockRecorder) Do(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockTxManager)(nil).Do), ctx, f)
+}
+
 // MockClientRepo is a mock of ClientRepo interface.
 type MockClientRepo struct {
 	ctrl     *gomock.Controller
@@ -276,6 +606,20 @@ func (mr *MockClientRepoMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClientRepo)(nil).Delete), arg0, arg1)
 }
 
+// Export mocks base method.
+func (m *MockClientRepo) Export(ctx context.Context, mailing *entity.Mailing, f func(*entity.Client) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, mailing, f)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockClientRepoMockRecorder) Export(ctx, mailing, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockClientRepo)(nil).Export), ctx, mailing, f)
+}
+
 // Read mocks base method.
 func (m *MockClientRepo) Read(arg0 context.Context, arg1 *entity.Client) (*entity.Client, error) {
 	m.ctrl.T.Helper()
@@ -291,19 +635,33 @@ func (mr *MockClientRepoMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockClientRepo)(nil).Read), arg0, arg1)
 }
 
-// ReadByFilter mocks base method.
-func (m *MockClientRepo) ReadByFilter(arg0 context.Context, arg1 *entity.Mailing) (entity.Clients, error) {
+// ReadPage mocks base method.
+func (m *MockClientRepo) ReadPage(ctx context.Context, mailing *entity.Mailing, afterID int64, limit int) (entity.Clients, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReadByFilter", arg0, arg1)
+	ret := m.ctrl.Call(m, "ReadPage", ctx, mailing, afterID, limit)
 	ret0, _ := ret[0].(entity.Clients)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ReadByFilter indicates an expected call of ReadByFilter.
-func (mr *MockClientRepoMockRecorder) ReadByFilter(arg0, arg1 interface{}) *gomock.Call {
+// ReadPage indicates an expected call of ReadPage.
+func (mr *MockClientRepoMockRecorder) ReadPage(ctx, mailing, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadPage", reflect.TypeOf((*MockClientRepo)(nil).ReadPage), ctx, mailing, afterID, limit)
+}
+
+// Restore mocks base method.
+func (m *MockClientRepo) Restore(arg0 context.Context, arg1 *entity.Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockClientRepoMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadByFilter", reflect.TypeOf((*MockClientRepo)(nil).ReadByFilter), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockClientRepo)(nil).Restore), arg0, arg1)
 }
 
 // Update mocks base method.
@@ -320,6 +678,21 @@ func (mr *MockClientRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockClientRepo)(nil).Update), arg0, arg1)
 }
 
+// Upsert mocks base method.
+func (m *MockClientRepo) Upsert(arg0 context.Context, arg1 entity.Clients) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockClientRepoMockRecorder) Upsert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockClientRepo)(nil).Upsert), arg0, arg1)
+}
+
 // MockMailingRepo is a mock of MailingRepo interface.
 type MockMailingRepo struct {
 	ctrl     *gomock.Controller
@@ -343,6 +716,21 @@ func (m *MockMailingRepo) EXPECT() *MockMailingRepoMockRecorder {
 	return m.recorder
 }
 
+// Archive mocks base method.
+func (m *MockMailingRepo) Archive(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, before, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockMailingRepoMockRecorder) Archive(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockMailingRepo)(nil).Archive), ctx, before, limit)
+}
+
 // Create mocks base method.
 func (m *MockMailingRepo) Create(arg0 context.Context, arg1 *entity.Mailing) error {
 	m.ctrl.T.Helper()
@@ -386,19 +774,36 @@ func (mr *MockMailingRepoMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockMailingRepo)(nil).Read), arg0, arg1)
 }
 
-// ReadAll mocks base method.
-func (m *MockMailingRepo) ReadAll(arg0 context.Context) (entity.Mailings, error) {
+// ReadPage mocks base method.
+func (m *MockMailingRepo) ReadPage(arg0 context.Context, arg1 *entity.MailingFilter) (entity.Mailings, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReadAll", arg0)
+	ret := m.ctrl.Call(m, "ReadPage", arg0, arg1)
 	ret0, _ := ret[0].(entity.Mailings)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReadPage indicates an expected call of ReadPage.
+func (mr *MockMailingRepoMockRecorder) ReadPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadPage", reflect.TypeOf((*MockMailingRepo)(nil).ReadPage), arg0, arg1)
+}
+
+// ReadStatsPage mocks base method.
+func (m *MockMailingRepo) ReadStatsPage(arg0 context.Context, arg1 *entity.MailingFilter) ([]*entity.MailingStats, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStatsPage", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.MailingStats)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-// ReadAll indicates an expected call of ReadAll.
-func (mr *MockMailingRepoMockRecorder) ReadAll(arg0 interface{}) *gomock.Call {
+// ReadStatsPage indicates an expected call of ReadStatsPage.
+func (mr *MockMailingRepoMockRecorder) ReadStatsPage(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockMailingRepo)(nil).ReadAll), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStatsPage", reflect.TypeOf((*MockMailingRepo)(nil).ReadStatsPage), arg0, arg1)
 }
 
 // ReadWithMessages mocks base method.
@@ -416,6 +821,20 @@ func (mr *MockMailingRepoMockRecorder) ReadWithMessages(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadWithMessages", reflect.TypeOf((*MockMailingRepo)(nil).ReadWithMessages), arg0, arg1)
 }
 
+// Restore mocks base method.
+func (m *MockMailingRepo) Restore(arg0 context.Context, arg1 *entity.Mailing) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockMailingRepoMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockMailingRepo)(nil).Restore), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockMailingRepo) Update(arg0 context.Context, arg1 *entity.Mailing) error {
 	m.ctrl.T.Helper()
@@ -467,6 +886,20 @@ func (mr *MockMessageRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMessageRepo)(nil).Create), arg0, arg1)
 }
 
+// Export mocks base method.
+func (m *MockMessageRepo) Export(ctx context.Context, filter *entity.MessageFilter, f func(*entity.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, filter, f)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockMessageRepoMockRecorder) Export(ctx, filter, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockMessageRepo)(nil).Export), ctx, filter, f)
+}
+
 // Read mocks base method.
 func (m *MockMessageRepo) Read(arg0 context.Context, arg1 *entity.Message) (*entity.Message, error) {
 	m.ctrl.T.Helper()
@@ -483,12 +916,13 @@ func (mr *MockMessageRepoMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call
 }
 
 // ReadByMailing mocks base method.
-func (m *MockMessageRepo) ReadByMailing(arg0 context.Context, arg1 *entity.Mailing) (entity.Messages, error) {
+func (m *MockMessageRepo) ReadByMailing(arg0 context.Context, arg1 *entity.MessageFilter) (entity.Messages, string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ReadByMailing", arg0, arg1)
 	ret0, _ := ret[0].(entity.Messages)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // ReadByMailing indicates an expected call of ReadByMailing.
@@ -497,22 +931,526 @@ func (mr *MockMessageRepoMockRecorder) ReadByMailing(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadByMailing", reflect.TypeOf((*MockMessageRepo)(nil).ReadByMailing), arg0, arg1)
 }
 
-// MockGeneralProducer is a mock of GeneralProducer interface.
-type MockGeneralProducer struct {
-	ctrl     *gomock.Controller
-	recorder *MockGeneralProducerMockRecorder
+// ReadSeries mocks base method.
+func (m *MockMessageRepo) ReadSeries(arg0 context.Context, arg1 *entity.SeriesFilter) ([]*entity.SeriesPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadSeries", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.SeriesPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockGeneralProducerMockRecorder is the mock recorder for MockGeneralProducer.
-type MockGeneralProducerMockRecorder struct {
-	mock *MockGeneralProducer
+// ReadSeries indicates an expected call of ReadSeries.
+func (mr *MockMessageRepoMockRecorder) ReadSeries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadSeries", reflect.TypeOf((*MockMessageRepo)(nil).ReadSeries), arg0, arg1)
 }
 
-// NewMockGeneralProducer creates a new mock instance.
-func NewMockGeneralProducer(ctrl *gomock.Controller) *MockGeneralProducer {
-	mock := &MockGeneralProducer{ctrl: ctrl}
-	mock.recorder = &MockGeneralProducerMockRecorder{mock}
-	return mock
+// MockChunkRepo is a mock of ChunkRepo interface.
+type MockChunkRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockChunkRepoMockRecorder
+}
+
+// MockChunkRepoMockRecorder is the mock recorder for MockChunkRepo.
+type MockChunkRepoMockRecorder struct {
+	mock *MockChunkRepo
+}
+
+// NewMockChunkRepo creates a new mock instance.
+func NewMockChunkRepo(ctrl *gomock.Controller) *MockChunkRepo {
+	mock := &MockChunkRepo{ctrl: ctrl}
+	mock.recorder = &MockChunkRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChunkRepo) EXPECT() *MockChunkRepoMockRecorder {
+	return m.recorder
+}
+
+// Completed mocks base method.
+func (m *MockChunkRepo) Completed(arg0 context.Context, arg1 *entity.Mailing) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Completed", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Completed indicates an expected call of Completed.
+func (mr *MockChunkRepoMockRecorder) Completed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Completed", reflect.TypeOf((*MockChunkRepo)(nil).Completed), arg0, arg1)
+}
+
+// Create mocks base method.
+func (m *MockChunkRepo) Create(arg0 context.Context, arg1 *entity.MailingChunk) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockChunkRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockChunkRepo)(nil).Create), arg0, arg1)
+}
+
+// Read mocks base method.
+func (m *MockChunkRepo) Read(arg0 context.Context, arg1 *entity.MailingChunk) (*entity.MailingChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", arg0, arg1)
+	ret0, _ := ret[0].(*entity.MailingChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockChunkRepoMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockChunkRepo)(nil).Read), arg0, arg1)
+}
+
+// ReadByMailing mocks base method.
+func (m *MockChunkRepo) ReadByMailing(arg0 context.Context, arg1 *entity.Mailing) (entity.MailingChunks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadByMailing", arg0, arg1)
+	ret0, _ := ret[0].(entity.MailingChunks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadByMailing indicates an expected call of ReadByMailing.
+func (mr *MockChunkRepoMockRecorder) ReadByMailing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadByMailing", reflect.TypeOf((*MockChunkRepo)(nil).ReadByMailing), arg0, arg1)
+}
+
+// Seal mocks base method.
+func (m *MockChunkRepo) Seal(ctx context.Context, mailing *entity.Mailing, chunks int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Seal", ctx, mailing, chunks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Seal indicates an expected call of Seal.
+func (mr *MockChunkRepoMockRecorder) Seal(ctx, mailing, chunks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Seal", reflect.TypeOf((*MockChunkRepo)(nil).Seal), ctx, mailing, chunks)
+}
+
+// SetStatus mocks base method.
+func (m *MockChunkRepo) SetStatus(arg0 context.Context, arg1 *entity.MailingChunk) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockChunkRepoMockRecorder) SetStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockChunkRepo)(nil).SetStatus), arg0, arg1)
+}
+
+// MockPartitionRepo is a mock of PartitionRepo interface.
+type MockPartitionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockPartitionRepoMockRecorder
+}
+
+// MockPartitionRepoMockRecorder is the mock recorder for MockPartitionRepo.
+type MockPartitionRepoMockRecorder struct {
+	mock *MockPartitionRepo
+}
+
+// NewMockPartitionRepo creates a new mock instance.
+func NewMockPartitionRepo(ctrl *gomock.Controller) *MockPartitionRepo {
+	mock := &MockPartitionRepo{ctrl: ctrl}
+	mock.recorder = &MockPartitionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPartitionRepo) EXPECT() *MockPartitionRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPartitionRepo) Create(ctx context.Context, from time.Time, months int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, from, months)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPartitionRepoMockRecorder) Create(ctx, from, months interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPartitionRepo)(nil).Create), ctx, from, months)
+}
+
+// Drop mocks base method.
+func (m *MockPartitionRepo) Drop(arg0 context.Context, arg1 *entity.Partition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drop", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Drop indicates an expected call of Drop.
+func (mr *MockPartitionRepoMockRecorder) Drop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drop", reflect.TypeOf((*MockPartitionRepo)(nil).Drop), arg0, arg1)
+}
+
+// Export mocks base method.
+func (m *MockPartitionRepo) Export(arg0 context.Context, arg1 *entity.Partition, arg2 io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockPartitionRepoMockRecorder) Export(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockPartitionRepo)(nil).Export), arg0, arg1, arg2)
+}
+
+// List mocks base method.
+func (m *MockPartitionRepo) List(arg0 context.Context) (entity.Partitions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].(entity.Partitions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPartitionRepoMockRecorder) List(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPartitionRepo)(nil).List), arg0)
+}
+
+// Lock mocks base method.
+func (m *MockPartitionRepo) Lock(arg0 context.Context) (func(), bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", arg0)
+	ret0, _ := ret[0].(func())
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockPartitionRepoMockRecorder) Lock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockPartitionRepo)(nil).Lock), arg0)
+}
+
+// MockAuditRepo is a mock of AuditRepo interface.
+type MockAuditRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepoMockRecorder
+}
+
+// MockAuditRepoMockRecorder is the mock recorder for MockAuditRepo.
+type MockAuditRepoMockRecorder struct {
+	mock *MockAuditRepo
+}
+
+// NewMockAuditRepo creates a new mock instance.
+func NewMockAuditRepo(ctrl *gomock.Controller) *MockAuditRepo {
+	mock := &MockAuditRepo{ctrl: ctrl}
+	mock.recorder = &MockAuditRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepo) EXPECT() *MockAuditRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditRepo) Create(arg0 context.Context, arg1 *entity.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditRepo)(nil).Create), arg0, arg1)
+}
+
+// ReadPage mocks base method.
+func (m *MockAuditRepo) ReadPage(arg0 context.Context, arg1 *entity.AuditFilter) ([]*entity.AuditRecord, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadPage", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.AuditRecord)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReadPage indicates an expected call of ReadPage.
+func (mr *MockAuditRepoMockRecorder) ReadPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadPage", reflect.TypeOf((*MockAuditRepo)(nil).ReadPage), arg0, arg1)
+}
+
+// MockImportRepo is a mock of ImportRepo interface.
+type MockImportRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportRepoMockRecorder
+}
+
+// MockImportRepoMockRecorder is the mock recorder for MockImportRepo.
+type MockImportRepoMockRecorder struct {
+	mock *MockImportRepo
+}
+
+// NewMockImportRepo creates a new mock instance.
+func NewMockImportRepo(ctrl *gomock.Controller) *MockImportRepo {
+	mock := &MockImportRepo{ctrl: ctrl}
+	mock.recorder = &MockImportRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportRepo) EXPECT() *MockImportRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockImportRepo) Create(arg0 context.Context, arg1 *entity.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockImportRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockImportRepo)(nil).Create), arg0, arg1)
+}
+
+// CreateErrors mocks base method.
+func (m *MockImportRepo) CreateErrors(arg0 context.Context, arg1 []*entity.ImportRowError) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateErrors", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateErrors indicates an expected call of CreateErrors.
+func (mr *MockImportRepoMockRecorder) CreateErrors(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateErrors", reflect.TypeOf((*MockImportRepo)(nil).CreateErrors), arg0, arg1)
+}
+
+// Read mocks base method.
+func (m *MockImportRepo) Read(arg0 context.Context, arg1 *entity.ImportJob) (*entity.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", arg0, arg1)
+	ret0, _ := ret[0].(*entity.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockImportRepoMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockImportRepo)(nil).Read), arg0, arg1)
+}
+
+// ReadErrors mocks base method.
+func (m *MockImportRepo) ReadErrors(ctx context.Context, job *entity.ImportJob, afterID int64, limit int) ([]*entity.ImportRowError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadErrors", ctx, job, afterID, limit)
+	ret0, _ := ret[0].([]*entity.ImportRowError)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadErrors indicates an expected call of ReadErrors.
+func (mr *MockImportRepoMockRecorder) ReadErrors(ctx, job, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadErrors", reflect.TypeOf((*MockImportRepo)(nil).ReadErrors), ctx, job, afterID, limit)
+}
+
+// ReadUnfinished mocks base method.
+func (m *MockImportRepo) ReadUnfinished(arg0 context.Context) ([]*entity.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUnfinished", arg0)
+	ret0, _ := ret[0].([]*entity.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadUnfinished indicates an expected call of ReadUnfinished.
+func (mr *MockImportRepoMockRecorder) ReadUnfinished(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUnfinished", reflect.TypeOf((*MockImportRepo)(nil).ReadUnfinished), arg0)
+}
+
+// Update mocks base method.
+func (m *MockImportRepo) Update(arg0 context.Context, arg1 *entity.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockImportRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockImportRepo)(nil).Update), arg0, arg1)
+}
+
+// MockExportRepo is a mock of ExportRepo interface.
+type MockExportRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportRepoMockRecorder
+}
+
+// MockExportRepoMockRecorder is the mock recorder for MockExportRepo.
+type MockExportRepoMockRecorder struct {
+	mock *MockExportRepo
+}
+
+// NewMockExportRepo creates a new mock instance.
+func NewMockExportRepo(ctrl *gomock.Controller) *MockExportRepo {
+	mock := &MockExportRepo{ctrl: ctrl}
+	mock.recorder = &MockExportRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportRepo) EXPECT() *MockExportRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockExportRepo) Create(arg0 context.Context, arg1 *entity.ExportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockExportRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockExportRepo)(nil).Create), arg0, arg1)
+}
+
+// Read mocks base method.
+func (m *MockExportRepo) Read(arg0 context.Context, arg1 *entity.ExportJob) (*entity.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", arg0, arg1)
+	ret0, _ := ret[0].(*entity.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockExportRepoMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockExportRepo)(nil).Read), arg0, arg1)
+}
+
+// ReadUnfinished mocks base method.
+func (m *MockExportRepo) ReadUnfinished(arg0 context.Context) ([]*entity.ExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUnfinished", arg0)
+	ret0, _ := ret[0].([]*entity.ExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadUnfinished indicates an expected call of ReadUnfinished.
+func (mr *MockExportRepoMockRecorder) ReadUnfinished(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUnfinished", reflect.TypeOf((*MockExportRepo)(nil).ReadUnfinished), arg0)
+}
+
+// Update mocks base method.
+func (m *MockExportRepo) Update(arg0 context.Context, arg1 *entity.ExportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockExportRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockExportRepo)(nil).Update), arg0, arg1)
+}
+
+// MockLeaseRepo is a mock of LeaseRepo interface.
+type MockLeaseRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaseRepoMockRecorder
+}
+
+// MockLeaseRepoMockRecorder is the mock recorder for MockLeaseRepo.
+type MockLeaseRepoMockRecorder struct {
+	mock *MockLeaseRepo
+}
+
+// NewMockLeaseRepo creates a new mock instance.
+func NewMockLeaseRepo(ctrl *gomock.Controller) *MockLeaseRepo {
+	mock := &MockLeaseRepo{ctrl: ctrl}
+	mock.recorder = &MockLeaseRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaseRepo) EXPECT() *MockLeaseRepoMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockLeaseRepo) Acquire(ctx context.Context, mailing *entity.Mailing, chunk int) (func(), bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx, mailing, chunk)
+	ret0, _ := ret[0].(func())
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockLeaseRepoMockRecorder) Acquire(ctx, mailing, chunk interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockLeaseRepo)(nil).Acquire), ctx, mailing, chunk)
+}
+
+// MockGeneralProducer is a mock of GeneralProducer interface.
+type MockGeneralProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeneralProducerMockRecorder
+}
+
+// MockGeneralProducerMockRecorder is the mock recorder for MockGeneralProducer.
+type MockGeneralProducerMockRecorder struct {
+	mock *MockGeneralProducer
+}
+
+// NewMockGeneralProducer creates a new mock instance.
+func NewMockGeneralProducer(ctrl *gomock.Controller) *MockGeneralProducer {
+	mock := &MockGeneralProducer{ctrl: ctrl}
+	mock.recorder = &MockGeneralProducerMockRecorder{mock}
+	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
@@ -571,6 +1509,43 @@ func (mr *MockAdditionalProducerMockRecorder) Publish(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockAdditionalProducer)(nil).Publish), arg0, arg1)
 }
 
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventPublisherMockRecorder
+}
+
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
+}
+
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventPublisher) Publish(arg0 context.Context, arg1 *entity.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventPublisherMockRecorder) Publish(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventPublisher)(nil).Publish), arg0, arg1)
+}
+
 // MockSender is a mock of Sender interface.
 type MockSender struct {
 	ctrl     *gomock.Controller
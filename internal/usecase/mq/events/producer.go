@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+)
+
+// Producer publishes domain events to "<prefix>.<event type>" subjects,
+// e.g. "events.v1.client.created".
+type Producer struct {
+	pub broker.Publisher
+
+	prefix string
+}
+
+func New(pub broker.Publisher, prefix string) *Producer {
+	return &Producer{pub, prefix}
+}
+
+func (p *Producer) Publish(ctx context.Context, event *entity.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("events.Producer - Publish(): %w", err)
+	}
+
+	// JetStream drops duplicates of events with the same ID
+	env := broker.NewEnvelope(ctx, event.Type, event.Version)
+	env.ID = event.ID
+	env.CreatedAt = event.OccurredAt
+
+	err = p.pub.Publish(ctx, p.prefix+"."+event.Type, data, env.Header())
+	if err != nil {
+		return fmt.Errorf("events.Producer - Publish(): %w", err)
+	}
+
+	return nil
+}
@@ -5,17 +5,17 @@ import (
 	"fmt"
 
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
-	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
 )
 
 type AdditionalProducer struct {
-	conn *nats_server.Connection
+	pub broker.Publisher
 
 	subj string
 }
 
-func NewAdditional(conn *nats_server.Connection, subj string) *AdditionalProducer {
-	return &AdditionalProducer{conn, subj}
+func NewAdditional(pub broker.Publisher, subj string) *AdditionalProducer {
+	return &AdditionalProducer{pub, subj}
 }
 
 func (p *AdditionalProducer) Publish(ctx context.Context, mwc *entity.MailingWithClients) error {
@@ -24,7 +24,10 @@ func (p *AdditionalProducer) Publish(ctx context.Context, mwc *entity.MailingWit
 		return fmt.Errorf("AdditionalProducer - Publish(): %w", err)
 	}
 
-	err = p.conn.Publish(p.subj, data)
+	env := broker.NewEnvelope(ctx, entity.PayloadMailingWithClients, entity.PayloadVersion)
+	env.Attempt = mwc.Try + 1
+
+	err = p.pub.Publish(ctx, p.subj, data, header(env, mwc.Mailing))
 	if err != nil {
 		return fmt.Errorf("AdditionalProducer - Publish(): %w", err)
 	}
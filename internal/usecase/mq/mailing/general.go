@@ -5,17 +5,18 @@ import (
 	"fmt"
 
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
-	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server/mod"
 )
 
 type GeneralProducer struct {
-	conn *nats_server.Connection
+	pub broker.Publisher
 
 	subj string
 }
 
-func NewGeneral(conn *nats_server.Connection, subj string) *GeneralProducer {
-	return &GeneralProducer{conn, subj}
+func NewGeneral(pub broker.Publisher, subj string) *GeneralProducer {
+	return &GeneralProducer{pub, subj}
 }
 
 func (p *GeneralProducer) Publish(ctx context.Context, general *entity.Mailing) error {
@@ -24,10 +25,22 @@ func (p *GeneralProducer) Publish(ctx context.Context, general *entity.Mailing)
 		return fmt.Errorf("GeneralProducer - Publish(): %w", err)
 	}
 
-	err = p.conn.Publish(p.subj, data)
+	env := broker.NewEnvelope(ctx, entity.PayloadMailing, entity.PayloadVersion)
+
+	err = p.pub.Publish(ctx, p.subj, data, header(env, general))
 	if err != nil {
 		return fmt.Errorf("GeneralProducer - Publish(): %w", err)
 	}
 
 	return nil
 }
+
+// header returns envelope and priority headers of mailing message
+func header(env broker.Envelope, mailing *entity.Mailing) broker.Header {
+	h := env.Header()
+	if mailing.Priority != "" {
+		h.Set(mod.PriorityHeader, mailing.Priority)
+	}
+
+	return h
+}
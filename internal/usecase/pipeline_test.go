@@ -0,0 +1,219 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/transport/nats_rpc"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/mq/events"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/mq/mailing"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server/mod"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server/server"
+)
+
+const (
+	generalSubj    = "mailing.general"
+	additionalSubj = "mailing.additional"
+	group          = "workers"
+)
+
+// chunkRepo is ChunkRepo over map for pipeline test
+type chunkRepo struct {
+	mu     sync.Mutex
+	chunks map[int]*entity.MailingChunk
+	sealed int
+}
+
+func (r *chunkRepo) Create(_ context.Context, c *entity.MailingChunk) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chunk := *c
+	r.chunks[c.Seq] = &chunk
+	return nil
+}
+
+func (r *chunkRepo) SetStatus(_ context.Context, c *entity.MailingChunk) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.chunks[c.Seq].Status != entity.ChunkDone {
+		r.chunks[c.Seq].Status = c.Status
+	}
+	return nil
+}
+
+func (r *chunkRepo) Read(_ context.Context, c *entity.MailingChunk) (*entity.MailingChunk, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chunk := *r.chunks[c.Seq]
+	return &chunk, nil
+}
+
+func (r *chunkRepo) ReadByMailing(context.Context, *entity.Mailing) (entity.MailingChunks, error) {
+	return nil, nil
+}
+
+func (r *chunkRepo) Seal(_ context.Context, _ *entity.Mailing, chunks int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sealed = chunks
+	return nil
+}
+
+func (r *chunkRepo) Completed(context.Context, *entity.Mailing) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	done := 0
+	for _, c := range r.chunks {
+		if c.Status == entity.ChunkDone {
+			done++
+		}
+	}
+	return r.sealed > 0 && r.sealed == done, nil
+}
+
+// TestPipeline passes mailing through publish -> fan-out -> consume -> send
+// with in-memory broker. Every client is sent in its own chunk, failed send
+// is published again with the same chunk.
+func TestPipeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	var (
+		mailingRepo = NewMockMailingRepo(ctrl)
+		messageRepo = NewMockMessageRepo(ctrl)
+		clientRepo  = NewMockClientRepo(ctrl)
+		leaseRepo   = NewMockLeaseRepo(ctrl)
+		auditRepo   = NewMockAuditRepo(ctrl)
+		sender      = NewMockSender(ctrl)
+		chunks      = &chunkRepo{chunks: make(map[int]*entity.MailingChunk)}
+	)
+
+	now := time.Now()
+	m := &entity.Mailing{
+		ID:            1,
+		MessageText:   "Hello",
+		DateTimeStart: now.Add(-time.Minute),
+		DateTimeEnd:   now.Add(time.Hour),
+		IntervalStart: now.Add(-time.Hour),
+		IntervalEnd:   now.Add(time.Hour),
+	}
+	clients := entity.Clients{
+		{ID: 1, PhoneNumber: 70000000001},
+		{ID: 2, PhoneNumber: 70000000002},
+	}
+
+	b := broker.NewMemory()
+	// Queues of group keep messages published before workers subscription
+	for _, subj := range []string{generalSubj, additionalSubj} {
+		_, err := b.Subscribe(subj, group)
+		assert.Equal(t, err, nil)
+	}
+	completed, _ := b.Subscribe("events.v1."+entity.EventMailingCompleted, "test")
+
+	var (
+		eventProducer = events.New(b, "events.v1")
+		mailingUC     = usecase.NewMailing(mailingRepo, messageRepo, auditRepo,
+			mailing.NewGeneral(b, generalSubj), eventProducer, noTx{})
+		consumerUC = usecase.NewConsumer(messageRepo, clientRepo, mailingRepo, chunks, leaseRepo,
+			sender, mailing.NewAdditional(b, additionalSubj), eventProducer, 1)
+	)
+
+	// The second client is unavailable at first attempt
+	var (
+		mu    sync.Mutex
+		tries = make(map[int64]int)
+	)
+	sender.EXPECT().Send(gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(_ context.Context, req *entity.SendRequest) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			tries[req.ID]++
+			if req.ID == 2 && tries[req.ID] == 1 {
+				return errors.New("unavailable")
+			}
+			return nil
+		})
+
+	clientRepo.EXPECT().ReadPage(gomock.Any(), gomock.Any(), gomock.Any(), 1).Times(3).
+		DoAndReturn(func(_ context.Context, _ *entity.Mailing, afterID int64, limit int) (entity.Clients, error) {
+			var page entity.Clients
+			for _, c := range clients {
+				if c.ID > afterID && len(page) < limit {
+					page = append(page, c)
+				}
+			}
+			return page, nil
+		})
+
+	mailingRepo.EXPECT().Create(gomock.Any(), m).Return(nil)
+	auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mailingRepo.EXPECT().Read(gomock.Any(), gomock.Any()).Return(m, nil).Times(4)
+	mailingRepo.EXPECT().ReadWithMessages(gomock.Any(), gomock.Any()).
+		Return(&entity.MailingStats{MailingID: m.ID}, nil).Times(4)
+	leaseRepo.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(func() {}, true, nil).Times(4)
+
+	var messages = make(chan *entity.Message, 3)
+	messageRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(_ context.Context, msg *entity.Message) error {
+			messages <- msg
+			return nil
+		})
+
+	s := server.New(b, nats_rpc.NewRouter(consumerUC, generalSubj, additionalSubj), mod.TaskType,
+		server.Queue(group))
+	go s.StartWorkers()
+
+	err := mailingUC.Add(context.Background(), m)
+	assert.Equal(t, err, nil)
+
+	var sent, failed int
+	for i := 0; i < 3; i++ {
+		select {
+		case msg := <-messages:
+			if msg.DeliveryStatus {
+				sent++
+			} else {
+				failed++
+				assert.Equal(t, msg.ClientID, int64(2))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("messages weren't sent: %d of 3", i)
+		}
+	}
+	assert.Equal(t, sent, 2)
+	assert.Equal(t, failed, 1)
+
+	// Mailing is completed when both chunks are done
+	msgs, err := completed.Fetch(context.Background(), 2, 5*time.Second)
+	assert.Equal(t, err, nil)
+	assert.NotEqual(t, len(msgs), 0)
+	assert.Equal(t, chunks.chunks[1].Status, entity.ChunkDone)
+	assert.Equal(t, chunks.chunks[2].Status, entity.ChunkDone)
+
+	// Messages are acknowledged after handling
+	deadline := time.Now().Add(5 * time.Second)
+	for b.Pending(generalSubj, group)+b.Pending(additionalSubj, group) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("messages weren't acknowledged")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Equal(t, s.Shutdown(ctx), nil)
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+type AuditRepo struct {
+	store *Store
+}
+
+func NewAudit(store *Store) *AuditRepo {
+	return &AuditRepo{store}
+}
+
+// Create inserts record, diff isn't changed after insertion, so it isn't copied
+func (r *AuditRepo) Create(ctx context.Context, record *entity.AuditRecord) error {
+	if err := checkEnum("action", record.Action, auditActions); err != nil {
+		return fmt.Errorf("AuditRepo - Create(): %w", err)
+	}
+	if err := checkEnum("entity", record.Entity, auditEntities); err != nil {
+		return fmt.Errorf("AuditRepo - Create(): %w", err)
+	}
+
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	db.auditSeq++
+	record.ID = db.auditSeq
+
+	rec := *record
+	rec.CreatedAt = stamp(rec.CreatedAt)
+	set(db, db.audit, rec.ID, rec)
+
+	return nil
+}
+
+// ReadPage returns page of records by filter and cursor of the next page.
+// Cursor is empty on the last page.
+func (r *AuditRepo) ReadPage(ctx context.Context, filter *entity.AuditFilter) (
+	[]*entity.AuditRecord, string, error,
+) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	var records []*entity.AuditRecord
+	for _, rec := range db.audit {
+		if filter.Entity != "" && rec.Entity != filter.Entity {
+			continue
+		}
+		if filter.EntityID != 0 && rec.EntityID != filter.EntityID {
+			continue
+		}
+		if filter.Actor != "" && rec.Actor != filter.Actor {
+			continue
+		}
+		if !filter.From.IsZero() && rec.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !rec.CreatedAt.Before(filter.To) {
+			continue
+		}
+		rec := rec
+		records = append(records, &rec)
+	}
+
+	records, next, err := page(records, &filter.Page, func(r *entity.AuditRecord) (time.Time, int64) {
+		return r.SortTime(filter.Sort), r.ID
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("AuditRepo - ReadPage(): %w", err)
+	}
+
+	return records, next, nil
+}
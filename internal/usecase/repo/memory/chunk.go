@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// ChunkRepo tracks progress of mailing audience chunks
+type ChunkRepo struct {
+	store *Store
+}
+
+func NewChunk(store *Store) *ChunkRepo {
+	return &ChunkRepo{store}
+}
+
+// Create inserts chunk. Existing chunk with the same seq isn't changed.
+func (r *ChunkRepo) Create(ctx context.Context, chunk *entity.MailingChunk) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	if _, ok := db.mailings[chunk.MailingID]; !ok {
+		return fmt.Errorf("ChunkRepo - Create(): %w: mailing %d doesn't exist", ErrConstraint, chunk.MailingID)
+	}
+	if err := checkEnum("status", chunk.Status, chunkStatuses); err != nil {
+		return fmt.Errorf("ChunkRepo - Create(): %w", err)
+	}
+
+	key := chunkKey{chunk.MailingID, chunk.Seq}
+	if _, ok := db.chunks[key]; !ok {
+		set(db, db.chunks, key, *chunk)
+	}
+
+	return nil
+}
+
+// SetStatus moves chunk status forward: created, published, done.
+// Chunk could be done before fan-out marks it published, so status
+// isn't moved back.
+func (r *ChunkRepo) SetStatus(ctx context.Context, chunk *entity.MailingChunk) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	next := slices.Index(chunkStatuses, chunk.Status)
+	if next < 0 {
+		return fmt.Errorf("ChunkRepo - SetStatus(): %w", checkEnum("status", chunk.Status, chunkStatuses))
+	}
+
+	key := chunkKey{chunk.MailingID, chunk.Seq}
+	c, ok := db.chunks[key]
+	if ok && slices.Index(chunkStatuses, c.Status) < next {
+		c.Status = chunk.Status
+		set(db, db.chunks, key, c)
+	}
+
+	return nil
+}
+
+func (r *ChunkRepo) Read(ctx context.Context, chunk *entity.MailingChunk) (*entity.MailingChunk, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	c, ok := db.chunks[chunkKey{chunk.MailingID, chunk.Seq}]
+	if !ok {
+		return nil, fmt.Errorf("ChunkRepo - Read(): chunk %d of mailing %d: %w",
+			chunk.Seq, chunk.MailingID, entity.ErrNotFound)
+	}
+
+	return &c, nil
+}
+
+// ReadByMailing returns chunks of mailing ordered by seq
+func (r *ChunkRepo) ReadByMailing(ctx context.Context, mailing *entity.Mailing) (entity.MailingChunks, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	var cs entity.MailingChunks
+	for k, c := range db.chunks {
+		if k.mailingID == mailing.ID {
+			c := c
+			cs = append(cs, &c)
+		}
+	}
+	sort.Slice(cs, func(i, j int) bool { return cs[i].Seq < cs[j].Seq })
+
+	return cs, nil
+}
+
+// Seal marks fan-out of mailing finished with chunks count
+func (r *ChunkRepo) Seal(ctx context.Context, mailing *entity.Mailing, chunks int) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	if _, ok := db.mailings[mailing.ID]; !ok {
+		return fmt.Errorf("ChunkRepo - Seal(): %w: mailing %d doesn't exist", ErrConstraint, mailing.ID)
+	}
+	set(db, db.fanouts, mailing.ID, chunks)
+
+	return nil
+}
+
+// Completed reports whether fan-out of mailing is sealed and all its chunks are done
+func (r *ChunkRepo) Completed(ctx context.Context, mailing *entity.Mailing) (bool, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	chunks, ok := db.fanouts[mailing.ID]
+	if !ok {
+		return false, nil
+	}
+
+	done := 0
+	for k, c := range db.chunks {
+		if k.mailingID == mailing.ID && c.Status == entity.ChunkDone {
+			done++
+		}
+	}
+
+	return done == chunks, nil
+}
@@ -0,0 +1,255 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+type ClientRepo struct {
+	store *Store
+}
+
+func NewClient(store *Store) *ClientRepo {
+	return &ClientRepo{store}
+}
+
+func (r *ClientRepo) Create(ctx context.Context, client *entity.Client) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	c := *client
+	if err := db.checkClient(&c); err != nil {
+		return fmt.Errorf("ClientRepo - Create(): %w", err)
+	}
+
+	db.clientSeq++
+	c.ID = db.clientSeq
+	c.Version = 1
+	set(db, db.clients, c.ID, clientRow{Client: c})
+	client.ID, client.Version = c.ID, c.Version
+
+	return nil
+}
+
+// Update changes client of the same version and increments its version.
+// entity.ErrConflict is returned if version differs or client is deleted.
+func (r *ClientRepo) Update(ctx context.Context, client *entity.Client) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	c, ok := db.clients[client.ID]
+	if !ok || c.deletedAt != nil || c.Version != client.Version {
+		return fmt.Errorf("ClientRepo - Update(): client %d: %w", client.ID, entity.ErrConflict)
+	}
+
+	if client.PhoneNumber != 0 {
+		c.PhoneNumber = client.PhoneNumber
+	}
+	if client.MobileOperator != 0 {
+		c.MobileOperator = client.MobileOperator
+	}
+	if client.Tag != "" {
+		c.Tag = client.Tag
+	}
+	if client.TimeZone != 0 {
+		c.TimeZone = client.TimeZone
+	}
+
+	if err := db.checkClient(&c.Client); err != nil {
+		return fmt.Errorf("ClientRepo - Update(): %w", err)
+	}
+	c.Version++
+	set(db, db.clients, c.ID, c)
+	client.Version = c.Version
+
+	return nil
+}
+
+// Delete marks client deleted, so it's excluded from audiences.
+// Messages of client are kept. Version is checked like by Update.
+func (r *ClientRepo) Delete(ctx context.Context, client *entity.Client) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	c, ok := db.clients[client.ID]
+	if !ok || c.deletedAt != nil || c.Version != client.Version {
+		return fmt.Errorf("ClientRepo - Delete(): client %d: %w", client.ID, entity.ErrConflict)
+	}
+
+	t := now()
+	c.deletedAt = &t
+	c.Version++
+	set(db, db.clients, c.ID, c)
+	client.Version = c.Version
+
+	return nil
+}
+
+// Restore returns deleted client. entity.ErrNotFound is returned if client
+// isn't deleted. Restore fails if phone number is taken by another client.
+func (r *ClientRepo) Restore(ctx context.Context, client *entity.Client) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	c, ok := db.clients[client.ID]
+	if !ok || c.deletedAt == nil {
+		return fmt.Errorf("ClientRepo - Restore(): client %d: %w", client.ID, entity.ErrNotFound)
+	}
+
+	if err := db.checkClient(&c.Client); err != nil {
+		return fmt.Errorf("ClientRepo - Restore(): %w", err)
+	}
+	c.deletedAt = nil
+	c.Version++
+	set(db, db.clients, c.ID, c)
+
+	return nil
+}
+
+func (r *ClientRepo) Read(ctx context.Context, client *entity.Client) (*entity.Client, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	c, ok := db.clients[client.ID]
+	if !ok || c.deletedAt != nil {
+		return nil, fmt.Errorf("ClientRepo - Read(): client %d: %w", client.ID, entity.ErrNotFound)
+	}
+
+	read := c.Client
+	return &read, nil
+}
+
+// ReadPage returns up to limit clients of mailing audience with ID
+// greater than afterID ordered by ID
+func (r *ClientRepo) ReadPage(ctx context.Context, mailing *entity.Mailing, afterID int64, limit int) (
+	entity.Clients, error,
+) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	return db.audience(mailing, afterID, limit), nil
+}
+
+// Upsert creates clients, clients of phone numbers taken by not deleted
+// ones are updated and their versions are incremented. Clients are
+// upserted all or none like in one statement.
+func (r *ClientRepo) Upsert(ctx context.Context, clients entity.Clients) (int64, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	taken := make(map[int64]int64)
+	for id, c := range db.clients {
+		if c.deletedAt == nil {
+			taken[c.PhoneNumber] = id
+		}
+	}
+
+	rows := make(map[int64]clientRow, len(clients))
+	seq := db.clientSeq
+	for _, client := range clients {
+		if err := checkEnum("tag", client.Tag, entity.ClientTags); err != nil {
+			return 0, fmt.Errorf("ClientRepo - Upsert(): %w", err)
+		}
+
+		c, ok := db.clients[taken[client.PhoneNumber]]
+		if !ok {
+			seq++
+			c = clientRow{Client: entity.Client{ID: seq, PhoneNumber: client.PhoneNumber}}
+		}
+		c.MobileOperator = client.MobileOperator
+		c.Tag = client.Tag
+		c.TimeZone = client.TimeZone
+		c.Version++
+		rows[c.ID] = c
+	}
+
+	db.clientSeq = seq
+	maps.Copy(db.clients, rows)
+
+	return int64(len(rows)), nil
+}
+
+// Export calls f for every client of mailing audience ordered by ID.
+// Clients are copied first, so store isn't locked while f is called.
+func (r *ClientRepo) Export(ctx context.Context, mailing *entity.Mailing, f func(*entity.Client) error) error {
+	db, unlock := r.store.lock(ctx)
+	cs := db.members(mailing)
+	unlock()
+
+	for i := range cs {
+		if err := f(&cs[i]); err != nil {
+			return fmt.Errorf("ClientRepo - Export(): %w", err)
+		}
+	}
+
+	return nil
+}
+
+// audience returns up to limit clients selected by mailing filter with ID
+// greater than afterID ordered by ID. Only ID, phone and time zone are set
+// like in PostgreSQL repository.
+func (db *state) audience(mailing *entity.Mailing, afterID int64, limit int) entity.Clients {
+	var cs entity.Clients
+	for _, c := range db.members(mailing) {
+		if c.ID <= afterID {
+			continue
+		}
+		if len(cs) == limit {
+			break
+		}
+		cs = append(cs, &entity.Client{ID: c.ID, PhoneNumber: c.PhoneNumber, TimeZone: c.TimeZone})
+	}
+
+	return cs
+}
+
+// members returns clients of mailing audience ordered by ID, deleted
+// clients are excluded
+func (db *state) members(mailing *entity.Mailing) []entity.Client {
+	// Code is compared as INTEGER column
+	code, _ := strconv.Atoi(mailing.MobileOperator)
+
+	var cs []entity.Client
+	for _, c := range db.clients {
+		if c.deletedAt != nil {
+			continue
+		}
+
+		switch mailing.FilterChoice {
+		case "code":
+			if c.MobileOperator != code {
+				continue
+			}
+		case "tag":
+			if c.Tag != mailing.Tag {
+				continue
+			}
+		}
+
+		cs = append(cs, c.Client)
+	}
+
+	sort.Slice(cs, func(i, j int) bool { return cs[i].ID < cs[j].ID })
+
+	return cs
+}
+
+// checkClient checks tag and uniqueness of phone number among not deleted clients
+func (db *state) checkClient(c *entity.Client) error {
+	if err := checkEnum("tag", c.Tag, entity.ClientTags); err != nil {
+		return err
+	}
+
+	for _, other := range db.clients {
+		if other.ID != c.ID && other.deletedAt == nil && other.PhoneNumber == c.PhoneNumber {
+			return fmt.Errorf("%w: phone number %d is taken", ErrConstraint, c.PhoneNumber)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+type ExportRepo struct {
+	store *Store
+}
+
+func NewExport(store *Store) *ExportRepo {
+	return &ExportRepo{store}
+}
+
+func (r *ExportRepo) Create(ctx context.Context, job *entity.ExportJob) error {
+	if err := checkEnum("status", job.Status, exportStatuses); err != nil {
+		return fmt.Errorf("ExportRepo - Create(): %w", err)
+	}
+
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	db.exportSeq++
+	job.ID = db.exportSeq
+
+	j := entity.ExportJob{
+		ID:        job.ID,
+		Request:   job.Request,
+		Status:    job.Status,
+		CreatedAt: stamp(job.CreatedAt),
+	}
+	set(db, db.exports, j.ID, j)
+
+	return nil
+}
+
+// Update sets status, number of rows, file and error of job
+func (r *ExportRepo) Update(ctx context.Context, job *entity.ExportJob) error {
+	if err := checkEnum("status", job.Status, exportStatuses); err != nil {
+		return fmt.Errorf("ExportRepo - Update(): %w", err)
+	}
+
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	j, ok := db.exports[job.ID]
+	if !ok {
+		return fmt.Errorf("ExportRepo - Update(): job %d: %w", job.ID, entity.ErrNotFound)
+	}
+
+	j.Status = job.Status
+	j.Rows = job.Rows
+	j.File = job.File
+	j.Error = job.Error
+	j.FinishedAt = nil
+	if job.FinishedAt != nil {
+		t := stamp(*job.FinishedAt)
+		j.FinishedAt = &t
+	}
+	set(db, db.exports, j.ID, j)
+
+	return nil
+}
+
+func (r *ExportRepo) Read(ctx context.Context, job *entity.ExportJob) (*entity.ExportJob, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	j, ok := db.exports[job.ID]
+	if !ok {
+		return nil, fmt.Errorf("ExportRepo - Read(): job %d: %w", job.ID, entity.ErrNotFound)
+	}
+
+	return &j, nil
+}
+
+// ReadUnfinished returns pending and running jobs ordered by ID
+func (r *ExportRepo) ReadUnfinished(ctx context.Context) ([]*entity.ExportJob, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	var jobs []*entity.ExportJob
+	for _, j := range db.exports {
+		if j.Status == entity.ExportPending || j.Status == entity.ExportRunning {
+			j := j
+			jobs = append(jobs, &j)
+		}
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].ID < jobs[k].ID })
+
+	return jobs, nil
+}
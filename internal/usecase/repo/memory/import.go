@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+type ImportRepo struct {
+	store *Store
+}
+
+func NewImport(store *Store) *ImportRepo {
+	return &ImportRepo{store}
+}
+
+func (r *ImportRepo) Create(ctx context.Context, job *entity.ImportJob) error {
+	if err := checkImport(job); err != nil {
+		return fmt.Errorf("ImportRepo - Create(): %w", err)
+	}
+
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	db.importSeq++
+	job.ID = db.importSeq
+
+	j := entity.ImportJob{
+		ID:        job.ID,
+		Format:    job.Format,
+		Status:    job.Status,
+		Actor:     job.Actor,
+		RequestID: job.RequestID,
+		CreatedAt: stamp(job.CreatedAt),
+	}
+	set(db, db.imports, j.ID, j)
+
+	return nil
+}
+
+// Update sets status, counters and error of job
+func (r *ImportRepo) Update(ctx context.Context, job *entity.ImportJob) error {
+	if err := checkImport(job); err != nil {
+		return fmt.Errorf("ImportRepo - Update(): %w", err)
+	}
+
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	j, ok := db.imports[job.ID]
+	if !ok {
+		return fmt.Errorf("ImportRepo - Update(): job %d: %w", job.ID, entity.ErrNotFound)
+	}
+
+	j.Status = job.Status
+	j.Total, j.Imported, j.Failed = job.Total, job.Imported, job.Failed
+	j.Error = job.Error
+	j.FinishedAt = nil
+	if job.FinishedAt != nil {
+		t := stamp(*job.FinishedAt)
+		j.FinishedAt = &t
+	}
+	set(db, db.imports, j.ID, j)
+
+	return nil
+}
+
+func (r *ImportRepo) Read(ctx context.Context, job *entity.ImportJob) (*entity.ImportJob, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	j, ok := db.imports[job.ID]
+	if !ok {
+		return nil, fmt.Errorf("ImportRepo - Read(): job %d: %w", job.ID, entity.ErrNotFound)
+	}
+
+	return &j, nil
+}
+
+// CreateErrors inserts invalid rows, IDs of errors aren't set
+func (r *ImportRepo) CreateErrors(ctx context.Context, errs []*entity.ImportRowError) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	for _, e := range errs {
+		if _, ok := db.imports[e.JobID]; !ok {
+			return fmt.Errorf("ImportRepo - CreateErrors(): %w: job %d doesn't exist", ErrConstraint, e.JobID)
+		}
+	}
+
+	for _, e := range errs {
+		db.importErrorSeq++
+		row := *e
+		row.ID = db.importErrorSeq
+		set(db, db.importErrors, row.ID, row)
+	}
+
+	return nil
+}
+
+// ReadErrors returns up to limit errors of job with ID greater than afterID ordered by ID
+func (r *ImportRepo) ReadErrors(ctx context.Context, job *entity.ImportJob, afterID int64, limit int) (
+	[]*entity.ImportRowError, error,
+) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	var errs []*entity.ImportRowError
+	for _, e := range db.importErrors {
+		if e.JobID == job.ID && e.ID > afterID {
+			e := e
+			errs = append(errs, &e)
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].ID < errs[j].ID })
+	if len(errs) > limit {
+		errs = errs[:limit]
+	}
+
+	return errs, nil
+}
+
+// checkImport checks enums of job like schema
+func checkImport(job *entity.ImportJob) error {
+	if err := checkEnum("format", job.Format, importFormats); err != nil {
+		return err
+	}
+
+	return checkEnum("status", job.Status, importStatuses)
+}
+
+// ReadUnfinished returns pending and running jobs ordered by ID
+func (r *ImportRepo) ReadUnfinished(ctx context.Context) ([]*entity.ImportJob, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	var jobs []*entity.ImportJob
+	for _, j := range db.imports {
+		if j.Status == entity.ImportPending || j.Status == entity.ImportRunning {
+			j := j
+			jobs = append(jobs, &j)
+		}
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].ID < jobs[k].ID })
+
+	return jobs, nil
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// LeaseRepo grants ownership of mailing chunk between workers of one
+// process. Chunk 0 is fan-out of mailing.
+type LeaseRepo struct {
+	store *Store
+}
+
+func NewLease(store *Store) *LeaseRepo {
+	return &LeaseRepo{store}
+}
+
+// Acquire tries to lock mailing chunk without waiting. If chunk is locked
+// by another worker ok is false. Release must be called to unlock it.
+func (r *LeaseRepo) Acquire(_ context.Context, mailing *entity.Mailing, chunk int) (
+	release func(), ok bool, err error,
+) {
+	release, ok = r.store.tryLock(fmt.Sprintf("lease:%d:%d", mailing.ID, chunk))
+
+	return release, ok, nil
+}
+
+// tryLock takes lock of key without waiting, release unlocks it
+func (s *Store) tryLock(key string) (release func(), ok bool) {
+	s.locksMu.Lock()
+	defer s.locksMu.Unlock()
+
+	if s.locks[key] {
+		return nil, false
+	}
+	s.locks[key] = true
+
+	return func() {
+		s.locksMu.Lock()
+		defer s.locksMu.Unlock()
+
+		delete(s.locks, key)
+	}, true
+}
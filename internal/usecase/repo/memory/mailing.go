@@ -0,0 +1,317 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+type MailingRepo struct {
+	store *Store
+}
+
+func NewMailing(store *Store) *MailingRepo {
+	return &MailingRepo{store}
+}
+
+func (r *MailingRepo) Create(ctx context.Context, mailing *entity.Mailing) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	m := *mailing
+	if m.Priority == "" {
+		m.Priority = entity.PriorityBulk
+	}
+	if err := checkMailing(&m); err != nil {
+		return fmt.Errorf("MailingRepo - Create(): %w", err)
+	}
+
+	db.mailingSeq++
+	m.ID = db.mailingSeq
+	m.Version = 1
+	m.DeletedAt = nil
+	m.DateTimeStart, m.DateTimeEnd = stamp(m.DateTimeStart), stamp(m.DateTimeEnd)
+	m.IntervalStart, m.IntervalEnd = stamp(m.IntervalStart), stamp(m.IntervalEnd)
+	set(db, db.mailings, m.ID, m)
+	mailing.ID, mailing.Version = m.ID, m.Version
+
+	return nil
+}
+
+// Update changes mailing of the same version and increments its version.
+// entity.ErrConflict is returned if version differs or mailing is deleted.
+func (r *MailingRepo) Update(ctx context.Context, mailing *entity.Mailing) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	m, ok := db.mailings[mailing.ID]
+	if !ok || m.DeletedAt != nil || m.Version != mailing.Version {
+		return fmt.Errorf("MailingRepo - Update(): mailing %d: %w", mailing.ID, entity.ErrConflict)
+	}
+
+	if mailing.MessageText != "" {
+		m.MessageText = mailing.MessageText
+	}
+	if mailing.MobileOperator != "" {
+		m.MobileOperator = mailing.MobileOperator
+	}
+	if mailing.Tag != "" {
+		m.Tag = mailing.Tag
+	}
+	if mailing.FilterChoice != "" {
+		m.FilterChoice = mailing.FilterChoice
+	}
+	if !mailing.DateTimeStart.IsZero() {
+		m.DateTimeStart = stamp(mailing.DateTimeStart)
+	}
+	if !mailing.DateTimeEnd.IsZero() {
+		m.DateTimeEnd = stamp(mailing.DateTimeEnd)
+	}
+	if !mailing.IntervalStart.IsZero() {
+		m.IntervalStart = stamp(mailing.IntervalStart)
+	}
+	if !mailing.IntervalEnd.IsZero() {
+		m.IntervalEnd = stamp(mailing.IntervalEnd)
+	}
+	if mailing.Priority != "" {
+		m.Priority = mailing.Priority
+	}
+
+	if err := checkMailing(&m); err != nil {
+		return fmt.Errorf("MailingRepo - Update(): %w", err)
+	}
+	m.Version++
+	set(db, db.mailings, m.ID, m)
+	mailing.Version = m.Version
+
+	return nil
+}
+
+// Delete marks mailing deleted, its messages are kept.
+// Version is checked like by Update.
+func (r *MailingRepo) Delete(ctx context.Context, mailing *entity.Mailing) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	m, ok := db.mailings[mailing.ID]
+	if !ok || m.DeletedAt != nil || m.Version != mailing.Version {
+		return fmt.Errorf("MailingRepo - Delete(): mailing %d: %w", mailing.ID, entity.ErrConflict)
+	}
+
+	t := now()
+	m.DeletedAt = &t
+	m.Version++
+	set(db, db.mailings, m.ID, m)
+	mailing.Version = m.Version
+
+	return nil
+}
+
+// Restore returns deleted or archived mailing with its messages.
+// entity.ErrNotFound is returned if mailing is neither deleted nor archived.
+func (r *MailingRepo) Restore(ctx context.Context, mailing *entity.Mailing) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	if m, ok := db.mailings[mailing.ID]; ok {
+		if m.DeletedAt == nil {
+			return fmt.Errorf("MailingRepo - Restore(): mailing %d: %w", mailing.ID, entity.ErrNotFound)
+		}
+		m.DeletedAt = nil
+		m.Version++
+		set(db, db.mailings, m.ID, m)
+		return nil
+	}
+
+	m, ok := db.mailingArchive[mailing.ID]
+	if !ok {
+		return fmt.Errorf("MailingRepo - Restore(): mailing %d: %w", mailing.ID, entity.ErrNotFound)
+	}
+
+	// Restored mailing isn't deleted
+	m.DeletedAt = nil
+	set(db, db.mailings, m.ID, m)
+	del(db, db.mailingArchive, m.ID)
+
+	// Restored messages are counted again like inserted ones
+	for id, msg := range db.messageArchive {
+		if msg.MailingID == m.ID {
+			set(db, db.messages, id, msg)
+			db.addStats(msg.Message)
+			del(db, db.messageArchive, id)
+		}
+	}
+
+	return nil
+}
+
+// Archive moves up to limit mailings finished before time with their
+// messages into archive. It returns IDs of archived mailings.
+func (r *MailingRepo) Archive(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	var ids []int64
+	for id, m := range db.mailings {
+		if !m.DateTimeEnd.After(stamp(before)) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	archived := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		archived[id] = true
+
+		set(db, db.mailingArchive, id, db.mailings[id])
+		del(db, db.mailings, id)
+		del(db, db.stats, id)
+		del(db, db.fanouts, id)
+	}
+
+	for id, msg := range db.messages {
+		if archived[msg.MailingID] {
+			set(db, db.messageArchive, id, msg)
+			del(db, db.messages, id)
+		}
+	}
+
+	for k := range db.chunks {
+		if archived[k.mailingID] {
+			del(db, db.chunks, k)
+		}
+	}
+
+	return ids, nil
+}
+
+// ReadWithMessages returns stats of mailing from rollup,
+// counters are zero if mailing doesn't have messages
+func (r *MailingRepo) ReadWithMessages(ctx context.Context, mailing *entity.Mailing) (*entity.MailingStats, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	if !db.activeMailing(mailing.ID) {
+		return nil, fmt.Errorf("MailingRepo - ReadWithMessages(): mailing %d: %w", mailing.ID, entity.ErrNotFound)
+	}
+
+	return db.mailingStats(mailing.ID), nil
+}
+
+// ReadStatsPage returns stats of page of mailings and cursor of the next page.
+// Cursor is empty on the last page.
+func (r *MailingRepo) ReadStatsPage(ctx context.Context, filter *entity.MailingFilter) (
+	[]*entity.MailingStats, string, error,
+) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	ms, next, err := page(db.filterMailings(filter), &filter.Page, mailingKey(filter.Sort))
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+	}
+
+	stats := make([]*entity.MailingStats, 0, len(ms))
+	for _, m := range ms {
+		stats = append(stats, db.mailingStats(m.ID))
+	}
+
+	return stats, next, nil
+}
+
+// Read returns mailing if it isn't deleted
+func (r *MailingRepo) Read(ctx context.Context, mailing *entity.Mailing) (*entity.Mailing, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	m, ok := db.mailings[mailing.ID]
+	if !ok || m.DeletedAt != nil {
+		return nil, fmt.Errorf("MailingRepo - Read(): mailing %d: %w", mailing.ID, entity.ErrNotFound)
+	}
+
+	return &m, nil
+}
+
+// ReadPage returns page of mailings by filter and cursor of the next page.
+// Cursor is empty on the last page.
+func (r *MailingRepo) ReadPage(ctx context.Context, filter *entity.MailingFilter) (
+	entity.Mailings, string, error,
+) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	ms, next, err := page(db.filterMailings(filter), &filter.Page, mailingKey(filter.Sort))
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
+	}
+
+	return ms, next, nil
+}
+
+// filterMailings returns copies of mailings selected by filter except page
+func (db *state) filterMailings(filter *entity.MailingFilter) entity.Mailings {
+	t := now()
+
+	var ms entity.Mailings
+	for _, m := range db.mailings {
+		if m.DeletedAt != nil && !filter.WithDeleted {
+			continue
+		}
+		if !filter.From.IsZero() && m.DateTimeStart.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !m.DateTimeStart.Before(filter.To) {
+			continue
+		}
+
+		switch filter.Status {
+		case entity.MailingScheduled:
+			if !m.DateTimeStart.After(t) {
+				continue
+			}
+		case entity.MailingActive:
+			if m.DateTimeStart.After(t) || !m.DateTimeEnd.After(t) {
+				continue
+			}
+		case entity.MailingFinished:
+			if m.DateTimeEnd.After(t) {
+				continue
+			}
+		}
+
+		m := m
+		ms = append(ms, &m)
+	}
+
+	return ms
+}
+
+// mailingKey returns position of mailing in page of sort
+func mailingKey(sort string) func(*entity.Mailing) (time.Time, int64) {
+	return func(m *entity.Mailing) (time.Time, int64) {
+		return m.SortTime(sort), m.ID
+	}
+}
+
+// mailingStats returns stats of mailing, bounds of messages are zero
+// if mailing doesn't have them
+func (db *state) mailingStats(id int64) *entity.MailingStats {
+	st, ok := db.stats[id]
+	if !ok {
+		return &entity.MailingStats{MailingID: id}
+	}
+
+	return &entity.MailingStats{
+		MailingID:     id,
+		DateTimeStart: st.first,
+		DateTimeEnd:   st.last,
+		Succesed:      st.succeeded,
+		Failed:        st.failed,
+	}
+}
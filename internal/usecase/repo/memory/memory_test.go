@@ -0,0 +1,27 @@
+package memory_test
+
+import (
+	"testing"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/memory"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/repotest"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(*testing.T) *repotest.Repos {
+		store := memory.NewStore()
+
+		return &repotest.Repos{
+			Client:    memory.NewClient(store),
+			Mailing:   memory.NewMailing(store),
+			Message:   memory.NewMessage(store),
+			Chunk:     memory.NewChunk(store),
+			Partition: memory.NewPartition(store),
+			Lease:     memory.NewLease(store),
+			Audit:     memory.NewAudit(store),
+			Import:    memory.NewImport(store),
+			Export:    memory.NewExport(store),
+			Tx:        memory.NewTxManager(store),
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+type MessageRepo struct {
+	store *Store
+}
+
+// NewMessage - MessageRepo constructor
+func NewMessage(store *Store) *MessageRepo {
+	return &MessageRepo{store}
+}
+
+// Create inserts message created now. Mailing and client must exist.
+func (r *MessageRepo) Create(ctx context.Context, msg *entity.Message) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	if _, ok := db.mailings[msg.MailingID]; !ok {
+		return fmt.Errorf("MessageRepo - Create(): %w: mailing %d doesn't exist", ErrConstraint, msg.MailingID)
+	}
+	if _, ok := db.clients[msg.ClientID]; !ok {
+		return fmt.Errorf("MessageRepo - Create(): %w: client %d doesn't exist", ErrConstraint, msg.ClientID)
+	}
+
+	m := *msg
+	db.messageSeq++
+	m.ID = db.messageSeq
+	m.DateTimeCreation = now()
+	set(db, db.messages, m.ID, messageRow{Message: m})
+	db.addStats(m)
+
+	return nil
+}
+
+// ReadByMailing returns page of messages of mailing by filter and cursor
+// of the next page. Cursor is empty on the last page.
+func (r *MessageRepo) ReadByMailing(ctx context.Context, filter *entity.MessageFilter) (
+	entity.Messages, string, error,
+) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	ms, next, err := page(db.selectMessages(filter), &filter.Page, func(m *entity.Message) (time.Time, int64) {
+		return m.SortTime(filter.Sort), m.ID
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+	}
+
+	return ms, next, nil
+}
+
+// Read -.
+func (r *MessageRepo) Read(ctx context.Context, message *entity.Message) (*entity.Message, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	m, ok := db.messages[message.ID]
+	if !ok {
+		return nil, fmt.Errorf("MessageRepo - Read(): message %d: %w", message.ID, entity.ErrNotFound)
+	}
+
+	return &m.Message, nil
+}
+
+// Export calls f for every message of filter ordered by ID, page of
+// filter isn't used. Like ClientRepo.Export store isn't locked while f is called.
+func (r *MessageRepo) Export(ctx context.Context, filter *entity.MessageFilter, f func(*entity.Message) error) error {
+	db, unlock := r.store.lock(ctx)
+	ms := db.selectMessages(filter)
+	unlock()
+
+	sort.Slice(ms, func(i, j int) bool { return ms[i].ID < ms[j].ID })
+	for _, m := range ms {
+		if err := f(m); err != nil {
+			return fmt.Errorf("MessageRepo - Export(): %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReadSeries returns counters of messages grouped by buckets of filter
+// time zone. Empty buckets are omitted.
+func (r *MessageRepo) ReadSeries(ctx context.Context, filter *entity.SeriesFilter) (
+	[]*entity.SeriesPoint, error,
+) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	byBucket := make(map[int64]*entity.SeriesPoint)
+	for _, msg := range db.messages {
+		m := msg.Message
+		if filter.MailingID != 0 && m.MailingID != filter.MailingID || !db.activeMailing(m.MailingID) {
+			continue
+		}
+		if m.DateTimeCreation.Before(filter.From) || !m.DateTimeCreation.Before(filter.To) {
+			continue
+		}
+
+		start := filter.BucketStart(m.DateTimeCreation)
+		p, ok := byBucket[start.Unix()]
+		if !ok {
+			p = &entity.SeriesPoint{Time: start}
+			byBucket[start.Unix()] = p
+		}
+
+		switch status(&m) {
+		case entity.MessageDelivered:
+			p.Sent++
+		case entity.MessageFailed:
+			p.Failed++
+		case entity.MessageDeferred:
+			p.Deferred++
+		}
+	}
+
+	points := make([]*entity.SeriesPoint, 0, len(byBucket))
+	for _, p := range byBucket {
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+
+	return points, nil
+}
+
+// status returns delivery status of message
+func status(m *entity.Message) string {
+	switch {
+	case m.DeliveryStatus:
+		return entity.MessageDelivered
+	case m.Deferred:
+		return entity.MessageDeferred
+	}
+
+	return entity.MessageFailed
+}
+
+// addStats counts inserted message in rollup of its mailing
+func (db *state) addStats(m entity.Message) {
+	st, ok := db.stats[m.MailingID]
+	if !ok {
+		st.first, st.last = m.DateTimeCreation, m.DateTimeCreation
+	}
+
+	switch status(&m) {
+	case entity.MessageDelivered:
+		st.succeeded++
+	case entity.MessageFailed:
+		st.failed++
+	}
+	if m.DateTimeCreation.Before(st.first) {
+		st.first = m.DateTimeCreation
+	}
+	if m.DateTimeCreation.After(st.last) {
+		st.last = m.DateTimeCreation
+	}
+
+	set(db, db.stats, m.MailingID, st)
+}
+
+// selectMessages returns mailing messages of filter in arbitrary order
+func (db *state) selectMessages(filter *entity.MessageFilter) entity.Messages {
+	var ms entity.Messages
+	for _, msg := range db.messages {
+		m := msg.Message
+		if m.MailingID != filter.MailingID || !db.activeMailing(m.MailingID) {
+			continue
+		}
+		if !filter.From.IsZero() && m.DateTimeCreation.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !m.DateTimeCreation.Before(filter.To) {
+			continue
+		}
+		if filter.Status != "" && status(&m) != filter.Status {
+			continue
+		}
+		ms = append(ms, &m)
+	}
+
+	return ms
+}
+
+// activeMailing reports whether mailing of message exists and isn't deleted
+func (db *state) activeMailing(id int64) bool {
+	m, ok := db.mailings[id]
+	return ok && m.DeletedAt == nil
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// partitionPrefix is prefix of monthly partitions like in PostgreSQL
+const partitionPrefix = "message_p"
+
+// PartitionRepo manages monthly partitions of messages. Messages aren't
+// stored by partitions, partition is messages created in its month.
+type PartitionRepo struct {
+	store *Store
+}
+
+func NewPartition(store *Store) *PartitionRepo {
+	return &PartitionRepo{store}
+}
+
+// Create creates partitions for months number of months starting from month of from.
+// Existing partitions aren't changed.
+func (r *PartitionRepo) Create(ctx context.Context, from time.Time, months int) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	start := entity.MonthStart(from)
+	for i := 0; i < months; i++ {
+		set(db, db.partitions, start.AddDate(0, i, 0), true)
+	}
+
+	return nil
+}
+
+// List returns monthly partitions ordered by month
+func (r *PartitionRepo) List(ctx context.Context) (entity.Partitions, error) {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	ps := make(entity.Partitions, 0, len(db.partitions))
+	for month := range db.partitions {
+		ps = append(ps, &entity.Partition{Name: partitionPrefix + month.Format("200601"), Month: month})
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].Month.Before(ps[j].Month) })
+
+	return ps, nil
+}
+
+// exportColumns are columns of message table in PostgreSQL order
+var exportColumns = []string{
+	"id", "date_time_creation", "delivery_status", "mailing_id", "client_id", "try", "deferred",
+}
+
+// Export writes messages of partition to w as CSV with header like COPY does
+func (r *PartitionRepo) Export(ctx context.Context, p *entity.Partition, w io.Writer) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	var ms []messageRow
+	for _, m := range db.messages {
+		if inMonth(m.DateTimeCreation, p.Month) {
+			ms = append(ms, m)
+		}
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].ID < ms[j].ID })
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportColumns); err != nil {
+		return fmt.Errorf("PartitionRepo - Export(): %w", err)
+	}
+	for _, m := range ms {
+		err := cw.Write([]string{
+			strconv.FormatInt(m.ID, 10),
+			timestamp(m.DateTimeCreation),
+			boolean(m.DeliveryStatus),
+			strconv.FormatInt(m.MailingID, 10),
+			strconv.FormatInt(m.ClientID, 10),
+			strconv.Itoa(m.Try),
+			boolean(m.Deferred),
+		})
+		if err != nil {
+			return fmt.Errorf("PartitionRepo - Export(): %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("PartitionRepo - Export(): %w", err)
+	}
+
+	return nil
+}
+
+// Drop drops partition with its messages. Rollups of messages aren't changed.
+func (r *PartitionRepo) Drop(ctx context.Context, p *entity.Partition) error {
+	db, unlock := r.store.lock(ctx)
+	defer unlock()
+
+	for id, m := range db.messages {
+		if inMonth(m.DateTimeCreation, p.Month) {
+			del(db, db.messages, id)
+		}
+	}
+	del(db, db.partitions, entity.MonthStart(p.Month))
+
+	return nil
+}
+
+// Lock tries to take lock of retention without waiting, so only one
+// job enforces it at a time. Release must be called to unlock it.
+func (r *PartitionRepo) Lock(context.Context) (release func(), ok bool, err error) {
+	release, ok = r.store.tryLock("retention")
+
+	return release, ok, nil
+}
+
+func inMonth(t, month time.Time) bool {
+	return entity.MonthStart(t).Equal(entity.MonthStart(month))
+}
+
+// timestamp formats time like PostgreSQL TIMESTAMP
+func timestamp(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05.999999")
+}
+
+// boolean formats b like PostgreSQL BOOLEAN
+func boolean(b bool) string {
+	if b {
+		return "t"
+	}
+
+	return "f"
+}
@@ -0,0 +1,298 @@
+// Package memory implements repositories over maps. It's used to run the
+// service and tests without PostgreSQL, semantics of repositories are the same.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// ErrConstraint is returned if row violates constraint of PostgreSQL schema
+var ErrConstraint = errors.New("constraint violation")
+
+// Store keeps rows of all the repositories. Statements are serialized
+// by one lock, transaction holds it until it's finished.
+type Store struct {
+	mu sync.Mutex
+	db *state
+
+	// Locks aren't transactional
+	locksMu sync.Mutex
+	locks   map[string]bool
+}
+
+func NewStore() *Store {
+	return &Store{
+		db: &state{
+			clients:        make(map[int64]clientRow),
+			mailings:       make(map[int64]entity.Mailing),
+			mailingArchive: make(map[int64]entity.Mailing),
+			messages:       make(map[int64]messageRow),
+			messageArchive: make(map[int64]messageRow),
+			stats:          make(map[int64]stats),
+			chunks:         make(map[chunkKey]entity.MailingChunk),
+			fanouts:        make(map[int64]int),
+			partitions:     make(map[time.Time]bool),
+			audit:          make(map[int64]entity.AuditRecord),
+			imports:        make(map[int64]entity.ImportJob),
+			importErrors:   make(map[int64]entity.ImportRowError),
+			exports:        make(map[int64]entity.ExportJob),
+		},
+		locks: make(map[string]bool),
+	}
+}
+
+// state is rows of tables, values are copied in and out of it.
+// Rows are written by set and del, so transaction can undo its changes.
+type state struct {
+	sequences
+
+	// undo restores rows changed by transaction in reverse order,
+	// changes aren't recorded out of transaction
+	undo []func()
+	inTx bool
+
+	clients        map[int64]clientRow
+	mailings       map[int64]entity.Mailing
+	mailingArchive map[int64]entity.Mailing
+	messages       map[int64]messageRow
+	messageArchive map[int64]messageRow
+	stats          map[int64]stats
+	chunks         map[chunkKey]entity.MailingChunk
+	fanouts        map[int64]int
+	partitions     map[time.Time]bool
+	audit          map[int64]entity.AuditRecord
+	imports        map[int64]entity.ImportJob
+	importErrors   map[int64]entity.ImportRowError
+	exports        map[int64]entity.ExportJob
+}
+
+// sequences are last IDs of tables
+type sequences struct {
+	clientSeq, mailingSeq, messageSeq, auditSeq int64
+	importSeq, importErrorSeq, exportSeq        int64
+}
+
+// set writes row of table
+func set[K comparable, V any](db *state, table map[K]V, key K, row V) {
+	record(db, table, key)
+	table[key] = row
+}
+
+// del deletes row of table
+func del[K comparable, V any](db *state, table map[K]V, key K) {
+	if _, ok := table[key]; ok {
+		record(db, table, key)
+		delete(table, key)
+	}
+}
+
+// record adds restoring of row of table to undo log of transaction
+func record[K comparable, V any](db *state, table map[K]V, key K) {
+	if !db.inTx {
+		return
+	}
+
+	prev, ok := table[key]
+	db.undo = append(db.undo, func() {
+		if ok {
+			table[key] = prev
+		} else {
+			delete(table, key)
+		}
+	})
+}
+
+// rollback undoes changes recorded after mark and restores sequences
+func (s *state) rollback(mark int, seqs sequences) {
+	for i := len(s.undo) - 1; i >= mark; i-- {
+		s.undo[i]()
+	}
+	s.undo = s.undo[:mark]
+	s.sequences = seqs
+}
+
+type clientRow struct {
+	entity.Client
+	deletedAt *time.Time
+}
+
+type messageRow struct {
+	entity.Message
+}
+
+// stats is rollup of messages of mailing, it isn't changed by deletion of messages
+type stats struct {
+	succeeded, failed int
+	first, last       time.Time
+}
+
+type chunkKey struct {
+	mailingID int64
+	seq       int
+}
+
+type txKey struct{}
+
+// lock takes lock of store and returns its state. It isn't taken again
+// in transaction of ctx, the lock is already held by transaction.
+func (s *Store) lock(ctx context.Context) (*state, func()) {
+	if tx, ok := ctx.Value(txKey{}).(*Store); ok && tx == s {
+		return s.db, func() {}
+	}
+
+	s.mu.Lock()
+	return s.db, s.mu.Unlock
+}
+
+// TxManager runs functions in transactions of store. Transaction holds
+// lock of store, so transactions and statements out of them are serialized.
+// Repositories must be called with ctx of function, otherwise they wait
+// for the transaction to finish and it never does.
+type TxManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{store}
+}
+
+// Do runs f in transaction, rows changed by f are restored from undo log
+// if f fails. Nested Do restores only changes of its f like savepoint.
+func (m *TxManager) Do(ctx context.Context, f func(ctx context.Context) error) (err error) {
+	s := m.store
+
+	if tx, ok := ctx.Value(txKey{}).(*Store); !ok || tx != s {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		ctx = context.WithValue(ctx, txKey{}, s)
+
+		// Committed changes can't be undone
+		s.db.inTx = true
+		defer func() {
+			s.db.inTx = false
+			s.db.undo = nil
+		}()
+	}
+
+	mark, seqs := len(s.db.undo), s.db.sequences
+	defer func() {
+		if p := recover(); p != nil {
+			s.db.rollback(mark, seqs)
+			panic(p)
+		}
+	}()
+
+	if err = f(ctx); err != nil {
+		s.db.rollback(mark, seqs)
+		return err
+	}
+
+	return nil
+}
+
+// now returns current time as it's stored in TIMESTAMP column
+func now() time.Time {
+	return stamp(time.Now())
+}
+
+// stamp drops precision and location like TIMESTAMP column
+func stamp(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// Values of enums of schema
+var (
+	filterChoices     = []string{"tag", "code"}
+	mailingPriorities = []string{entity.PriorityTransactional, entity.PriorityBulk}
+	chunkStatuses     = []string{entity.ChunkCreated, entity.ChunkPublished, entity.ChunkDone}
+	auditEntities     = []string{entity.AuditClient, entity.AuditMailing, entity.AuditImport}
+	importFormats     = []string{entity.ImportCSV, entity.ImportNDJSON}
+	importStatuses    = []string{entity.ImportPending, entity.ImportRunning, entity.ImportDone, entity.ImportFailed}
+	exportStatuses    = []string{entity.ExportPending, entity.ExportRunning, entity.ExportDone, entity.ExportFailed}
+	auditActions      = []string{
+		entity.AuditCreate, entity.AuditUpdate, entity.AuditDelete, entity.AuditRestore, entity.AuditArchive, entity.AuditUpsert,
+	}
+)
+
+// checkEnum fails if value isn't one of enum values
+func checkEnum(name, value string, values []string) error {
+	for _, v := range values {
+		if v == value {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: invalid %s %q", ErrConstraint, name, value)
+}
+
+// checkMailing checks mailing columns like schema. Operator code is
+// INTEGER column, so it's normalized.
+func checkMailing(m *entity.Mailing) error {
+	code, err := strconv.Atoi(m.MobileOperator)
+	if err != nil {
+		return fmt.Errorf("%w: invalid mobile_operator_code %q", ErrConstraint, m.MobileOperator)
+	}
+	m.MobileOperator = strconv.Itoa(code)
+
+	if err = checkEnum("tag", m.Tag, entity.ClientTags); err != nil {
+		return err
+	}
+	if err = checkEnum("filter_choice", m.FilterChoice, filterChoices); err != nil {
+		return err
+	}
+
+	return checkEnum("priority", m.Priority, mailingPriorities)
+}
+
+// page sorts items by sort of page with ties by ID, skips items up to
+// cursor and returns up to limit items and cursor of the next page
+func page[T any](items []T, p *entity.Page, key func(T) (time.Time, int64)) ([]T, string, error) {
+	after, err := p.After()
+	if err != nil {
+		return nil, "", err
+	}
+
+	desc := p.Order == entity.OrderDesc
+
+	// less reports whether position (t1, id1) is before (t2, id2) in page order
+	less := func(t1 time.Time, id1 int64, t2 time.Time, id2 int64) bool {
+		if desc {
+			t1, id1, t2, id2 = t2, id2, t1, id1
+		}
+		if !t1.Equal(t2) {
+			return t1.Before(t2)
+		}
+		return id1 < id2
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		ti, idi := key(items[i])
+		tj, idj := key(items[j])
+		return less(ti, idi, tj, idj)
+	})
+
+	if after != nil {
+		i := sort.Search(len(items), func(i int) bool {
+			t, id := key(items[i])
+			return less(after.Time, after.ID, t, id)
+		})
+		items = items[i:]
+	}
+
+	if len(items) <= p.Limit {
+		return items, "", nil
+	}
+
+	items = items[:p.Limit]
+	t, id := key(items[len(items)-1])
+
+	return items, p.Next(t, id), nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
+)
+
+type AuditRepo struct {
+	Builder squirrel.StatementBuilderType
+	conn    *pgxpool.Pool
+}
+
+func NewAudit(conn *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		conn:    conn,
+	}
+}
+
+func (r *AuditRepo) Create(ctx context.Context, record *entity.AuditRecord) error {
+	builder, err := queries.InsertAudit(r.Builder, record)
+	if err != nil {
+		return fmt.Errorf("AuditRepo - Create(): %w", err)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("AuditRepo - Create(): %w", err)
+	}
+
+	err = querier(ctx, r.conn).QueryRow(ctx, query, args...).Scan(&record.ID)
+	if err != nil {
+		return fmt.Errorf("AuditRepo - Create(): %w", err)
+	}
+
+	return nil
+}
+
+// ReadPage returns page of records by filter and cursor of the next page.
+// Cursor is empty on the last page.
+func (r *AuditRepo) ReadPage(ctx context.Context, filter *entity.AuditFilter) (
+	[]*entity.AuditRecord, string, error,
+) {
+	builder, err := queries.SelectAuditPage(r.Builder, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("AuditRepo - ReadPage(): %w", err)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, "", fmt.Errorf("AuditRepo - ReadPage(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("AuditRepo - ReadPage(): %w", err)
+	}
+	defer rows.Close()
+
+	var records []*entity.AuditRecord
+	for rows.Next() {
+		record, err := queries.ScanAudit(rows)
+		if err != nil {
+			return nil, "", fmt.Errorf("AuditRepo - ReadPage(): %w", err)
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("AuditRepo - ReadPage(): %w", err)
+	}
+
+	if len(records) <= filter.Limit {
+		return records, "", nil
+	}
+
+	records = records[:filter.Limit]
+	last := records[len(records)-1]
+
+	return records, filter.Next(last.SortTime(filter.Sort), last.ID), nil
+}
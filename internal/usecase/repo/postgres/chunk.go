@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
+)
+
+// ChunkRepo tracks progress of mailing audience chunks
+type ChunkRepo struct {
+	Builder squirrel.StatementBuilderType
+	conn    *pgxpool.Pool
+}
+
+func NewChunk(conn *pgxpool.Pool) *ChunkRepo {
+	return &ChunkRepo{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		conn:    conn,
+	}
+}
+
+// Create inserts chunk. Existing chunk with the same seq isn't changed.
+func (r *ChunkRepo) Create(ctx context.Context, chunk *entity.MailingChunk) error {
+	query, args, err := queries.InsertChunk(r.Builder, chunk).ToSql()
+	if err != nil {
+		return fmt.Errorf("ChunkRepo - Create(): %w", err)
+	}
+
+	_, err = querier(ctx, r.conn).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ChunkRepo - Create(): %w", err)
+	}
+
+	return nil
+}
+
+// SetStatus moves chunk status forward: created, published, done.
+// Chunk could be done before fan-out marks it published, so status
+// isn't moved back.
+func (r *ChunkRepo) SetStatus(ctx context.Context, chunk *entity.MailingChunk) error {
+	query, args, err := queries.SetChunkStatus(r.Builder, chunk, squirrel.Expr("now()")).ToSql()
+	if err != nil {
+		return fmt.Errorf("ChunkRepo - SetStatus(): %w", err)
+	}
+
+	_, err = querier(ctx, r.conn).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ChunkRepo - SetStatus(): %w", err)
+	}
+
+	return nil
+}
+
+func (r *ChunkRepo) Read(ctx context.Context, chunk *entity.MailingChunk) (*entity.MailingChunk, error) {
+	query, args, err := queries.SelectChunk(r.Builder, chunk).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ChunkRepo - Read(): %w", err)
+	}
+
+	c, err := queries.ScanChunk(querier(ctx, r.conn).QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("ChunkRepo - Read(): %w", err)
+	}
+
+	return c, nil
+}
+
+// ReadByMailing returns chunks of mailing ordered by seq
+func (r *ChunkRepo) ReadByMailing(ctx context.Context, mailing *entity.Mailing) (entity.MailingChunks, error) {
+	query, args, err := queries.SelectChunks(r.Builder, mailing).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ChunkRepo - ReadByMailing(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ChunkRepo - ReadByMailing(): %w", err)
+	}
+	defer rows.Close()
+
+	var cs entity.MailingChunks
+	for rows.Next() {
+		c, err := queries.ScanChunk(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ChunkRepo - ReadByMailing(): %w", err)
+		}
+		cs = append(cs, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ChunkRepo - ReadByMailing(): %w", err)
+	}
+
+	return cs, nil
+}
+
+// Seal marks fan-out of mailing finished with chunks count
+func (r *ChunkRepo) Seal(ctx context.Context, mailing *entity.Mailing, chunks int) error {
+	query, args, err := queries.SealFanout(r.Builder, mailing, chunks).ToSql()
+	if err != nil {
+		return fmt.Errorf("ChunkRepo - Seal(): %w", err)
+	}
+
+	_, err = querier(ctx, r.conn).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ChunkRepo - Seal(): %w", err)
+	}
+
+	return nil
+}
+
+// Completed reports whether fan-out of mailing is sealed and all its chunks are done
+func (r *ChunkRepo) Completed(ctx context.Context, mailing *entity.Mailing) (bool, error) {
+	query, args, err := queries.SelectCompleted(r.Builder, mailing).ToSql()
+	if err != nil {
+		return false, fmt.Errorf("ChunkRepo - Completed(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.conn).Query(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("ChunkRepo - Completed(): %w", err)
+	}
+	defer rows.Close()
+
+	// Fan-out isn't sealed if there are no rows
+	var completed bool
+	for rows.Next() {
+		if err = rows.Scan(&completed); err != nil {
+			return false, fmt.Errorf("ChunkRepo - Completed(): %w", err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return false, fmt.Errorf("ChunkRepo - Completed(): %w", err)
+	}
+
+	return completed, nil
+}
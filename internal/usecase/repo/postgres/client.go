@@ -2,14 +2,18 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
 )
 
-const tableClient string = "client"
+// clientImportTable is temporary table of clients upserted by COPY
+const clientImportTable = "client_import"
 
 type ClientRepo struct {
 	Builder squirrel.StatementBuilderType
@@ -24,21 +28,12 @@ func NewClient(conn *pgxpool.Pool) *ClientRepo {
 }
 
 func (r *ClientRepo) Create(ctx context.Context, client *entity.Client) error {
-	query, args, err := r.Builder.
-		Insert(tableClient).
-		Columns("mobile_operator_code", "phone_number", "tag", "time_zone").
-		Values(
-			client.MobileOperator,
-			client.PhoneNumber,
-			client.Tag,
-			client.TimeZone,
-		).
-		ToSql()
+	query, args, err := queries.InsertClient(r.Builder, client).ToSql()
 	if err != nil {
 		return fmt.Errorf("ClientRepo - Create(): %w", err)
 	}
 
-	_, err = r.conn.Exec(ctx, query, args...)
+	err = querier(ctx, r.conn).QueryRow(ctx, query, args...).Scan(&client.ID, &client.Version)
 	if err != nil {
 		return fmt.Errorf("ClientRepo - Create(): %w", err)
 	}
@@ -46,113 +41,207 @@ func (r *ClientRepo) Create(ctx context.Context, client *entity.Client) error {
 	return nil
 }
 
+// Update changes client of the same version and increments its version.
+// entity.ErrConflict is returned if version differs or client is deleted.
 func (r *ClientRepo) Update(ctx context.Context, client *entity.Client) error {
-	builder := r.Builder.
-		Update(tableClient).
-		Where(squirrel.Eq{"id": client.ID})
-
-	if client.PhoneNumber != 0 {
-		builder = builder.Set("phone_number", client.PhoneNumber)
-	}
-	if client.MobileOperator != 0 {
-		builder = builder.Set("mobile_operator_code", client.MobileOperator)
-	}
-	if client.Tag != "" {
-		builder = builder.Set("tag", client.Tag)
-	}
-	if client.TimeZone != 0 {
-		builder = builder.Set("time_zone", client.TimeZone)
-	}
-
-	query, args, err := builder.ToSql()
+	query, args, err := queries.UpdateClient(r.Builder, client).ToSql()
 	if err != nil {
 		return fmt.Errorf("ClientRepo - Update(): %w", err)
 	}
 
-	_, err = r.conn.Exec(ctx, query, args...)
+	tag, err := querier(ctx, r.conn).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("ClientRepo - Update(): %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("ClientRepo - Update(): client %d: %w", client.ID, entity.ErrConflict)
+	}
+	client.Version++
 
 	return nil
 }
 
+// Delete marks client deleted, so it's excluded from audiences.
+// Messages of client are kept. Version is checked like by Update.
 func (r *ClientRepo) Delete(ctx context.Context, client *entity.Client) error {
-	query, args, err := r.Builder.
-		Delete(tableClient).
-		Where(squirrel.Eq{"id": client.ID}).
-		ToSql()
+	query, args, err := queries.DeleteClient(r.Builder, client, squirrel.Expr("now()")).ToSql()
 	if err != nil {
 		return fmt.Errorf("ClientRepo - Delete(): %w", err)
 	}
 
-	_, err = r.conn.Exec(ctx, query, args...)
+	tag, err := querier(ctx, r.conn).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("ClientRepo - Delete(): %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("ClientRepo - Delete(): client %d: %w", client.ID, entity.ErrConflict)
+	}
+	client.Version++
+
+	return nil
+}
+
+// Restore returns deleted client. entity.ErrNotFound is returned if client
+// isn't deleted. Restore fails if phone number is taken by another client.
+func (r *ClientRepo) Restore(ctx context.Context, client *entity.Client) error {
+	query, args, err := queries.RestoreClient(r.Builder, client).ToSql()
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Restore(): %w", err)
+	}
+
+	tag, err := querier(ctx, r.conn).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Restore(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("ClientRepo - Restore(): client %d: %w", client.ID, entity.ErrNotFound)
+	}
 
 	return nil
 }
 
 func (r *ClientRepo) Read(ctx context.Context, client *entity.Client) (*entity.Client, error) {
-	query, _, err := r.Builder.
-		Select("*").
-		From(tableClient).
-		Where(squirrel.Eq{"id": client.ID}).
-		ToSql()
+	query, args, err := queries.SelectClient(r.Builder, client).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("ClientRepo - Read(): %w", err)
 	}
 
-	var c entity.Client
-	err = r.conn.QueryRow(ctx, query).Scan(
-		&c.ID, &c.MobileOperator, &c.PhoneNumber, &c.Tag, &c.TimeZone,
-	)
+	c, err := queries.ScanClient(querier(ctx, r.conn).QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("ClientRepo - Read(): client %d: %w", client.ID, entity.ErrNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("ClientRepo - Read(): %w", err)
 	}
 
-	return &c, nil
+	return c, nil
 }
 
-func (r *ClientRepo) ReadByFilter(ctx context.Context, mailing *entity.Mailing) (entity.Clients, error) {
-	var where map[string]interface{} = make(map[string]interface{}, 1)
+// ReadPage returns up to limit clients of mailing audience with ID
+// greater than afterID ordered by ID. It's keyset pagination, so pages
+// are stable if clients are added or deleted between calls.
+func (r *ClientRepo) ReadPage(ctx context.Context, mailing *entity.Mailing, afterID int64, limit int) (
+	entity.Clients, error,
+) {
+	query, args, err := queries.SelectAudience(r.Builder, mailing).
+		Where(squirrel.Gt{"id": afterID}).
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ClientRepo - ReadPage(): %w", err)
+	}
 
-	switch mailing.FilterChoice {
-	case "code":
-		where["mobile_operator_code"] = squirrel.Eq{"mobile_operator_code": mailing.MobileOperator}
-	case "tag":
-		where["tag"] = squirrel.Eq{"tag": mailing.Tag}
+	cs, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ClientRepo - ReadPage(): %w", err)
 	}
 
-	query, _, err := r.Builder.
-		Select("id", "phone_number", "time_zone").
-		From(tableClient).
-		Where(where).
-		ToSql()
+	return cs, nil
+}
+
+// Upsert creates clients, clients of phone numbers taken by not deleted
+// ones are updated and their versions are incremented. Clients are sent by
+// COPY to temporary table and upserted from it in one transaction.
+func (r *ClientRepo) Upsert(ctx context.Context, clients entity.Clients) (int64, error) {
+	if len(clients) == 0 {
+		return 0, nil
+	}
+
+	tx, err := querier(ctx, r.conn).Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("ClientRepo - ReadByFilter(): %w", err)
+		return 0, fmt.Errorf("ClientRepo - Upsert(): %w", err)
 	}
+	// Rollback is no-op after commit
+	defer tx.Rollback(context.WithoutCancel(ctx))
 
-	var cs entity.Clients
-	rows, err := r.conn.Query(ctx, query)
+	_, err = tx.Exec(ctx, `CREATE TEMP TABLE `+clientImportTable+` (
+		phone_number BIGINT,
+		mobile_operator_code INTEGER,
+		tag TEXT,
+		time_zone INTEGER
+	) ON COMMIT DROP`)
+	if err != nil {
+		return 0, fmt.Errorf("ClientRepo - Upsert(): %w", err)
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{clientImportTable}, queries.ClientImportColumns,
+		pgx.CopyFromSlice(len(clients), func(i int) ([]any, error) {
+			c := clients[i]
+			return []any{c.PhoneNumber, c.MobileOperator, c.Tag, c.TimeZone}, nil
+		}))
+	if err != nil {
+		return 0, fmt.Errorf("ClientRepo - Upsert() - CopyFrom(): %w", err)
+	}
+
+	query, args, err := queries.UpsertClientsFrom(r.Builder, clientImportTable).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("ClientRepo - Upsert(): %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("ClientRepo - Upsert(): %w", err)
+	}
+
+	// Table is dropped at once, Upsert could be called again in outer transaction
+	if _, err = tx.Exec(ctx, "DROP TABLE "+clientImportTable); err != nil {
+		return 0, fmt.Errorf("ClientRepo - Upsert(): %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("ClientRepo - Upsert() - Commit(): %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// Export calls f for every client of mailing audience ordered by ID,
+// rows are received from server while f is called, so clients
+// aren't buffered. Export stops at the first error of f.
+func (r *ClientRepo) Export(ctx context.Context, mailing *entity.Mailing, f func(*entity.Client) error) error {
+	query, args, err := queries.SelectClients(r.Builder, mailing).ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("ClientRepo - ReadByFiler(): %w", err)
+		return fmt.Errorf("ClientRepo - Export(): %w", err)
 	}
 
+	rows, err := querier(ctx, r.conn).Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Export(): %w", err)
+	}
+	defer rows.Close()
+
 	for rows.Next() {
-		var c entity.Client
-		err = rows.Scan(
-			&c.ID, &c.PhoneNumber, &c.TimeZone,
-		)
+		c, err := queries.ScanClient(rows)
 		if err != nil {
-			return nil, fmt.Errorf("ClientRepo - ReadByFiler(): %w", err)
+			return fmt.Errorf("ClientRepo - Export(): %w", err)
+		}
+		if err = f(c); err != nil {
+			return fmt.Errorf("ClientRepo - Export(): %w", err)
 		}
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("ClientRepo - ReadByFilter(): %w", err)
+		return fmt.Errorf("ClientRepo - Export(): %w", err)
 	}
 
-	return cs, nil
+	return nil
+}
+
+func (r *ClientRepo) query(ctx context.Context, query string, args ...interface{}) (entity.Clients, error) {
+	rows, err := querier(ctx, r.conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cs entity.Clients
+	for rows.Next() {
+		c, err := queries.ScanRecipient(rows)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+
+	return cs, rows.Err()
 }
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// leaseNamespace is the first key of advisory lock to not intersect
+// with other advisory locks in database.
+const leaseNamespace int32 = 1001
+
+// LeaseRepo grants ownership of mailing chunk between workers with session
+// advisory locks. Lock is bound to the connection, so lease of dead
+// worker is released by PostgreSQL together with its connection.
+//
+// Chunks of the same mailing are leased independently, chunk 0 is
+// fan-out of mailing.
+//
+// Connection of lease is held while chunk is sent, so pool of LeaseRepo
+// must be dedicated one. Leases taken from pool of other repositories
+// drain it and workers holding them block on their own queries.
+type LeaseRepo struct {
+	conn *pgxpool.Pool
+}
+
+func NewLease(conn *pgxpool.Pool) *LeaseRepo {
+	return &LeaseRepo{conn}
+}
+
+// Acquire tries to lock mailing chunk without waiting. If chunk is locked
+// by another worker ok is false. Release must be called to unlock it.
+func (r *LeaseRepo) Acquire(ctx context.Context, mailing *entity.Mailing, chunk int) (
+	release func(), ok bool, err error,
+) {
+	conn, err := r.conn.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("LeaseRepo - Acquire(): %w", err)
+	}
+
+	key := fmt.Sprintf("%d:%d", mailing.ID, chunk)
+
+	err = conn.QueryRow(ctx,
+		"SELECT pg_try_advisory_lock($1, hashtext($2))", leaseNamespace, key,
+	).Scan(&ok)
+	if err != nil || !ok {
+		conn.Release()
+		if err != nil {
+			return nil, false, fmt.Errorf("LeaseRepo - Acquire(): %w", err)
+		}
+		return nil, false, nil
+	}
+
+	release = func() {
+		_, err := conn.Exec(context.Background(),
+			"SELECT pg_advisory_unlock($1, hashtext($2))", leaseNamespace, key)
+		if err != nil {
+			// Connection is dropped to be sure lock is released
+			_ = conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}
+
+	return release, true, nil
+}
@@ -2,15 +2,18 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
 )
 
-const tableMailing = "mailing"
-
 type MailingRepo struct {
 	Builder squirrel.StatementBuilderType
 	conn    *pgxpool.Pool
@@ -24,25 +27,12 @@ func NewMailing(conn *pgxpool.Pool) *MailingRepo {
 }
 
 func (r *MailingRepo) Create(ctx context.Context, mailing *entity.Mailing) error {
-	query, args, err := r.Builder.
-		Insert(tableMailing).
-		Columns("message_text", "mobile_operator_code", "tag",
-			"datetime_start", "datetime_end", "interval_start", "interval_end").
-		Values(
-			mailing.MessageText,
-			mailing.MobileOperator,
-			mailing.Tag,
-			mailing.DateTimeStart,
-			mailing.DateTimeEnd,
-			mailing.IntervalStart,
-			mailing.IntervalEnd,
-		).
-		ToSql()
+	query, args, err := queries.InsertMailing(r.Builder, mailing).ToSql()
 	if err != nil {
 		return fmt.Errorf("MailingRepo - Create(): %w", err)
 	}
 
-	_, err = r.conn.Exec(ctx, query, args...)
+	err = querier(ctx, r.conn).QueryRow(ctx, query, args...).Scan(&mailing.ID, &mailing.Version)
 	if err != nil {
 		return fmt.Errorf("MailingRepo - Create(): %w", err)
 	}
@@ -50,94 +40,226 @@ func (r *MailingRepo) Create(ctx context.Context, mailing *entity.Mailing) error
 	return nil
 }
 
+// Update changes mailing of the same version and increments its version.
+// entity.ErrConflict is returned if version differs or mailing is deleted.
 func (r *MailingRepo) Update(ctx context.Context, mailing *entity.Mailing) error {
-	builder := r.Builder.
-		Update(tableMailing).
-		Where(squirrel.Eq{"id": mailing.ID})
-
-	if mailing.MessageText != "" {
-		builder = builder.Set("message_text", mailing.MessageText)
-	}
-	if mailing.MobileOperator != "" {
-		builder = builder.Set("mobile_operator_code", mailing.MobileOperator)
+	query, args, err := queries.UpdateMailing(r.Builder, mailing).ToSql()
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Update(): %w", err)
 	}
-	if mailing.Tag != "" {
-		builder = builder.Set("tag", mailing.Tag)
+
+	tag, err := querier(ctx, r.conn).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Update(): %w", err)
 	}
-	if mailing.FilterChoice != "" {
-		builder = builder.Set("filter_choice", mailing.FilterChoice)
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("MailingRepo - Update(): mailing %d: %w", mailing.ID, entity.ErrConflict)
 	}
-	if mailing.DateTimeStart.IsZero() {
-		builder = builder.Set("datetime_start", mailing.DateTimeStart)
+	mailing.Version++
+
+	return nil
+}
+
+// Delete marks mailing deleted, its messages are kept.
+// Version is checked like by Update.
+func (r *MailingRepo) Delete(ctx context.Context, mailing *entity.Mailing) error {
+	query, args, err := queries.DeleteMailing(r.Builder, mailing, squirrel.Expr("now()")).ToSql()
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Delete(): %w", err)
 	}
-	if mailing.DateTimeEnd.IsZero() {
-		builder = builder.Set("datetime_end", mailing.DateTimeEnd)
+
+	tag, err := querier(ctx, r.conn).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Delete(): %w", err)
 	}
-	if mailing.IntervalStart.IsZero() {
-		builder = builder.Set("interval_start", mailing.IntervalStart)
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("MailingRepo - Delete(): mailing %d: %w", mailing.ID, entity.ErrConflict)
 	}
-	if mailing.IntervalEnd.IsZero() {
-		builder = builder.Set("interval_end", mailing.IntervalEnd)
+	mailing.Version++
+
+	return nil
+}
+
+// Restore returns deleted or archived mailing with its messages.
+// entity.ErrNotFound is returned if mailing is neither deleted nor archived.
+func (r *MailingRepo) Restore(ctx context.Context, mailing *entity.Mailing) error {
+	tx, err := querier(ctx, r.conn).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Restore(): %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	query, args, err := builder.ToSql()
+	query, args, err := queries.UndeleteMailing(r.Builder, mailing).ToSql()
 	if err != nil {
-		return fmt.Errorf("MailingRepo - Update(): %w", err)
+		return fmt.Errorf("MailingRepo - Restore(): %w", err)
 	}
 
-	_, err = r.conn.Exec(ctx, query, args...)
+	tag, err := tx.Exec(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("MailingRepo - Update(): %w", err)
+		return fmt.Errorf("MailingRepo - Restore(): %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		// Mailing is inserted before its messages because of foreign key.
+		// Restored mailing isn't deleted, so deleted_at isn't copied.
+		mailings := strings.Join(queries.MailingColumns[:len(queries.MailingColumns)-1], ", ")
+		tag, err = tx.Exec(ctx,
+			"INSERT INTO "+queries.TableMailing+" ("+mailings+") SELECT "+mailings+
+				" FROM "+queries.TableMailingArchive+" WHERE id = $1",
+			mailing.ID)
+		if err != nil {
+			return fmt.Errorf("MailingRepo - Restore(): %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("MailingRepo - Restore(): mailing %d: %w", mailing.ID, entity.ErrNotFound)
+		}
+
+		err = moveMessages(ctx, tx, queries.TableMessageArchive, queries.TableMessage, []int64{mailing.ID})
+		if err != nil {
+			return fmt.Errorf("MailingRepo - Restore(): %w", err)
+		}
+
+		_, err = tx.Exec(ctx, "DELETE FROM "+queries.TableMailingArchive+" WHERE id = $1", mailing.ID)
+		if err != nil {
+			return fmt.Errorf("MailingRepo - Restore(): %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("MailingRepo - Restore(): %w", err)
 	}
 
 	return nil
 }
 
-func (r *MailingRepo) Delete(ctx context.Context, mailing *entity.Mailing) error {
-	query, args, err := r.Builder.
-		Delete(tableMailing).
-		Where(squirrel.Eq{"id": mailing.ID}).
-		ToSql()
+// Archive moves up to limit mailings finished before time with their
+// messages into archive tables. It returns IDs of archived mailings.
+// Mailings locked by other archivers are skipped.
+func (r *MailingRepo) Archive(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	tx, err := querier(ctx, r.conn).Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("MailingRepo - Delete(): %w", err)
+		return nil, fmt.Errorf("MailingRepo - Archive(): %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	_, err = r.conn.Exec(ctx, query, args...)
+	rows, err := tx.Query(ctx,
+		"SELECT id FROM "+queries.TableMailing+" WHERE datetime_end <= $1 ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED",
+		before.UTC(), limit)
 	if err != nil {
-		return fmt.Errorf("MailingRepo - Delete(): %w", err)
+		return nil, fmt.Errorf("MailingRepo - Archive(): %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("MailingRepo - Archive(): %w", err)
+	}
+	if len(ids) == 0 {
+		return ids, nil
 	}
 
-	return nil
+	columns := strings.Join(queries.MailingColumns, ", ")
+	_, err = tx.Exec(ctx,
+		"INSERT INTO "+queries.TableMailingArchive+" ("+columns+") SELECT "+columns+
+			" FROM "+queries.TableMailing+" WHERE id = ANY($1)",
+		ids)
+	if err != nil {
+		return nil, fmt.Errorf("MailingRepo - Archive(): %w", err)
+	}
+
+	err = moveMessages(ctx, tx, queries.TableMessage, queries.TableMessageArchive, ids)
+	if err != nil {
+		return nil, fmt.Errorf("MailingRepo - Archive(): %w", err)
+	}
+
+	// Chunks and stats of mailing are deleted by cascade
+	_, err = tx.Exec(ctx, "DELETE FROM "+queries.TableMailing+" WHERE id = ANY($1)", ids)
+	if err != nil {
+		return nil, fmt.Errorf("MailingRepo - Archive(): %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("MailingRepo - Archive(): %w", err)
+	}
+
+	return ids, nil
+}
+
+// moveMessages moves messages of mailings from one table to another
+func moveMessages(ctx context.Context, tx pgx.Tx, from, to string, mailings []int64) error {
+	columns := strings.Join(queries.ArchiveMessageColumns, ", ")
+	_, err := tx.Exec(ctx,
+		"WITH moved AS (DELETE FROM "+from+" WHERE mailing_id = ANY($1) RETURNING "+columns+") "+
+			"INSERT INTO "+to+" ("+columns+") SELECT "+columns+" FROM moved",
+		mailings)
+
+	return err
 }
 
+// ReadWithMessages returns stats of mailing that isn't deleted from mailing_stats
+// rollup, counters are zero if mailing doesn't have messages
 func (r *MailingRepo) ReadWithMessages(ctx context.Context, mailing *entity.Mailing) (*entity.MailingStats, error) {
-	query, _, err := r.Builder.
-		Select().
-		Columns("m.id AS mailing_id",
-			"MIN(msg.date_time_creation)",
-			"MAX(msg.date_time_creation)",
-			"COUNT(CASE WHEN msg.delivery_status = 1 THEN 1 ELSE 0 END) AS successed",
-			"COUNT(CASE WHEN msg.delivery_status = 0 THEN 1 ELSE 0 END) AS failed",
-		).
-		From(tableMailing + " m").
-		Where(squirrel.Eq{"m.id": mailing.ID}).
-		LeftJoin("message msg ON msg.mailing_id = m.id").
-		GroupBy("m.id, m.datetime_start, m.datetime_end").
-		OrderBy("m.datetime_end").
-		ToSql()
+	query, args, err := queries.SelectStats(r.Builder, mailing).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("MailingRepo - ReadWithMessages(): %w", err)
 	}
 
-	var m entity.MailingStats
-	err = r.conn.QueryRow(ctx, query).Scan(
-		&m.MailingID, &m.DateTimeStart, &m.DateTimeEnd, &m.Succesed, &m.Failed,
-	)
+	m, err := queries.ScanStats(querier(ctx, r.conn).QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("MailingRepo - ReadWithMessages(): mailing %d: %w", mailing.ID, entity.ErrNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("MailingRepo - ReadWithMessages(): %w", err)
 	}
 
-	return &m, nil
+	return m, nil
+}
+
+// ReadStatsPage returns stats of page of mailings in one query and cursor
+// of the next page. Cursor is empty on the last page.
+func (r *MailingRepo) ReadStatsPage(ctx context.Context, filter *entity.MailingFilter) (
+	[]*entity.MailingStats, string, error,
+) {
+	builder, err := queries.SelectStatsPage(r.Builder, filter, squirrel.Expr("now()"))
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		stats []*entity.MailingStats
+		sorts []time.Time
+	)
+	for rows.Next() {
+		var m entity.Mailing
+		s, err := queries.ScanStats(rows, &m.DateTimeStart, &m.DateTimeEnd)
+		if err != nil {
+			return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+		}
+		stats = append(stats, s)
+		sorts = append(sorts, m.SortTime(filter.Sort))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+	}
+
+	if len(stats) <= filter.Limit {
+		return stats, "", nil
+	}
+
+	stats = stats[:filter.Limit]
+	last := len(stats) - 1
+
+	return stats, filter.Next(sorts[last], stats[last].MailingID), nil
 }
 
 // Read - Select all the fields from mailing table.
@@ -145,57 +267,62 @@ func (r *MailingRepo) ReadWithMessages(ctx context.Context, mailing *entity.Mail
 // Important: if current mailing.ID doesn't compare with any row
 // in table then Read() return error. It needs to check mailing by deletion.
 func (r *MailingRepo) Read(ctx context.Context, mailing *entity.Mailing) (*entity.Mailing, error) {
-	query, _, err := r.Builder.
-		Select("*").
-		From(tableMailing).
-		Where(squirrel.Eq{"id": mailing.ID}).
-		ToSql()
+	query, args, err := queries.SelectMailing(r.Builder, mailing).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("MailingRepo - Read(): %w", err)
 	}
 
-	var m entity.Mailing
-	err = r.conn.QueryRow(ctx, query).Scan(
-		&m.ID, &m.MessageText, &m.MobileOperator, &m.Tag, &m.FilterChoice,
-		&m.DateTimeStart, &m.DateTimeEnd, &m.IntervalStart, &m.IntervalEnd,
-	)
+	m, err := queries.ScanMailing(querier(ctx, r.conn).QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("MailingRepo - Read(): mailing %d: %w", mailing.ID, entity.ErrNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("MailingRepo - Read(): %w", err)
 	}
 
-	return &m, nil
+	return m, nil
 }
 
-func (r *MailingRepo) ReadAll(ctx context.Context) (entity.Mailings, error) {
-	query, _, err := r.Builder.
-		Select("*").
-		From(tableMailing).
-		ToSql()
+// ReadPage returns page of mailings by filter and cursor of the next page.
+// Cursor is empty on the last page.
+func (r *MailingRepo) ReadPage(ctx context.Context, filter *entity.MailingFilter) (
+	entity.Mailings, string, error,
+) {
+	builder, err := queries.SelectMailingPage(r.Builder, filter, squirrel.Expr("now()"))
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
+	}
+
+	query, args, err := builder.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("MailingRepo - ReadAll(): %w", err)
+		return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
 	}
 
-	rows, err := r.conn.Query(ctx, query)
+	rows, err := querier(ctx, r.conn).Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("MailingRepo - ReadAll(): %w", err)
+		return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
 	}
+	defer rows.Close()
 
 	var ms entity.Mailings
 	for rows.Next() {
-		var m entity.Mailing
-		err = rows.Scan(
-			&m.ID, &m.MessageText, &m.MobileOperator, &m.Tag, &m.FilterChoice,
-			&m.DateTimeStart, &m.DateTimeEnd, &m.IntervalStart, &m.IntervalEnd,
-		)
+		m, err := queries.ScanMailing(rows)
 		if err != nil {
-			return nil, fmt.Errorf("MailingRepo - ReadAll(): %w", err)
+			return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
 		}
-		ms = append(ms, &m)
+		ms = append(ms, m)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("MailingRepo - ReadAll(): %w", err)
+		return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
 	}
 
-	return ms, nil
+	if len(ms) <= filter.Limit {
+		return ms, "", nil
+	}
+
+	ms = ms[:filter.Limit]
+	last := ms[len(ms)-1]
+
+	return ms, filter.Next(last.SortTime(filter.Sort), last.ID), nil
 }
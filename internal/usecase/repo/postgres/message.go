@@ -7,10 +7,9 @@ import (
 	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
 )
 
-const tableMessage = "message"
-
 type MessageRepo struct {
 	Builder squirrel.StatementBuilderType
 	conn    *pgxpool.Pool
@@ -26,21 +25,12 @@ func NewMessage(conn *pgxpool.Pool) *MessageRepo {
 
 // Create -.
 func (r *MessageRepo) Create(ctx context.Context, message *entity.Message) error {
-	query, args, err := r.Builder.
-		Insert(tableMessage).
-		Columns("try", "delivery_status", "mailing_id", "client_id").
-		Values(
-			message.Try,
-			message.DeliveryStatus,
-			message.MailingID,
-			message.ClientID,
-		).
-		ToSql()
+	query, args, err := queries.InsertMessage(r.Builder, message, squirrel.Expr("now()")).ToSql()
 	if err != nil {
 		return fmt.Errorf("MessageRepo - Create(): %w", err)
 	}
 
-	_, err = r.conn.Exec(ctx, query, args...)
+	_, err = querier(ctx, r.conn).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("MessageRepo - Create(): %w", err)
 	}
@@ -48,61 +38,148 @@ func (r *MessageRepo) Create(ctx context.Context, message *entity.Message) error
 	return nil
 }
 
-// ReadByMailing -.
-func (r *MessageRepo) ReadByMailing(ctx context.Context, mailing *entity.Mailing) (
-	entity.Messages, error,
+// ReadByMailing returns page of messages of mailing by filter and cursor
+// of the next page. Cursor is empty on the last page.
+func (r *MessageRepo) ReadByMailing(ctx context.Context, filter *entity.MessageFilter) (
+	entity.Messages, string, error,
 ) {
-	query, _, err := r.Builder.
-		Select("*").
-		From(tableMessage).
-		Where(squirrel.Eq{"mailing_id": mailing.ID}).
-		ToSql()
+	builder, err := queries.SelectMessagePage(r.Builder, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+	}
+
+	query, args, err := builder.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+		return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
 	}
 
-	rows, err := r.conn.Query(ctx, query)
+	rows, err := querier(ctx, r.conn).Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+		return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
 	}
+	defer rows.Close()
 
 	var ms entity.Messages
 	for rows.Next() {
-		var m entity.Message
-		err = rows.Scan(
-			&m.ID, &m.DateTimeCreation, &m.Try, &m.DeliveryStatus, &m.MailingID, &m.ClientID,
-		)
+		m, err := queries.ScanMessage(rows)
 		if err != nil {
-			return nil, fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+			return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
 		}
-		ms = append(ms, &m)
+		ms = append(ms, m)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+		return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+	}
+
+	if len(ms) <= filter.Limit {
+		return ms, "", nil
 	}
 
-	return ms, nil
+	ms = ms[:filter.Limit]
+	last := ms[len(ms)-1]
+
+	return ms, filter.Next(last.SortTime(filter.Sort), last.ID), nil
 }
 
 // Read -.
 func (r *MessageRepo) Read(ctx context.Context, message *entity.Message) (*entity.Message, error) {
-	query, _, err := r.Builder.
-		Select("*").
-		From(tableMessage).
-		Where(squirrel.Eq{"id": message.ID}).
-		ToSql()
+	query, args, err := queries.SelectMessage(r.Builder, message).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("MessageRepo - Read(): %w", err)
 	}
 
-	var m entity.Message
-	err = r.conn.QueryRow(ctx, query).Scan(
-		&m.ID, &m.DateTimeCreation, &m.Try, &m.DeliveryStatus, &m.MailingID, &m.ClientID,
-	)
+	m, err := queries.ScanMessage(querier(ctx, r.conn).QueryRow(ctx, query, args...))
 	if err != nil {
 		return nil, fmt.Errorf("MessageRepo - Read(): %w", err)
 	}
 
-	return &m, nil
+	return m, nil
+}
+
+// Export calls f for every message of filter ordered by ID, page of
+// filter isn't used. Like ClientRepo.Export messages aren't buffered.
+func (r *MessageRepo) Export(ctx context.Context, filter *entity.MessageFilter, f func(*entity.Message) error) error {
+	query, args, err := queries.SelectMessages(r.Builder, filter).OrderBy("id").ToSql()
+	if err != nil {
+		return fmt.Errorf("MessageRepo - Export(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.conn).Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("MessageRepo - Export(): %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := queries.ScanMessage(rows)
+		if err != nil {
+			return fmt.Errorf("MessageRepo - Export(): %w", err)
+		}
+		if err = f(m); err != nil {
+			return fmt.Errorf("MessageRepo - Export(): %w", err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("MessageRepo - Export(): %w", err)
+	}
+
+	return nil
+}
+
+// ReadSeries returns counters of messages grouped by buckets of filter
+// time zone. Empty buckets are omitted.
+func (r *MessageRepo) ReadSeries(ctx context.Context, filter *entity.SeriesFilter) (
+	[]*entity.SeriesPoint, error,
+) {
+	// Creation time is stored in UTC
+	bucket := squirrel.Expr("date_trunc(?, date_time_creation AT TIME ZONE 'UTC', ?::text) AS bucket",
+		filter.Bucket, filter.TimeZone)
+
+	builder := r.Builder.
+		Select().
+		Column(bucket).
+		Columns(
+			"COUNT(*) FILTER (WHERE delivery_status)",
+			"COUNT(*) FILTER (WHERE NOT delivery_status AND NOT deferred)",
+			"COUNT(*) FILTER (WHERE deferred)",
+		).
+		From(queries.TableMessage).
+		Where(queries.ActiveMailing).
+		Where(squirrel.GtOrEq{"date_time_creation": filter.From.UTC()}).
+		Where(squirrel.Lt{"date_time_creation": filter.To.UTC()}).
+		GroupBy("bucket").
+		OrderBy("bucket")
+
+	if filter.MailingID != 0 {
+		builder = builder.Where(squirrel.Eq{"mailing_id": filter.MailingID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("MessageRepo - ReadSeries(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("MessageRepo - ReadSeries(): %w", err)
+	}
+	defer rows.Close()
+
+	var points []*entity.SeriesPoint
+	for rows.Next() {
+		var p entity.SeriesPoint
+		err = rows.Scan(&p.Time, &p.Sent, &p.Failed, &p.Deferred)
+		if err != nil {
+			return nil, fmt.Errorf("MessageRepo - ReadSeries(): %w", err)
+		}
+		points = append(points, &p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("MessageRepo - ReadSeries(): %w", err)
+	}
+
+	return points, nil
 }
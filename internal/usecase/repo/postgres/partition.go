@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
+)
+
+// partitionNamespace is the first key of advisory lock of retention
+const partitionNamespace int32 = 1002
+
+// partitionPrefix is prefix of monthly partitions of message, see migrations
+const partitionPrefix = queries.TableMessage + "_p"
+
+// PartitionRepo manages monthly partitions of message table
+type PartitionRepo struct {
+	conn *pgxpool.Pool
+}
+
+func NewPartition(conn *pgxpool.Pool) *PartitionRepo {
+	return &PartitionRepo{conn}
+}
+
+// Create creates partitions for months number of months starting from month of from.
+// Existing partitions aren't changed.
+func (r *PartitionRepo) Create(ctx context.Context, from time.Time, months int) error {
+	start := entity.MonthStart(from)
+	for i := 0; i < months; i++ {
+		_, err := r.conn.Exec(ctx, "SELECT message_partition_create($1::date)", start.AddDate(0, i, 0))
+		if err != nil {
+			return fmt.Errorf("PartitionRepo - Create(): %w", err)
+		}
+	}
+
+	return nil
+}
+
+// List returns monthly partitions ordered by month, default partition isn't included
+func (r *PartitionRepo) List(ctx context.Context) (entity.Partitions, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = $1::regclass AND c.relname LIKE $2
+		ORDER BY c.relname`,
+		queries.TableMessage, partitionPrefix+"%")
+	if err != nil {
+		return nil, fmt.Errorf("PartitionRepo - List(): %w", err)
+	}
+
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("PartitionRepo - List(): %w", err)
+	}
+
+	ps := make(entity.Partitions, 0, len(names))
+	for _, name := range names {
+		month, err := time.Parse("200601", strings.TrimPrefix(name, partitionPrefix))
+		if err != nil {
+			// Table isn't created by message_partition_create()
+			continue
+		}
+		ps = append(ps, &entity.Partition{Name: name, Month: month})
+	}
+
+	return ps, nil
+}
+
+// Export writes messages of partition to w as CSV with header
+func (r *PartitionRepo) Export(ctx context.Context, p *entity.Partition, w io.Writer) error {
+	conn, err := r.conn.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("PartitionRepo - Export(): %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Conn().PgConn().CopyTo(ctx, w,
+		"COPY "+pgx.Identifier{p.Name}.Sanitize()+" TO STDOUT WITH (FORMAT csv, HEADER)")
+	if err != nil {
+		return fmt.Errorf("PartitionRepo - Export(): %w", err)
+	}
+
+	return nil
+}
+
+// Drop drops partition with its messages. Rollups of messages aren't changed.
+// Partition is detached concurrently first, so writes to message table
+// aren't blocked by ACCESS EXCLUSIVE lock of parent while it's dropped.
+// Detaching interrupted by previous Drop is finalized.
+func (r *PartitionRepo) Drop(ctx context.Context, p *entity.Partition) error {
+	name := pgx.Identifier{p.Name}.Sanitize()
+
+	var pending *bool
+	err := r.conn.QueryRow(ctx,
+		"SELECT inhdetachpending FROM pg_inherits WHERE inhrelid = to_regclass($1)", name).Scan(&pending)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("PartitionRepo - Drop(): %w", err)
+	}
+
+	// DETACH CONCURRENTLY can't run in transaction, so pool is used
+	if pending != nil {
+		detach := "ALTER TABLE " + queries.TableMessage + " DETACH PARTITION " + name + " CONCURRENTLY"
+		if *pending {
+			detach = "ALTER TABLE " + queries.TableMessage + " DETACH PARTITION " + name + " FINALIZE"
+		}
+		if _, err = r.conn.Exec(ctx, detach); err != nil {
+			return fmt.Errorf("PartitionRepo - Drop(): %w", err)
+		}
+	}
+
+	if _, err = r.conn.Exec(ctx, "DROP TABLE IF EXISTS "+name); err != nil {
+		return fmt.Errorf("PartitionRepo - Drop(): %w", err)
+	}
+
+	return nil
+}
+
+// Lock tries to take lock of retention without waiting, so only one
+// instance enforces it at a time. Release must be called to unlock it.
+func (r *PartitionRepo) Lock(ctx context.Context) (release func(), ok bool, err error) {
+	conn, err := r.conn.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("PartitionRepo - Lock(): %w", err)
+	}
+
+	err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1, 0)", partitionNamespace).Scan(&ok)
+	if err != nil || !ok {
+		conn.Release()
+		if err != nil {
+			return nil, false, fmt.Errorf("PartitionRepo - Lock(): %w", err)
+		}
+		return nil, false, nil
+	}
+
+	release = func() {
+		_, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1, 0)", partitionNamespace)
+		if err != nil {
+			// Connection is dropped to be sure lock is released
+			_ = conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}
+
+	return release, true, nil
+}
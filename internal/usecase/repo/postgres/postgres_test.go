@@ -0,0 +1,115 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/postgres"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/repotest"
+	"gitlab.com/fluxx1on_group/event_message_service/migrations"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/migrate"
+)
+
+// TestConformance runs on database of TEST_DATABASE_URL, its tables are truncated
+func TestConformance(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close(ctx)
+
+	ms, err := migrate.Load(migrations.Postgres, migrations.PostgresDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = migrate.New(conn, ms).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	repotest.Run(t, func(t *testing.T) *repotest.Repos {
+		// Partition of the current month could be dropped by previous test
+		_, err := conn.Exec(ctx, `
+			TRUNCATE client, mailing, message, mailing_stats, mailing_chunk, mailing_fanout,
+				mailing_archive, message_archive, audit_log, import_job, import_error, export_job RESTART IDENTITY CASCADE;
+			SELECT message_partition_create(now()::date)`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return &repotest.Repos{
+			Client:    postgres.NewClient(pool),
+			Mailing:   postgres.NewMailing(pool),
+			Message:   postgres.NewMessage(pool),
+			Chunk:     postgres.NewChunk(pool),
+			Partition: postgres.NewPartition(pool),
+			Lease:     postgres.NewLease(pool),
+			Audit:     postgres.NewAudit(pool),
+			Import:    postgres.NewImport(pool),
+			Export:    postgres.NewExport(pool),
+			Tx:        postgres.NewTxManager(pool),
+		}
+	})
+}
+
+// TestLeasePool runs on database of TEST_DATABASE_URL
+func TestLeasePool(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+
+	newPool := func(size int32) *pgxpool.Pool {
+		cfg, err := pgxpool.ParseConfig(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.MaxConns = size
+
+		pool, err := pgxpool.NewWithConfig(ctx, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(pool.Close)
+
+		return pool
+	}
+
+	// test 1: held leases don't take connections of repositories
+	{
+		pool, leases := newPool(1), postgres.NewLease(newPool(4))
+
+		for chunk := 1; chunk <= 4; chunk++ {
+			release, ok, err := leases.Acquire(ctx, &entity.Mailing{ID: 1}, chunk)
+			if err != nil || !ok {
+				t.Fatal(ok, err)
+			}
+			defer release()
+		}
+
+		queryCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		if err := pool.Ping(queryCtx); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
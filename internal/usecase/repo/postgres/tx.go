@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Querier runs statements on pool or in transaction
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type txKey struct{}
+
+// querier returns transaction of ctx or pool if there is no transaction.
+// Repositories run every statement on it.
+func querier(ctx context.Context, pool *pgxpool.Pool) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return pool
+}
+
+// TxManager runs functions in transactions. Transaction is carried in
+// ctx, so all the repositories called with ctx of function use it.
+type TxManager struct {
+	conn *pgxpool.Pool
+}
+
+func NewTxManager(conn *pgxpool.Pool) *TxManager {
+	return &TxManager{conn}
+}
+
+// Do runs f in transaction, it's committed if f succeeds and rolled back
+// otherwise. Nested Do runs f in savepoint of outer transaction, so its
+// failure doesn't abort outer one.
+func (m *TxManager) Do(ctx context.Context, f func(ctx context.Context) error) (err error) {
+	tx, err := querier(ctx, m.conn).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("TxManager - Do() - Begin(): %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+			panic(p)
+		}
+	}()
+
+	if err = f(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		// Rollback is done even if ctx is cancelled
+		if rbErr := tx.Rollback(context.WithoutCancel(ctx)); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("TxManager - Do() - Rollback(): %w", rbErr))
+		}
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("TxManager - Do() - Commit(): %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package queries
+
+import (
+	"encoding/json"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+const TableAudit = "audit_log"
+
+// AuditColumns are columns scanned by ScanAudit
+var AuditColumns = []string{
+	"id", "created_at", "actor", "action", "entity", "entity_id", "diff", "request_id",
+}
+
+// InsertAudit returns id of inserted record, diff is stored as JSON text
+func InsertAudit(b squirrel.StatementBuilderType, record *entity.AuditRecord) (squirrel.InsertBuilder, error) {
+	diff, err := json.Marshal(record.Diff)
+	if err != nil {
+		return squirrel.InsertBuilder{}, err
+	}
+
+	return b.
+		Insert(TableAudit).
+		Columns("created_at", "actor", "action", "entity", "entity_id", "diff", "request_id").
+		Values(
+			record.CreatedAt,
+			record.Actor,
+			record.Action,
+			record.Entity,
+			record.EntityID,
+			string(diff),
+			record.RequestID,
+		).
+		Suffix("RETURNING id"), nil
+}
+
+// SelectAuditPage selects page of records by filter, they're scanned by ScanAudit
+func SelectAuditPage(b squirrel.StatementBuilderType, filter *entity.AuditFilter) (squirrel.SelectBuilder, error) {
+	builder := b.
+		Select(AuditColumns...).
+		From(TableAudit)
+
+	if filter.Entity != "" {
+		builder = builder.Where(squirrel.Eq{"entity": filter.Entity})
+	}
+	if filter.EntityID != 0 {
+		builder = builder.Where(squirrel.Eq{"entity_id": filter.EntityID})
+	}
+	if filter.Actor != "" {
+		builder = builder.Where(squirrel.Eq{"actor": filter.Actor})
+	}
+	if !filter.From.IsZero() {
+		builder = builder.Where(squirrel.GtOrEq{"created_at": filter.From.UTC()})
+	}
+	if !filter.To.IsZero() {
+		builder = builder.Where(squirrel.Lt{"created_at": filter.To.UTC()})
+	}
+
+	return Paginate(builder, &filter.Page)
+}
+
+func ScanAudit(row Row) (*entity.AuditRecord, error) {
+	var (
+		r    entity.AuditRecord
+		diff []byte
+	)
+	err := row.Scan(&r.ID, &r.CreatedAt, &r.Actor, &r.Action, &r.Entity, &r.EntityID, &diff, &r.RequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(diff, &r.Diff); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
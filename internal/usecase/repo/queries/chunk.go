@@ -0,0 +1,82 @@
+package queries
+
+import (
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+const (
+	TableChunk  = "mailing_chunk"
+	TableFanout = "mailing_fanout"
+)
+
+var chunkColumns = []string{"mailing_id", "seq", "after_id", "last_id", "status"}
+
+// chunkRank orders statuses of chunk, they're moved only forward
+const chunkRank = "CASE status WHEN 'created' THEN 0 WHEN 'published' THEN 1 ELSE 2 END"
+
+// InsertChunk inserts chunk, existing chunk with the same seq isn't changed
+func InsertChunk(b squirrel.StatementBuilderType, chunk *entity.MailingChunk) squirrel.InsertBuilder {
+	return b.
+		Insert(TableChunk).
+		Columns(chunkColumns...).
+		Values(chunk.MailingID, chunk.Seq, chunk.AfterID, chunk.LastID, chunk.Status).
+		Suffix("ON CONFLICT (mailing_id, seq) DO NOTHING")
+}
+
+// SetChunkStatus moves status of chunk forward, it isn't moved back
+func SetChunkStatus(b squirrel.StatementBuilderType, chunk *entity.MailingChunk, now squirrel.Sqlizer) squirrel.UpdateBuilder {
+	rank := map[string]int{entity.ChunkCreated: 0, entity.ChunkPublished: 1, entity.ChunkDone: 2}
+
+	return b.
+		Update(TableChunk).
+		Set("status", chunk.Status).
+		Set("updated_at", now).
+		Where(squirrel.Eq{"mailing_id": chunk.MailingID, "seq": chunk.Seq}).
+		Where(chunkRank+" < ?", rank[chunk.Status])
+}
+
+// SelectChunk selects chunk by mailing and seq, it's scanned by ScanChunk
+func SelectChunk(b squirrel.StatementBuilderType, chunk *entity.MailingChunk) squirrel.SelectBuilder {
+	return b.
+		Select(chunkColumns...).
+		From(TableChunk).
+		Where(squirrel.Eq{"mailing_id": chunk.MailingID, "seq": chunk.Seq})
+}
+
+// SelectChunks selects chunks of mailing ordered by seq, they're scanned by ScanChunk
+func SelectChunks(b squirrel.StatementBuilderType, mailing *entity.Mailing) squirrel.SelectBuilder {
+	return b.
+		Select(chunkColumns...).
+		From(TableChunk).
+		Where(squirrel.Eq{"mailing_id": mailing.ID}).
+		OrderBy("seq")
+}
+
+func ScanChunk(row Row) (*entity.MailingChunk, error) {
+	var c entity.MailingChunk
+	if err := row.Scan(&c.MailingID, &c.Seq, &c.AfterID, &c.LastID, &c.Status); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// SealFanout sets chunks count of finished fan-out of mailing
+func SealFanout(b squirrel.StatementBuilderType, mailing *entity.Mailing, chunks int) squirrel.InsertBuilder {
+	return b.
+		Insert(TableFanout).
+		Columns("mailing_id", "chunks").
+		Values(mailing.ID, chunks).
+		Suffix("ON CONFLICT (mailing_id) DO UPDATE SET chunks = EXCLUDED.chunks")
+}
+
+// SelectCompleted selects whether all the chunks of sealed fan-out are done.
+// There are no rows if fan-out isn't sealed.
+func SelectCompleted(b squirrel.StatementBuilderType, mailing *entity.Mailing) squirrel.SelectBuilder {
+	return b.
+		Select("f.chunks = (SELECT COUNT(*) FROM " + TableChunk +
+			" c WHERE c.mailing_id = f.mailing_id AND c.status = 'done')").
+		From(TableFanout + " f").
+		Where(squirrel.Eq{"f.mailing_id": mailing.ID})
+}
@@ -0,0 +1,128 @@
+package queries
+
+import (
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+const TableClient = "client"
+
+// InsertClient returns id and version of inserted client
+func InsertClient(b squirrel.StatementBuilderType, client *entity.Client) squirrel.InsertBuilder {
+	return b.
+		Insert(TableClient).
+		Columns("mobile_operator_code", "phone_number", "tag", "time_zone").
+		Values(
+			client.MobileOperator,
+			client.PhoneNumber,
+			client.Tag,
+			client.TimeZone,
+		).
+		Suffix("RETURNING id, version")
+}
+
+// UpdateClient sets non-zero fields of client that isn't deleted and
+// increments its version. No rows are changed if version of client differs.
+func UpdateClient(b squirrel.StatementBuilderType, client *entity.Client) squirrel.UpdateBuilder {
+	builder := b.
+		Update(TableClient).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": client.ID, "version": client.Version, "deleted_at": nil})
+
+	if client.PhoneNumber != 0 {
+		builder = builder.Set("phone_number", client.PhoneNumber)
+	}
+	if client.MobileOperator != 0 {
+		builder = builder.Set("mobile_operator_code", client.MobileOperator)
+	}
+	if client.Tag != "" {
+		builder = builder.Set("tag", client.Tag)
+	}
+	if client.TimeZone != 0 {
+		builder = builder.Set("time_zone", client.TimeZone)
+	}
+
+	return builder
+}
+
+// DeleteClient marks client deleted at now like UpdateClient changes it
+func DeleteClient(b squirrel.StatementBuilderType, client *entity.Client, now squirrel.Sqlizer) squirrel.UpdateBuilder {
+	return b.
+		Update(TableClient).
+		Set("deleted_at", now).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": client.ID, "version": client.Version, "deleted_at": nil})
+}
+
+// RestoreClient unmarks deleted client, no rows are changed if it isn't deleted
+func RestoreClient(b squirrel.StatementBuilderType, client *entity.Client) squirrel.UpdateBuilder {
+	return b.
+		Update(TableClient).
+		Set("deleted_at", nil).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": client.ID}).
+		Where(squirrel.NotEq{"deleted_at": nil})
+}
+
+// SelectClient selects client that isn't deleted, it's scanned by ScanClient
+func SelectClient(b squirrel.StatementBuilderType, client *entity.Client) squirrel.SelectBuilder {
+	return b.
+		Select("id", "phone_number", "mobile_operator_code", "tag", "time_zone", "version").
+		From(TableClient).
+		Where(squirrel.Eq{"id": client.ID, "deleted_at": nil})
+}
+
+func ScanClient(row Row) (*entity.Client, error) {
+	var c entity.Client
+	err := row.Scan(&c.ID, &c.PhoneNumber, &c.MobileOperator, &c.Tag, &c.TimeZone, &c.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// SelectClients selects clients of mailing audience with all the fields
+// ordered by ID, they're scanned by ScanClient
+func SelectClients(b squirrel.StatementBuilderType, mailing *entity.Mailing) squirrel.SelectBuilder {
+	return b.
+		Select("id", "phone_number", "mobile_operator_code", "tag", "time_zone", "version").
+		From(TableClient).
+		Where(audience(mailing)).
+		OrderBy("id")
+}
+
+// SelectAudience selects clients of mailing audience ordered by ID,
+// they're scanned by ScanRecipient
+func SelectAudience(b squirrel.StatementBuilderType, mailing *entity.Mailing) squirrel.SelectBuilder {
+	return b.
+		Select("id", "phone_number", "time_zone").
+		From(TableClient).
+		Where(audience(mailing)).
+		OrderBy("id")
+}
+
+// ScanRecipient scans client of audience, only fields used by sending are set
+func ScanRecipient(row Row) (*entity.Client, error) {
+	var c entity.Client
+	if err := row.Scan(&c.ID, &c.PhoneNumber, &c.TimeZone); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// audience returns condition of clients selected by mailing filter,
+// deleted clients are excluded
+func audience(mailing *entity.Mailing) squirrel.Sqlizer {
+	active := squirrel.Eq{"deleted_at": nil}
+
+	switch mailing.FilterChoice {
+	case "code":
+		return squirrel.And{active, squirrel.Eq{"mobile_operator_code": mailing.MobileOperator}}
+	case "tag":
+		return squirrel.And{active, squirrel.Eq{"tag": mailing.Tag}}
+	}
+
+	return active
+}
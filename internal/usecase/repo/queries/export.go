@@ -0,0 +1,78 @@
+package queries
+
+import (
+	"encoding/json"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+const TableExportJob = "export_job"
+
+// InsertExportJob returns id of inserted job, request is stored as JSON text
+func InsertExportJob(b squirrel.StatementBuilderType, job *entity.ExportJob) (squirrel.InsertBuilder, error) {
+	request, err := json.Marshal(job.Request)
+	if err != nil {
+		return squirrel.InsertBuilder{}, err
+	}
+
+	return b.
+		Insert(TableExportJob).
+		Columns("request", "status", "created_at").
+		Values(string(request), job.Status, job.CreatedAt.UTC()).
+		Suffix("RETURNING id"), nil
+}
+
+// UpdateExportJob sets status, number of rows, file and error of job
+func UpdateExportJob(b squirrel.StatementBuilderType, job *entity.ExportJob) squirrel.UpdateBuilder {
+	var finishedAt any
+	if job.FinishedAt != nil {
+		finishedAt = job.FinishedAt.UTC()
+	}
+
+	return b.
+		Update(TableExportJob).
+		Set("status", job.Status).
+		Set("rows", job.Rows).
+		Set("file", job.File).
+		Set("error", job.Error).
+		Set("finished_at", finishedAt).
+		Where(squirrel.Eq{"id": job.ID})
+}
+
+var exportJobColumns = []string{"id", "request", "status", "rows", "file", "error", "created_at", "finished_at"}
+
+// SelectExportJob selects job, it's scanned by ScanExportJob
+func SelectExportJob(b squirrel.StatementBuilderType, job *entity.ExportJob) squirrel.SelectBuilder {
+	return b.
+		Select(exportJobColumns...).
+		From(TableExportJob).
+		Where(squirrel.Eq{"id": job.ID})
+}
+
+// SelectUnfinishedExportJobs selects pending and running jobs ordered by id,
+// they're scanned by ScanExportJob
+func SelectUnfinishedExportJobs(b squirrel.StatementBuilderType) squirrel.SelectBuilder {
+	return b.
+		Select(exportJobColumns...).
+		From(TableExportJob).
+		Where(squirrel.Eq{"status": []string{entity.ExportPending, entity.ExportRunning}}).
+		OrderBy("id")
+}
+
+func ScanExportJob(row Row) (*entity.ExportJob, error) {
+	var (
+		j       entity.ExportJob
+		request []byte
+	)
+	err := row.Scan(&j.ID, &request, &j.Status, &j.Rows, &j.File, &j.Error, &j.CreatedAt, &j.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(request, &j.Request); err != nil {
+		return nil, err
+	}
+
+	return &j, nil
+}
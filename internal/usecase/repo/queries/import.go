@@ -0,0 +1,142 @@
+package queries
+
+import (
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+const (
+	TableImportJob   = "import_job"
+	TableImportError = "import_error"
+)
+
+// InsertImportJob returns id of inserted job
+func InsertImportJob(b squirrel.StatementBuilderType, job *entity.ImportJob) squirrel.InsertBuilder {
+	return b.
+		Insert(TableImportJob).
+		Columns("format", "status", "actor", "request_id", "created_at").
+		Values(job.Format, job.Status, job.Actor, job.RequestID, job.CreatedAt.UTC()).
+		Suffix("RETURNING id")
+}
+
+// UpdateImportJob sets status, counters and error of job
+func UpdateImportJob(b squirrel.StatementBuilderType, job *entity.ImportJob) squirrel.UpdateBuilder {
+	var finishedAt any
+	if job.FinishedAt != nil {
+		finishedAt = job.FinishedAt.UTC()
+	}
+
+	return b.
+		Update(TableImportJob).
+		Set("status", job.Status).
+		Set("total", job.Total).
+		Set("imported", job.Imported).
+		Set("failed", job.Failed).
+		Set("error", job.Error).
+		Set("finished_at", finishedAt).
+		Where(squirrel.Eq{"id": job.ID})
+}
+
+var importJobColumns = []string{
+	"id", "format", "status", "actor", "request_id", "total", "imported", "failed", "error", "created_at", "finished_at",
+}
+
+// SelectImportJob selects job, it's scanned by ScanImportJob
+func SelectImportJob(b squirrel.StatementBuilderType, job *entity.ImportJob) squirrel.SelectBuilder {
+	return b.
+		Select(importJobColumns...).
+		From(TableImportJob).
+		Where(squirrel.Eq{"id": job.ID})
+}
+
+// SelectUnfinishedImportJobs selects pending and running jobs ordered by id,
+// they're scanned by ScanImportJob
+func SelectUnfinishedImportJobs(b squirrel.StatementBuilderType) squirrel.SelectBuilder {
+	return b.
+		Select(importJobColumns...).
+		From(TableImportJob).
+		Where(squirrel.Eq{"status": []string{entity.ImportPending, entity.ImportRunning}}).
+		OrderBy("id")
+}
+
+func ScanImportJob(row Row) (*entity.ImportJob, error) {
+	var j entity.ImportJob
+	err := row.Scan(&j.ID, &j.Format, &j.Status, &j.Actor, &j.RequestID,
+		&j.Total, &j.Imported, &j.Failed, &j.Error, &j.CreatedAt, &j.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &j, nil
+}
+
+// InsertImportErrors inserts invalid rows of import, errs mustn't be empty
+func InsertImportErrors(b squirrel.StatementBuilderType, errs []*entity.ImportRowError) squirrel.InsertBuilder {
+	builder := b.
+		Insert(TableImportError).
+		Columns("job_id", "line", "field", "value", "message")
+
+	for _, e := range errs {
+		builder = builder.Values(e.JobID, e.Line, e.Field, e.Value, e.Message)
+	}
+
+	return builder
+}
+
+// SelectImportErrors selects up to limit errors of job with id greater
+// than afterID ordered by id, they're scanned by ScanImportError
+func SelectImportErrors(b squirrel.StatementBuilderType, job *entity.ImportJob, afterID int64, limit int) squirrel.SelectBuilder {
+	return b.
+		Select("id", "job_id", "line", "field", "value", "message").
+		From(TableImportError).
+		Where(squirrel.Eq{"job_id": job.ID}).
+		Where(squirrel.Gt{"id": afterID}).
+		OrderBy("id").
+		Limit(uint64(limit))
+}
+
+func ScanImportError(row Row) (*entity.ImportRowError, error) {
+	var e entity.ImportRowError
+	if err := row.Scan(&e.ID, &e.JobID, &e.Line, &e.Field, &e.Value, &e.Message); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// ClientImportColumns are columns of clients upserted by import
+var ClientImportColumns = []string{"phone_number", "mobile_operator_code", "tag", "time_zone"}
+
+// upsertClient updates client of taken phone number instead of insertion.
+// Deleted clients don't take phone numbers, so they aren't updated.
+const upsertClient = `ON CONFLICT (phone_number) WHERE deleted_at IS NULL DO UPDATE SET
+	mobile_operator_code = excluded.mobile_operator_code,
+	tag = excluded.tag,
+	time_zone = excluded.time_zone,
+	version = client.version + 1`
+
+// UpsertClients inserts clients or updates clients of their phone numbers.
+// Phone numbers of clients must be unique, clients mustn't be empty.
+func UpsertClients(b squirrel.StatementBuilderType, clients entity.Clients) squirrel.InsertBuilder {
+	builder := b.
+		Insert(TableClient).
+		Columns(ClientImportColumns...)
+
+	for _, c := range clients {
+		builder = builder.Values(c.PhoneNumber, c.MobileOperator, c.Tag, c.TimeZone)
+	}
+
+	return builder.Suffix(upsertClient)
+}
+
+// UpsertClientsFrom upserts clients like UpsertClients from table with
+// ClientImportColumns, tag is text there
+func UpsertClientsFrom(b squirrel.StatementBuilderType, table string) squirrel.InsertBuilder {
+	return b.
+		Insert(TableClient).
+		Columns(ClientImportColumns...).
+		Select(b.
+			Select("phone_number", "mobile_operator_code", "tag::client_tag", "time_zone").
+			From(table)).
+		Suffix(upsertClient)
+}
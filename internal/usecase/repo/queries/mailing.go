@@ -0,0 +1,219 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+const (
+	TableMailing = "mailing"
+	// TableStats is rollup of messages counters, see migrations
+	TableStats = "mailing_stats"
+
+	TableMailingArchive = "mailing_archive"
+	TableMessageArchive = "message_archive"
+)
+
+// MailingColumns are columns scanned by ScanMailing
+var MailingColumns = []string{
+	"id", "message_text", "mobile_operator_code", "tag", "filter_choice",
+	"datetime_start", "datetime_end", "interval_start", "interval_end", "priority", "version",
+	"deleted_at", // the last one
+}
+
+// ArchiveMessageColumns are columns of message kept in archive
+var ArchiveMessageColumns = []string{
+	"id", "date_time_creation", "try", "delivery_status", "deferred", "mailing_id", "client_id",
+}
+
+// InsertMailing returns id and version of inserted mailing, priority is bulk by default
+func InsertMailing(b squirrel.StatementBuilderType, mailing *entity.Mailing) squirrel.InsertBuilder {
+	return b.
+		Insert(TableMailing).
+		Columns("message_text", "mobile_operator_code", "tag", "filter_choice",
+			"datetime_start", "datetime_end", "interval_start", "interval_end", "priority").
+		Values(
+			mailing.MessageText,
+			mailing.MobileOperator,
+			mailing.Tag,
+			mailing.FilterChoice,
+			mailing.DateTimeStart,
+			mailing.DateTimeEnd,
+			mailing.IntervalStart,
+			mailing.IntervalEnd,
+			priority(mailing),
+		).
+		Suffix("RETURNING id, version")
+}
+
+// UpdateMailing sets non-zero fields of mailing that isn't deleted and
+// increments its version. No rows are changed if version of mailing differs.
+func UpdateMailing(b squirrel.StatementBuilderType, mailing *entity.Mailing) squirrel.UpdateBuilder {
+	builder := b.
+		Update(TableMailing).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": mailing.ID, "version": mailing.Version, "deleted_at": nil})
+
+	if mailing.MessageText != "" {
+		builder = builder.Set("message_text", mailing.MessageText)
+	}
+	if mailing.MobileOperator != "" {
+		builder = builder.Set("mobile_operator_code", mailing.MobileOperator)
+	}
+	if mailing.Tag != "" {
+		builder = builder.Set("tag", mailing.Tag)
+	}
+	if mailing.FilterChoice != "" {
+		builder = builder.Set("filter_choice", mailing.FilterChoice)
+	}
+	if !mailing.DateTimeStart.IsZero() {
+		builder = builder.Set("datetime_start", mailing.DateTimeStart)
+	}
+	if !mailing.DateTimeEnd.IsZero() {
+		builder = builder.Set("datetime_end", mailing.DateTimeEnd)
+	}
+	if !mailing.IntervalStart.IsZero() {
+		builder = builder.Set("interval_start", mailing.IntervalStart)
+	}
+	if !mailing.IntervalEnd.IsZero() {
+		builder = builder.Set("interval_end", mailing.IntervalEnd)
+	}
+	if mailing.Priority != "" {
+		builder = builder.Set("priority", mailing.Priority)
+	}
+
+	return builder
+}
+
+// DeleteMailing marks mailing deleted at now like UpdateMailing changes it
+func DeleteMailing(b squirrel.StatementBuilderType, mailing *entity.Mailing, now squirrel.Sqlizer) squirrel.UpdateBuilder {
+	return b.
+		Update(TableMailing).
+		Set("deleted_at", now).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": mailing.ID, "version": mailing.Version, "deleted_at": nil})
+}
+
+// UndeleteMailing unmarks deleted mailing, no rows are changed if it isn't deleted
+func UndeleteMailing(b squirrel.StatementBuilderType, mailing *entity.Mailing) squirrel.UpdateBuilder {
+	return b.
+		Update(TableMailing).
+		Set("deleted_at", nil).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": mailing.ID}).
+		Where(squirrel.NotEq{"deleted_at": nil})
+}
+
+// SelectMailing selects mailing that isn't deleted, it's scanned by ScanMailing
+func SelectMailing(b squirrel.StatementBuilderType, mailing *entity.Mailing) squirrel.SelectBuilder {
+	return b.
+		Select(MailingColumns...).
+		From(TableMailing).
+		Where(squirrel.Eq{"id": mailing.ID, "deleted_at": nil})
+}
+
+// SelectMailingPage selects page of mailings by filter, they're scanned by ScanMailing
+func SelectMailingPage(b squirrel.StatementBuilderType, filter *entity.MailingFilter, now squirrel.Sqlizer) (
+	squirrel.SelectBuilder, error,
+) {
+	builder := b.
+		Select(MailingColumns...).
+		From(TableMailing)
+
+	return Paginate(filterMailings(builder, filter, now), &filter.Page)
+}
+
+func ScanMailing(row Row) (*entity.Mailing, error) {
+	var m entity.Mailing
+	err := row.Scan(
+		&m.ID, &m.MessageText, &m.MobileOperator, &m.Tag, &m.FilterChoice,
+		&m.DateTimeStart, &m.DateTimeEnd, &m.IntervalStart, &m.IntervalEnd, &m.Priority, &m.Version, &m.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// SelectStats selects stats of mailing that isn't deleted from rollup,
+// it's scanned by ScanStats
+func SelectStats(b squirrel.StatementBuilderType, mailing *entity.Mailing) squirrel.SelectBuilder {
+	return b.
+		Select(statsColumns...).
+		From(TableMailing).
+		LeftJoin(TableStats + " ON mailing_id = id").
+		Where(squirrel.Eq{"id": mailing.ID, "deleted_at": nil})
+}
+
+// SelectStatsPage selects stats of page of mailings by filter. Rows are
+// scanned by ScanStats with start and end of mailing as extra columns.
+func SelectStatsPage(b squirrel.StatementBuilderType, filter *entity.MailingFilter, now squirrel.Sqlizer) (
+	squirrel.SelectBuilder, error,
+) {
+	builder := b.
+		Select(append(statsColumns, "datetime_start", "datetime_end")...).
+		From(TableMailing).
+		LeftJoin(TableStats + " ON mailing_id = id")
+
+	return Paginate(filterMailings(builder, filter, now), &filter.Page)
+}
+
+var statsColumns = []string{
+	"id", "first_at", "last_at", "COALESCE(succeeded, 0)", "COALESCE(failed, 0)",
+}
+
+// ScanStats scans stats columns and extra columns into dest. Bounds
+// of messages are zero if mailing doesn't have them.
+func ScanStats(row Row, dest ...any) (*entity.MailingStats, error) {
+	var (
+		s           entity.MailingStats
+		first, last *time.Time
+	)
+	err := row.Scan(append([]any{&s.MailingID, &first, &last, &s.Succesed, &s.Failed}, dest...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if first != nil {
+		s.DateTimeStart, s.DateTimeEnd = *first, *last
+	}
+
+	return &s, nil
+}
+
+// filterMailings adds conditions of filter except page, status is
+// compared with now
+func filterMailings(b squirrel.SelectBuilder, filter *entity.MailingFilter, now squirrel.Sqlizer) squirrel.SelectBuilder {
+	if !filter.WithDeleted {
+		b = b.Where(squirrel.Eq{"deleted_at": nil})
+	}
+	if !filter.From.IsZero() {
+		b = b.Where(squirrel.GtOrEq{"datetime_start": filter.From})
+	}
+	if !filter.To.IsZero() {
+		b = b.Where(squirrel.Lt{"datetime_start": filter.To})
+	}
+
+	switch filter.Status {
+	case entity.MailingScheduled:
+		b = b.Where(squirrel.Expr("datetime_start > ?", now))
+	case entity.MailingActive:
+		b = b.Where(squirrel.Expr("datetime_start <= ? AND datetime_end > ?", now, now))
+	case entity.MailingFinished:
+		b = b.Where(squirrel.Expr("datetime_end <= ?", now))
+	}
+
+	return b
+}
+
+// priority returns mailing priority or bulk one by default
+func priority(mailing *entity.Mailing) string {
+	if mailing.Priority == "" {
+		return entity.PriorityBulk
+	}
+
+	return mailing.Priority
+}
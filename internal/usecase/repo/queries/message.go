@@ -0,0 +1,84 @@
+package queries
+
+import (
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+const TableMessage = "message"
+
+// ActiveMailing keeps messages of mailings that aren't deleted
+var ActiveMailing = squirrel.Expr("mailing_id IN (SELECT id FROM " + TableMailing + " WHERE deleted_at IS NULL)")
+
+// MessageColumns are columns scanned by ScanMessage
+var MessageColumns = []string{
+	"id", "date_time_creation", "try", "delivery_status", "deferred", "mailing_id", "client_id",
+}
+
+// InsertMessage inserts message created at now
+func InsertMessage(b squirrel.StatementBuilderType, message *entity.Message, now squirrel.Sqlizer) squirrel.InsertBuilder {
+	return b.
+		Insert(TableMessage).
+		Columns("date_time_creation", "try", "delivery_status", "deferred", "mailing_id", "client_id").
+		Values(
+			now,
+			message.Try,
+			message.DeliveryStatus,
+			message.Deferred,
+			message.MailingID,
+			message.ClientID,
+		)
+}
+
+// SelectMessage selects message by ID, it's scanned by ScanMessage
+func SelectMessage(b squirrel.StatementBuilderType, message *entity.Message) squirrel.SelectBuilder {
+	return b.
+		Select(MessageColumns...).
+		From(TableMessage).
+		Where(squirrel.Eq{"id": message.ID})
+}
+
+// SelectMessagePage selects page of mailing messages by filter,
+// they're scanned by ScanMessage
+func SelectMessagePage(b squirrel.StatementBuilderType, filter *entity.MessageFilter) (squirrel.SelectBuilder, error) {
+	return Paginate(SelectMessages(b, filter), &filter.Page)
+}
+
+// SelectMessages selects all messages of mailing that isn't deleted by filter,
+// page of filter isn't used. They're scanned by ScanMessage.
+func SelectMessages(b squirrel.StatementBuilderType, filter *entity.MessageFilter) squirrel.SelectBuilder {
+	builder := b.
+		Select(MessageColumns...).
+		From(TableMessage).
+		Where(squirrel.Eq{"mailing_id": filter.MailingID}).
+		Where(ActiveMailing)
+
+	if !filter.From.IsZero() {
+		builder = builder.Where(squirrel.GtOrEq{"date_time_creation": filter.From})
+	}
+	if !filter.To.IsZero() {
+		builder = builder.Where(squirrel.Lt{"date_time_creation": filter.To})
+	}
+	switch filter.Status {
+	case entity.MessageDelivered:
+		builder = builder.Where("delivery_status")
+	case entity.MessageFailed:
+		builder = builder.Where("NOT delivery_status AND NOT deferred")
+	case entity.MessageDeferred:
+		builder = builder.Where("deferred")
+	}
+
+	return builder
+}
+
+func ScanMessage(row Row) (*entity.Message, error) {
+	var m entity.Message
+	err := row.Scan(
+		&m.ID, &m.DateTimeCreation, &m.Try, &m.DeliveryStatus, &m.Deferred, &m.MailingID, &m.ClientID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
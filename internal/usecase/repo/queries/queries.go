@@ -0,0 +1,42 @@
+// Package queries builds statements shared by SQL storages. Statements are
+// built by squirrel with placeholders of storage, current time is passed
+// as expression because storages get it differently.
+package queries
+
+import (
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// Row is row of query result of any driver
+type Row interface {
+	Scan(dest ...any) error
+}
+
+// Paginate adds cursor condition, order and limit of page to builder.
+// Sort of page is the column name, ties are ordered by id. One row more
+// than limit is selected to know whether the next page exists.
+func Paginate(b squirrel.SelectBuilder, page *entity.Page) (squirrel.SelectBuilder, error) {
+	after, err := page.After()
+	if err != nil {
+		return b, err
+	}
+
+	cmp, order := ">", " ASC"
+	if page.Order == entity.OrderDesc {
+		cmp, order = "<", " DESC"
+	}
+
+	if page.Sort == entity.SortID {
+		if after != nil {
+			b = b.Where("id "+cmp+" ?", after.ID)
+		}
+	} else {
+		if after != nil {
+			b = b.Where("("+page.Sort+", id) "+cmp+" (?, ?)", after.Time, after.ID)
+		}
+		b = b.OrderBy(page.Sort + order)
+	}
+
+	return b.OrderBy("id" + order).Limit(uint64(page.Limit) + 1), nil
+}
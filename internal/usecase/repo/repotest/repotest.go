@@ -0,0 +1,1009 @@
+// Package repotest is conformance suite of repositories. Every storage
+// runs it, so repositories of storages behave the same way.
+package repotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+// Repos are repositories of one storage
+type Repos struct {
+	Client    usecase.ClientRepo
+	Mailing   usecase.MailingRepo
+	Message   usecase.MessageRepo
+	Chunk     usecase.ChunkRepo
+	Partition usecase.PartitionRepo
+	Lease     usecase.LeaseRepo
+	Audit     usecase.AuditRepo
+	Import    usecase.ImportRepo
+	Export    usecase.ExportRepo
+	Tx        usecase.TxManager
+}
+
+// Run runs the suite, open returns repositories of empty storage
+func Run(t *testing.T, open func(t *testing.T) *Repos) {
+	tests := []struct {
+		name string
+		run  func(*testing.T, *Repos)
+	}{
+		{"Client", testClient},
+		{"Upsert", testUpsert},
+		{"Mailing", testMailing},
+		{"MailingPage", testMailingPage},
+		{"Stats", testStats},
+		{"Messages", testMessages},
+		{"Archive", testArchive},
+		{"Chunk", testChunk},
+		{"Lease", testLease},
+		{"Partition", testPartition},
+		{"Audit", testAudit},
+		{"Import", testImport},
+		{"Export", testExport},
+		{"Tx", testTx},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, open(t))
+		})
+	}
+}
+
+var ctx = context.Background()
+
+// hour is now truncated to seconds, so it's kept by every storage as is
+func hour(n int) time.Time {
+	return time.Now().UTC().Truncate(time.Second).Add(time.Duration(n) * time.Hour)
+}
+
+func newMailing(t *testing.T, r *Repos, start, end time.Time) *entity.Mailing {
+	t.Helper()
+
+	m := &entity.Mailing{
+		MessageText:    "Hello",
+		MobileOperator: "900",
+		Tag:            "gold",
+		FilterChoice:   "tag",
+		DateTimeStart:  start,
+		DateTimeEnd:    end,
+		IntervalStart:  start,
+		IntervalEnd:    end,
+	}
+	assert.Equal(t, r.Mailing.Create(ctx, m), nil)
+	assert.NotEqual(t, m.ID, int64(0))
+
+	return m
+}
+
+func newClient(t *testing.T, r *Repos, phone int64, tag string) *entity.Client {
+	t.Helper()
+
+	c := &entity.Client{PhoneNumber: phone, MobileOperator: 900, Tag: tag, TimeZone: 12}
+	assert.Equal(t, r.Client.Create(ctx, c), nil)
+	assert.NotEqual(t, c.ID, int64(0))
+
+	return c
+}
+
+// newMessages creates delivered, failed and deferred messages of mailing
+func newMessages(t *testing.T, r *Repos, m *entity.Mailing, c *entity.Client, delivered, failed, deferred int) {
+	t.Helper()
+
+	for i := 0; i < delivered+failed+deferred; i++ {
+		msg := &entity.Message{
+			MailingID:      m.ID,
+			ClientID:       c.ID,
+			DeliveryStatus: i < delivered,
+			Deferred:       i >= delivered+failed,
+		}
+		assert.Equal(t, r.Message.Create(ctx, msg), nil)
+	}
+}
+
+func ids[T any](items []T, id func(T) int64) []int64 {
+	res := make([]int64, 0, len(items))
+	for _, item := range items {
+		res = append(res, id(item))
+	}
+
+	return res
+}
+
+func testClient(t *testing.T, r *Repos) {
+	gold := newClient(t, r, 70000000001, "gold")
+	silver := newClient(t, r, 70000000002, "silver")
+
+	// test 1: client is read with all the fields
+	{
+		c, err := r.Client.Read(ctx, &entity.Client{ID: gold.ID})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, c, gold)
+	}
+
+	// test 2: phone number is unique, tag is one of enum
+	{
+		assert.NotEqual(t, r.Client.Create(ctx, &entity.Client{PhoneNumber: 70000000001, Tag: "vip"}), nil)
+		assert.NotEqual(t, r.Client.Create(ctx, &entity.Client{PhoneNumber: 70000000009, Tag: "bronze"}), nil)
+	}
+
+	// test 3: only set fields are updated, version is incremented
+	{
+		patch := &entity.Client{ID: silver.ID, Tag: "gold", Version: silver.Version}
+		assert.Equal(t, r.Client.Update(ctx, patch), nil)
+		assert.Equal(t, patch.Version, silver.Version+1)
+
+		c, err := r.Client.Read(ctx, &entity.Client{ID: silver.ID})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, c.Tag, "gold")
+		assert.Equal(t, c.PhoneNumber, silver.PhoneNumber)
+		assert.Equal(t, c.Version, patch.Version)
+	}
+
+	// test 4: client of stale version isn't updated or deleted
+	{
+		err := r.Client.Update(ctx, &entity.Client{ID: silver.ID, Tag: "vip", Version: silver.Version})
+		assert.Equal(t, errors.Is(err, entity.ErrConflict), true)
+
+		err = r.Client.Delete(ctx, silver)
+		assert.Equal(t, errors.Is(err, entity.ErrConflict), true)
+
+		c, err := r.Client.Read(ctx, &entity.Client{ID: silver.ID})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, c.Tag, "gold")
+		silver.Version = c.Version
+	}
+
+	// test 5: audience is filtered by tag and paged by ID
+	{
+		m := &entity.Mailing{FilterChoice: "tag", Tag: "gold"}
+		cs, err := r.Client.ReadPage(ctx, m, 0, 1)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(cs, func(c *entity.Client) int64 { return c.ID }), []int64{gold.ID})
+
+		cs, err = r.Client.ReadPage(ctx, m, gold.ID, 10)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(cs, func(c *entity.Client) int64 { return c.ID }), []int64{silver.ID})
+
+		cs, err = r.Client.ReadPage(ctx, &entity.Mailing{FilterChoice: "code", MobileOperator: "901"}, 0, 100)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(cs), 0)
+	}
+
+	// test 6: deleted client isn't read and isn't in audience, its phone could be taken
+	{
+		assert.Equal(t, r.Client.Delete(ctx, gold), nil)
+
+		_, err := r.Client.Read(ctx, &entity.Client{ID: gold.ID})
+		assert.Equal(t, errors.Is(err, entity.ErrNotFound), true)
+
+		cs, err := r.Client.ReadPage(ctx, &entity.Mailing{FilterChoice: "tag", Tag: "gold"}, 0, 100)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(cs, func(c *entity.Client) int64 { return c.ID }), []int64{silver.ID})
+
+		newClient(t, r, gold.PhoneNumber, "vip")
+	}
+
+	// test 7: client with taken phone isn't restored, client that isn't deleted is not found
+	{
+		assert.NotEqual(t, r.Client.Restore(ctx, gold), nil)
+
+		err := r.Client.Restore(ctx, silver)
+		assert.Equal(t, errors.Is(err, entity.ErrNotFound), true)
+	}
+}
+
+func testUpsert(t *testing.T, r *Repos) {
+	taken := newClient(t, r, 70000000001, "gold")
+	deleted := newClient(t, r, 70000000002, "gold")
+	assert.Equal(t, r.Client.Delete(ctx, deleted), nil)
+
+	// test 1: client of taken phone is updated, the others are created
+	{
+		n, err := r.Client.Upsert(ctx, entity.Clients{
+			{PhoneNumber: 70000000001, MobileOperator: 901, Tag: "vip", TimeZone: -4},
+			{PhoneNumber: 70000000002, MobileOperator: 902, Tag: "silver", TimeZone: 0},
+			{PhoneNumber: 70000000003, MobileOperator: 903, Tag: "silver", TimeZone: 8},
+		})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, n, int64(3))
+
+		c, err := r.Client.Read(ctx, &entity.Client{ID: taken.ID})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, c, &entity.Client{
+			ID: taken.ID, PhoneNumber: 70000000001, MobileOperator: 901, Tag: "vip", TimeZone: -4,
+			Version: taken.Version + 1,
+		})
+
+		cs, err := r.Client.ReadPage(ctx, &entity.Mailing{FilterChoice: "tag", Tag: "silver"}, 0, 100)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(cs), 2)
+		assert.NotEqual(t, cs[0].ID, deleted.ID)
+	}
+
+	// test 2: batch with invalid client isn't upserted
+	{
+		_, err := r.Client.Upsert(ctx, entity.Clients{
+			{PhoneNumber: 70000000004, MobileOperator: 904, Tag: "gold"},
+			{PhoneNumber: 70000000005, MobileOperator: 905, Tag: "bronze"},
+		})
+		assert.NotEqual(t, err, nil)
+
+		cs, err := r.Client.ReadPage(ctx, &entity.Mailing{FilterChoice: "tag", Tag: "gold"}, 0, 100)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(cs), 0)
+	}
+}
+
+func testMailing(t *testing.T, r *Repos) {
+	m := newMailing(t, r, hour(-1), hour(1))
+
+	// test 1: mailing is read with default priority and the first version
+	{
+		read, err := r.Mailing.Read(ctx, &entity.Mailing{ID: m.ID})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, read.MobileOperator, "900")
+		assert.Equal(t, read.Priority, entity.PriorityBulk)
+		assert.Equal(t, read.Version, int64(1))
+		assert.Equal(t, m.Version, int64(1))
+		assert.Equal(t, read.DateTimeStart.Equal(m.DateTimeStart), true)
+		assert.Equal(t, read.DeletedAt, (*time.Time)(nil))
+	}
+
+	// test 2: only set fields are updated, stale version isn't
+	{
+		end := hour(2)
+		err := r.Mailing.Update(ctx, &entity.Mailing{ID: m.ID, MessageText: "Bye", DateTimeEnd: end, Version: 1})
+		assert.Equal(t, err, nil)
+
+		err = r.Mailing.Update(ctx, &entity.Mailing{ID: m.ID, MessageText: "Hi", Version: 1})
+		assert.Equal(t, errors.Is(err, entity.ErrConflict), true)
+
+		read, err := r.Mailing.Read(ctx, &entity.Mailing{ID: m.ID})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, read.MessageText, "Bye")
+		assert.Equal(t, read.DateTimeEnd.Equal(end), true)
+		assert.Equal(t, read.DateTimeStart.Equal(m.DateTimeStart), true)
+		assert.Equal(t, read.Version, int64(2))
+		m.Version = read.Version
+	}
+
+	// test 3: enums are checked
+	{
+		bad := *m
+		bad.Tag = "bronze"
+		assert.NotEqual(t, r.Mailing.Create(ctx, &bad), nil)
+	}
+
+	// test 4: deleted mailing isn't read, it's restored once
+	{
+		assert.Equal(t, r.Mailing.Delete(ctx, m), nil)
+		_, err := r.Mailing.Read(ctx, &entity.Mailing{ID: m.ID})
+		assert.Equal(t, errors.Is(err, entity.ErrNotFound), true)
+
+		assert.Equal(t, r.Mailing.Restore(ctx, m), nil)
+		restored, err := r.Mailing.Read(ctx, &entity.Mailing{ID: m.ID})
+		assert.Equal(t, err, nil)
+		// Version of deleted mailing is stale after restoring
+		assert.Equal(t, restored.Version, m.Version+1)
+
+		err = r.Mailing.Restore(ctx, m)
+		assert.Equal(t, errors.Is(err, entity.ErrNotFound), true)
+	}
+}
+
+func testMailingPage(t *testing.T, r *Repos) {
+	var (
+		finished  = newMailing(t, r, hour(-3), hour(-2))
+		active    = newMailing(t, r, hour(-1), hour(1))
+		scheduled = newMailing(t, r, hour(2), hour(3))
+		deleted   = newMailing(t, r, hour(-2), hour(2))
+		tie       = newMailing(t, r, hour(-1), hour(4))
+	)
+	assert.Equal(t, r.Mailing.Delete(ctx, deleted), nil)
+
+	mailingID := func(m *entity.Mailing) int64 { return m.ID }
+
+	// test 1: pages by start time descending with ties by ID, deleted mailing is skipped
+	{
+		filter := &entity.MailingFilter{Page: entity.Page{Limit: 2, Sort: entity.SortDateTimeStart, Order: entity.OrderDesc}}
+		assert.Equal(t, filter.Validate(), nil)
+
+		var got []int64
+		for {
+			ms, next, err := r.Mailing.ReadPage(ctx, filter)
+			assert.Equal(t, err, nil)
+			got = append(got, ids(ms, mailingID)...)
+			if next == "" {
+				break
+			}
+			filter.Cursor = next
+		}
+		assert.Equal(t, got, []int64{scheduled.ID, tie.ID, active.ID, finished.ID})
+	}
+
+	// test 2: status filter
+	{
+		for status, want := range map[string][]int64{
+			entity.MailingScheduled: {scheduled.ID},
+			entity.MailingActive:    {active.ID, tie.ID},
+			entity.MailingFinished:  {finished.ID},
+		} {
+			filter := &entity.MailingFilter{Status: status}
+			assert.Equal(t, filter.Validate(), nil)
+
+			ms, next, err := r.Mailing.ReadPage(ctx, filter)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, next, "")
+			assert.Equal(t, ids(ms, mailingID), want)
+		}
+	}
+
+	// test 3: deleted mailing is selected with deleted, range bounds start time
+	{
+		filter := &entity.MailingFilter{WithDeleted: true, From: hour(-2), To: hour(2)}
+		assert.Equal(t, filter.Validate(), nil)
+
+		ms, _, err := r.Mailing.ReadPage(ctx, filter)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(ms, mailingID), []int64{active.ID, deleted.ID, tie.ID})
+	}
+
+	// test 4: cursor of another sort is rejected
+	{
+		filter := &entity.MailingFilter{Page: entity.Page{Limit: 1}}
+		assert.Equal(t, filter.Validate(), nil)
+		_, next, err := r.Mailing.ReadPage(ctx, filter)
+		assert.Equal(t, err, nil)
+
+		filter = &entity.MailingFilter{Page: entity.Page{Cursor: next, Sort: entity.SortDateTimeEnd}}
+		assert.Equal(t, filter.Validate(), nil)
+		_, _, err = r.Mailing.ReadPage(ctx, filter)
+		assert.Equal(t, errors.Is(err, entity.ErrInvalidFilter), true)
+	}
+}
+
+func testStats(t *testing.T, r *Repos) {
+	var (
+		c     = newClient(t, r, 70000000001, "gold")
+		first = newMailing(t, r, hour(-1), hour(1))
+		empty = newMailing(t, r, hour(0), hour(1))
+	)
+	newMessages(t, r, first, c, 2, 1, 3)
+
+	// test 1: deferred messages aren't failed, bounds are times of messages
+	{
+		s, err := r.Mailing.ReadWithMessages(ctx, first)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, s.MailingID, first.ID)
+		assert.Equal(t, s.Succesed, 2)
+		assert.Equal(t, s.Failed, 1)
+		assert.Equal(t, s.DateTimeStart.IsZero(), false)
+		assert.Equal(t, s.DateTimeEnd.Before(s.DateTimeStart), false)
+	}
+
+	// test 2: stats of page, mailing without messages has zero stats
+	{
+		filter := &entity.MailingFilter{Page: entity.Page{Limit: 1}}
+		assert.Equal(t, filter.Validate(), nil)
+
+		stats, next, err := r.Mailing.ReadStatsPage(ctx, filter)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(stats), 1)
+		assert.Equal(t, stats[0].Succesed, 2)
+
+		filter.Cursor = next
+		stats, next, err = r.Mailing.ReadStatsPage(ctx, filter)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, next, "")
+		assert.Equal(t, stats, []*entity.MailingStats{{MailingID: empty.ID}})
+	}
+
+	// test 3: message of unknown mailing isn't created
+	{
+		err := r.Message.Create(ctx, &entity.Message{MailingID: empty.ID + 100, ClientID: c.ID})
+		assert.NotEqual(t, err, nil)
+	}
+}
+
+func testMessages(t *testing.T, r *Repos) {
+	var (
+		c = newClient(t, r, 70000000001, "gold")
+		m = newMailing(t, r, hour(-1), hour(1))
+	)
+	newMessages(t, r, m, c, 3, 1, 1)
+
+	// test 1: pages of messages by ID, all the messages are read once
+	{
+		filter := &entity.MessageFilter{MailingID: m.ID, Page: entity.Page{Limit: 2}}
+		assert.Equal(t, filter.Validate(), nil)
+
+		var got entity.Messages
+		for {
+			ms, next, err := r.Message.ReadByMailing(ctx, filter)
+			assert.Equal(t, err, nil)
+			got = append(got, ms...)
+			if next == "" {
+				break
+			}
+			filter.Cursor = next
+		}
+		assert.Equal(t, len(got), 5)
+		for i := 1; i < len(got); i++ {
+			assert.Equal(t, got[i-1].ID < got[i].ID, true)
+		}
+
+		msg, err := r.Message.Read(ctx, &entity.Message{ID: got[0].ID})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, msg, got[0])
+	}
+
+	// test 2: status filter
+	{
+		for status, want := range map[string]int{
+			entity.MessageDelivered: 3,
+			entity.MessageFailed:    1,
+			entity.MessageDeferred:  1,
+		} {
+			filter := &entity.MessageFilter{MailingID: m.ID, Status: status}
+			assert.Equal(t, filter.Validate(), nil)
+
+			ms, _, err := r.Message.ReadByMailing(ctx, filter)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(ms), want)
+		}
+	}
+
+	// test 3: series counts messages by buckets of range
+	{
+		filter := &entity.SeriesFilter{MailingID: m.ID, From: hour(-1), To: hour(1), Bucket: entity.BucketHour}
+		assert.Equal(t, filter.Validate(), nil)
+
+		points, err := r.Message.ReadSeries(ctx, filter)
+		assert.Equal(t, err, nil)
+
+		var sum entity.SeriesPoint
+		for _, p := range points {
+			assert.Equal(t, p.Time.Equal(filter.BucketStart(p.Time)), true)
+			sum.Sent += p.Sent
+			sum.Failed += p.Failed
+			sum.Deferred += p.Deferred
+		}
+		assert.Equal(t, sum, entity.SeriesPoint{Sent: 3, Failed: 1, Deferred: 1})
+	}
+
+	// test 4: messages and stats of deleted mailing aren't read
+	{
+		assert.Equal(t, r.Mailing.Delete(ctx, m), nil)
+
+		messages := &entity.MessageFilter{MailingID: m.ID}
+		assert.Equal(t, messages.Validate(), nil)
+		ms, _, err := r.Message.ReadByMailing(ctx, messages)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(ms), 0)
+
+		filter := &entity.SeriesFilter{MailingID: m.ID, From: hour(-1), To: hour(1), Bucket: entity.BucketHour}
+		assert.Equal(t, filter.Validate(), nil)
+		points, err := r.Message.ReadSeries(ctx, filter)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(points), 0)
+
+		_, err = r.Mailing.ReadWithMessages(ctx, m)
+		assert.Equal(t, errors.Is(err, entity.ErrNotFound), true)
+
+		assert.Equal(t, r.Mailing.Restore(ctx, m), nil)
+	}
+}
+
+func testArchive(t *testing.T, r *Repos) {
+	var (
+		c        = newClient(t, r, 70000000001, "gold")
+		finished = newMailing(t, r, hour(-3), hour(-2))
+		active   = newMailing(t, r, hour(-1), hour(1))
+	)
+	newMessages(t, r, finished, c, 2, 1, 0)
+	assert.Equal(t, r.Chunk.Create(ctx, &entity.MailingChunk{MailingID: finished.ID, Seq: 1, Status: entity.ChunkDone}), nil)
+
+	// test 1: only finished mailing is archived
+	{
+		ids, err := r.Mailing.Archive(ctx, hour(0), 10)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids, []int64{finished.ID})
+
+		_, err = r.Mailing.Read(ctx, finished)
+		assert.NotEqual(t, err, nil)
+		_, err = r.Mailing.Read(ctx, active)
+		assert.Equal(t, err, nil)
+
+		ids, err = r.Mailing.Archive(ctx, hour(0), 10)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(ids), 0)
+	}
+
+	// test 2: archived mailing is restored with messages and stats, chunks aren't kept
+	{
+		assert.Equal(t, r.Mailing.Restore(ctx, finished), nil)
+
+		s, err := r.Mailing.ReadWithMessages(ctx, finished)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, s.Succesed, 2)
+		assert.Equal(t, s.Failed, 1)
+
+		filter := &entity.MessageFilter{MailingID: finished.ID}
+		assert.Equal(t, filter.Validate(), nil)
+		ms, _, err := r.Message.ReadByMailing(ctx, filter)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(ms), 3)
+
+		cs, err := r.Chunk.ReadByMailing(ctx, finished)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(cs), 0)
+	}
+}
+
+func testChunk(t *testing.T, r *Repos) {
+	m := newMailing(t, r, hour(-1), hour(1))
+	chunk := func(seq int, status string) *entity.MailingChunk {
+		return &entity.MailingChunk{MailingID: m.ID, Seq: seq, AfterID: int64(seq * 10), LastID: int64(seq*10 + 10), Status: status}
+	}
+
+	// test 1: existing chunk isn't changed by Create
+	{
+		assert.Equal(t, r.Chunk.Create(ctx, chunk(2, entity.ChunkCreated)), nil)
+		assert.Equal(t, r.Chunk.Create(ctx, chunk(1, entity.ChunkCreated)), nil)
+		assert.Equal(t, r.Chunk.Create(ctx, chunk(1, entity.ChunkPublished)), nil)
+
+		cs, err := r.Chunk.ReadByMailing(ctx, m)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, cs, entity.MailingChunks{chunk(1, entity.ChunkCreated), chunk(2, entity.ChunkCreated)})
+	}
+
+	// test 2: status isn't moved back
+	{
+		assert.Equal(t, r.Chunk.SetStatus(ctx, chunk(1, entity.ChunkDone)), nil)
+		assert.Equal(t, r.Chunk.SetStatus(ctx, chunk(1, entity.ChunkPublished)), nil)
+
+		c, err := r.Chunk.Read(ctx, chunk(1, ""))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, c.Status, entity.ChunkDone)
+	}
+
+	// test 3: mailing is completed after sealing when all the chunks are done
+	{
+		ok, err := r.Chunk.Completed(ctx, m)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ok, false)
+
+		assert.Equal(t, r.Chunk.Seal(ctx, m, 2), nil)
+		ok, err = r.Chunk.Completed(ctx, m)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ok, false)
+
+		assert.Equal(t, r.Chunk.SetStatus(ctx, chunk(2, entity.ChunkDone)), nil)
+		ok, err = r.Chunk.Completed(ctx, m)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ok, true)
+	}
+}
+
+func testLease(t *testing.T, r *Repos) {
+	m := &entity.Mailing{ID: 1}
+
+	// test 1: chunk is leased once until release, chunks are leased independently
+	{
+		release, ok, err := r.Lease.Acquire(ctx, m, 1)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ok, true)
+
+		_, ok, err = r.Lease.Acquire(ctx, m, 1)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ok, false)
+
+		other, ok, err := r.Lease.Acquire(ctx, m, 2)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ok, true)
+		other()
+
+		release()
+		release, ok, err = r.Lease.Acquire(ctx, m, 1)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ok, true)
+		release()
+	}
+
+	// test 2: retention is locked once
+	{
+		release, ok, err := r.Partition.Lock(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ok, true)
+
+		_, ok, err = r.Partition.Lock(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ok, false)
+		release()
+	}
+}
+
+func testPartition(t *testing.T, r *Repos) {
+	var (
+		c = newClient(t, r, 70000000001, "gold")
+		m = newMailing(t, r, hour(-1), hour(1))
+	)
+	month := entity.MonthStart(time.Now())
+	assert.Equal(t, r.Partition.Create(ctx, month, 1), nil)
+	newMessages(t, r, m, c, 1, 1, 0)
+
+	future := time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+	months := func() []time.Time {
+		ps, err := r.Partition.List(ctx)
+		assert.Equal(t, err, nil)
+
+		var res []time.Time
+		for _, p := range ps {
+			res = append(res, p.Month)
+		}
+		return res
+	}
+
+	// test 1: partitions are created ahead and listed by month
+	{
+		assert.Equal(t, r.Partition.Create(ctx, future, 2), nil)
+		got := months()
+		assert.Equal(t, got[len(got)-2:], []time.Time{future, future.AddDate(0, 1, 0)})
+	}
+
+	// test 2: partition is exported with header
+	{
+		var buf bytes.Buffer
+		p := &entity.Partition{Name: "message_p" + month.Format("200601"), Month: month}
+		assert.Equal(t, r.Partition.Export(ctx, p, &buf), nil)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Equal(t, len(lines), 3)
+		assert.Equal(t, strings.HasPrefix(lines[0], "id,date_time_creation,"), true)
+	}
+
+	// test 3: messages are dropped with partition, stats are kept
+	{
+		p := &entity.Partition{Name: "message_p" + month.Format("200601"), Month: month}
+		assert.Equal(t, r.Partition.Drop(ctx, p), nil)
+		for _, got := range months() {
+			assert.NotEqual(t, got, month)
+		}
+
+		filter := &entity.MessageFilter{MailingID: m.ID}
+		assert.Equal(t, filter.Validate(), nil)
+		ms, _, err := r.Message.ReadByMailing(ctx, filter)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(ms), 0)
+
+		s, err := r.Mailing.ReadWithMessages(ctx, m)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, s.Succesed, 1)
+		assert.Equal(t, s.Failed, 1)
+	}
+}
+
+func testAudit(t *testing.T, r *Repos) {
+	diff, err := entity.Diff(&entity.Client{ID: 1, Tag: "gold"}, &entity.Client{ID: 1, Tag: "vip"})
+	assert.Equal(t, err, nil)
+
+	records := []*entity.AuditRecord{
+		{CreatedAt: hour(-2), Actor: "alice", Action: entity.AuditCreate, Entity: entity.AuditClient, EntityID: 1},
+		{CreatedAt: hour(-1), Actor: "bob", Action: entity.AuditUpdate, Entity: entity.AuditClient, EntityID: 1,
+			Diff: diff, RequestID: "req-1"},
+		{CreatedAt: hour(0), Actor: "alice", Action: entity.AuditDelete, Entity: entity.AuditMailing, EntityID: 1},
+	}
+	for _, rec := range records {
+		assert.Equal(t, r.Audit.Create(ctx, rec), nil)
+		assert.NotEqual(t, rec.ID, int64(0))
+	}
+
+	readPage := func(filter entity.AuditFilter) ([]*entity.AuditRecord, string) {
+		t.Helper()
+
+		assert.Equal(t, filter.Validate(), nil)
+		recs, next, err := r.Audit.ReadPage(ctx, &filter)
+		assert.Equal(t, err, nil)
+
+		return recs, next
+	}
+	recordID := func(rec *entity.AuditRecord) int64 { return rec.ID }
+
+	// test 1: record is read with its diff
+	{
+		recs, _ := readPage(entity.AuditFilter{Actor: "bob"})
+		assert.Equal(t, len(recs), 1)
+		assert.Equal(t, recs[0].RequestID, "req-1")
+		assert.Equal(t, recs[0].CreatedAt.Equal(hour(-1)), true)
+		assert.Equal(t, string(recs[0].Diff["tag"].Before), `"gold"`)
+		assert.Equal(t, string(recs[0].Diff["tag"].After), `"vip"`)
+		assert.Equal(t, len(recs[0].Diff), 1)
+	}
+
+	// test 2: records are filtered by entity, actor and time range
+	{
+		recs, _ := readPage(entity.AuditFilter{Entity: entity.AuditClient, EntityID: 1})
+		assert.Equal(t, ids(recs, recordID), []int64{records[0].ID, records[1].ID})
+
+		recs, _ = readPage(entity.AuditFilter{Actor: "alice", From: hour(-2).Add(time.Second)})
+		assert.Equal(t, ids(recs, recordID), []int64{records[2].ID})
+
+		recs, _ = readPage(entity.AuditFilter{From: hour(-2), To: hour(0)})
+		assert.Equal(t, ids(recs, recordID), []int64{records[0].ID, records[1].ID})
+	}
+
+	// test 3: records are paged by time in descending order
+	{
+		page := entity.Page{Limit: 2, Sort: entity.SortCreatedAt, Order: entity.OrderDesc}
+		recs, next := readPage(entity.AuditFilter{Page: page})
+		assert.Equal(t, ids(recs, recordID), []int64{records[2].ID, records[1].ID})
+		assert.NotEqual(t, next, "")
+
+		page.Cursor = next
+		recs, next = readPage(entity.AuditFilter{Page: page})
+		assert.Equal(t, ids(recs, recordID), []int64{records[0].ID})
+		assert.Equal(t, next, "")
+	}
+
+	// test 4: archived mailings and upserted batches of imports are recorded
+	{
+		archived := &entity.AuditRecord{
+			CreatedAt: hour(1), Actor: "carol", Action: entity.AuditArchive, Entity: entity.AuditMailing, EntityID: 2,
+		}
+		upserted := &entity.AuditRecord{
+			CreatedAt: hour(1), Actor: "carol", Action: entity.AuditUpsert, Entity: entity.AuditImport, EntityID: 1,
+		}
+		assert.Equal(t, r.Audit.Create(ctx, archived), nil)
+		assert.Equal(t, r.Audit.Create(ctx, upserted), nil)
+
+		recs, _ := readPage(entity.AuditFilter{Entity: entity.AuditImport})
+		assert.Equal(t, ids(recs, recordID), []int64{upserted.ID})
+		assert.Equal(t, recs[0].Action, entity.AuditUpsert)
+	}
+}
+
+func testImport(t *testing.T, r *Repos) {
+	job := &entity.ImportJob{
+		Format: entity.ImportCSV, Status: entity.ImportPending, Actor: "alice", RequestID: "req-1", CreatedAt: hour(0),
+	}
+	assert.Equal(t, r.Import.Create(ctx, job), nil)
+	assert.NotEqual(t, job.ID, int64(0))
+
+	// test 1: job is read with its progress
+	{
+		finished := hour(1)
+		job.Status, job.Total, job.Imported, job.Failed = entity.ImportDone, 3, 2, 1
+		job.FinishedAt = &finished
+		assert.Equal(t, r.Import.Update(ctx, job), nil)
+
+		j, err := r.Import.Read(ctx, &entity.ImportJob{ID: job.ID})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, j.Status, entity.ImportDone)
+		assert.Equal(t, j.Imported, int64(2))
+		assert.Equal(t, j.Actor, "alice")
+		assert.Equal(t, j.RequestID, "req-1")
+		assert.Equal(t, j.CreatedAt.Equal(hour(0)), true)
+		assert.Equal(t, j.FinishedAt.Equal(finished), true)
+
+		_, err = r.Import.Read(ctx, &entity.ImportJob{ID: job.ID + 1})
+		assert.Equal(t, errors.Is(err, entity.ErrNotFound), true)
+	}
+
+	// test 2: errors of job are paged in order of insertion
+	{
+		errs := []*entity.ImportRowError{
+			{JobID: job.ID, Line: 2, Field: "tag", Value: "bronze", Message: "must be silver, gold or vip"},
+			{JobID: job.ID, Line: 2, Field: "time_zone", Value: "99", Message: "out of range"},
+			{JobID: job.ID, Line: 5, Message: "wrong number of fields"},
+		}
+		assert.Equal(t, r.Import.CreateErrors(ctx, errs), nil)
+
+		read, err := r.Import.ReadErrors(ctx, job, 0, 2)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(read), 2)
+		assert.Equal(t, read[1].Field, "time_zone")
+
+		read, err = r.Import.ReadErrors(ctx, job, read[1].ID, 2)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(read), 1)
+		assert.Equal(t, read[0].Line, int64(5))
+		assert.Equal(t, read[0].Field, "")
+	}
+
+	// test 3: only pending and running jobs are unfinished
+	{
+		pending := &entity.ImportJob{Format: entity.ImportCSV, Status: entity.ImportPending, CreatedAt: hour(0)}
+		running := &entity.ImportJob{Format: entity.ImportNDJSON, Status: entity.ImportPending, CreatedAt: hour(0)}
+		assert.Equal(t, r.Import.Create(ctx, pending), nil)
+		assert.Equal(t, r.Import.Create(ctx, running), nil)
+		running.Status = entity.ImportRunning
+		assert.Equal(t, r.Import.Update(ctx, running), nil)
+
+		jobs, err := r.Import.ReadUnfinished(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(jobs, func(j *entity.ImportJob) int64 { return j.ID }), []int64{pending.ID, running.ID})
+		assert.Equal(t, jobs[1].Status, entity.ImportRunning)
+		assert.Equal(t, jobs[1].Format, entity.ImportNDJSON)
+	}
+}
+
+func testExport(t *testing.T, r *Repos) {
+	var (
+		gold    = newClient(t, r, 70000000001, "gold")
+		vip     = newClient(t, r, 70000000002, "vip")
+		deleted = newClient(t, r, 70000000003, "gold")
+		m       = newMailing(t, r, hour(-1), hour(1))
+	)
+	assert.Equal(t, r.Client.Delete(ctx, deleted), nil)
+	newMessages(t, r, m, gold, 2, 1, 0)
+
+	// test 1: clients of audience are exported with all the fields in order of ID
+	{
+		var got entity.Clients
+		err := r.Client.Export(ctx, &entity.Mailing{}, func(c *entity.Client) error {
+			got = append(got, c)
+			return nil
+		})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, got, entity.Clients{gold, vip})
+
+		got = nil
+		err = r.Client.Export(ctx, &entity.Mailing{FilterChoice: "tag", Tag: "vip"}, func(c *entity.Client) error {
+			got = append(got, c)
+			return nil
+		})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, got, entity.Clients{vip})
+	}
+
+	// test 2: messages are exported by filter in order of ID, error of f stops export
+	{
+		var got entity.Messages
+		err := r.Message.Export(ctx, &entity.MessageFilter{MailingID: m.ID}, func(msg *entity.Message) error {
+			got = append(got, msg)
+			return nil
+		})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(got), 3)
+		assert.Equal(t, got[0].ID < got[1].ID && got[1].ID < got[2].ID, true)
+
+		filter := &entity.MessageFilter{MailingID: m.ID, Status: entity.MessageFailed}
+		errStop := errors.New("stop")
+		calls := 0
+		err = r.Message.Export(ctx, filter, func(msg *entity.Message) error {
+			calls++
+			assert.Equal(t, msg.DeliveryStatus, false)
+			return errStop
+		})
+		assert.Equal(t, errors.Is(err, errStop), true)
+		assert.Equal(t, calls, 1)
+	}
+
+	// test 3: job is read with its request and result
+	{
+		job := &entity.ExportJob{
+			Request: entity.ExportRequest{
+				Kind: entity.ExportMessages, Format: entity.ExportParquet, MailingID: m.ID, From: hour(-1),
+			},
+			Status:    entity.ExportPending,
+			CreatedAt: hour(0),
+		}
+		assert.Equal(t, r.Export.Create(ctx, job), nil)
+		assert.NotEqual(t, job.ID, int64(0))
+
+		finished := hour(1)
+		job.Status, job.Rows, job.File, job.FinishedAt = entity.ExportDone, 3, "export_1.parquet", &finished
+		assert.Equal(t, r.Export.Update(ctx, job), nil)
+
+		j, err := r.Export.Read(ctx, &entity.ExportJob{ID: job.ID})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, j.Status, entity.ExportDone)
+		assert.Equal(t, j.Rows, int64(3))
+		assert.Equal(t, j.File, "export_1.parquet")
+		assert.Equal(t, j.Request.MailingID, m.ID)
+		assert.Equal(t, j.Request.From.Equal(hour(-1)), true)
+		assert.Equal(t, j.FinishedAt.Equal(finished), true)
+
+		_, err = r.Export.Read(ctx, &entity.ExportJob{ID: job.ID + 1})
+		assert.Equal(t, errors.Is(err, entity.ErrNotFound), true)
+	}
+
+	// test 4: only pending and running jobs are unfinished
+	{
+		req := entity.ExportRequest{Kind: entity.ExportClients, Format: entity.ExportCSV}
+		pending := &entity.ExportJob{Request: req, Status: entity.ExportPending, CreatedAt: hour(0)}
+		running := &entity.ExportJob{Request: req, Status: entity.ExportPending, CreatedAt: hour(0)}
+		assert.Equal(t, r.Export.Create(ctx, pending), nil)
+		assert.Equal(t, r.Export.Create(ctx, running), nil)
+		running.Status = entity.ExportRunning
+		assert.Equal(t, r.Export.Update(ctx, running), nil)
+
+		jobs, err := r.Export.ReadUnfinished(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(jobs, func(j *entity.ExportJob) int64 { return j.ID }), []int64{pending.ID, running.ID})
+		assert.Equal(t, jobs[1].Status, entity.ExportRunning)
+		assert.Equal(t, jobs[1].Request.Kind, entity.ExportClients)
+	}
+}
+
+func testTx(t *testing.T, r *Repos) {
+	errAbort := errors.New("abort")
+
+	// test 1: changes of failed transaction are rolled back, its error is returned
+	{
+		var c entity.Client
+		err := r.Tx.Do(ctx, func(ctx context.Context) error {
+			c = entity.Client{PhoneNumber: 70000000001, Tag: "gold"}
+			if err := r.Client.Create(ctx, &c); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		assert.Equal(t, errors.Is(err, errAbort), true)
+
+		_, err = r.Client.Read(ctx, &c)
+		assert.NotEqual(t, err, nil)
+	}
+
+	// test 2: failed nested transaction is rolled back to savepoint
+	{
+		outer := entity.Client{PhoneNumber: 70000000002, Tag: "gold"}
+		inner := entity.Client{PhoneNumber: 70000000003, Tag: "gold"}
+
+		err := r.Tx.Do(ctx, func(ctx context.Context) error {
+			if err := r.Client.Create(ctx, &outer); err != nil {
+				return err
+			}
+
+			err := r.Tx.Do(ctx, func(ctx context.Context) error {
+				if err := r.Client.Create(ctx, &inner); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			assert.Equal(t, errors.Is(err, errAbort), true)
+
+			return nil
+		})
+		assert.Equal(t, err, nil)
+
+		_, err = r.Client.Read(ctx, &outer)
+		assert.Equal(t, err, nil)
+		_, err = r.Client.Read(ctx, &inner)
+		assert.NotEqual(t, err, nil)
+	}
+
+	// test 3: rows updated and deleted by failed transaction are restored
+	{
+		c := newClient(t, r, 70000000004, "gold")
+		m := newMailing(t, r, hour(-1), hour(1))
+
+		err := r.Tx.Do(ctx, func(ctx context.Context) error {
+			changed := *c
+			changed.Tag = "vip"
+			if err := r.Client.Update(ctx, &changed); err != nil {
+				return err
+			}
+			if err := r.Mailing.Delete(ctx, m); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		assert.Equal(t, errors.Is(err, errAbort), true)
+
+		read, err := r.Client.Read(ctx, c)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, read.Tag, "gold")
+		_, err = r.Mailing.Read(ctx, m)
+		assert.Equal(t, err, nil)
+	}
+}
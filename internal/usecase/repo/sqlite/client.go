@@ -0,0 +1,244 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
+)
+
+// upsertBatch bounds rows of one statement, so its variables are within SQLite limit
+const upsertBatch = 1000
+
+// exportPage is number of rows read at once by Export
+const exportPage = 1000
+
+type ClientRepo struct {
+	Builder squirrel.StatementBuilderType
+	db      *sql.DB
+}
+
+func NewClient(db *sql.DB) *ClientRepo {
+	return &ClientRepo{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		db:      db,
+	}
+}
+
+func (r *ClientRepo) Create(ctx context.Context, client *entity.Client) error {
+	query, args, err := queries.InsertClient(r.Builder, client).ToSql()
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Create(): %w", err)
+	}
+
+	err = querier(ctx, r.db).QueryRow(ctx, query, args...).Scan(&client.ID, &client.Version)
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Create(): %w", err)
+	}
+
+	return nil
+}
+
+// Update changes client of the same version and increments its version.
+// entity.ErrConflict is returned if version differs or client is deleted.
+func (r *ClientRepo) Update(ctx context.Context, client *entity.Client) error {
+	query, args, err := queries.UpdateClient(r.Builder, client).ToSql()
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Update(): %w", err)
+	}
+
+	res, err := querier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Update(): %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("ClientRepo - Update(): client %d: %w", client.ID, entity.ErrConflict)
+	}
+	client.Version++
+
+	return nil
+}
+
+// Delete marks client deleted, so it's excluded from audiences.
+// Messages of client are kept. Version is checked like by Update.
+func (r *ClientRepo) Delete(ctx context.Context, client *entity.Client) error {
+	query, args, err := queries.DeleteClient(r.Builder, client, now()).ToSql()
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Delete(): %w", err)
+	}
+
+	res, err := querier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Delete(): %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("ClientRepo - Delete(): client %d: %w", client.ID, entity.ErrConflict)
+	}
+	client.Version++
+
+	return nil
+}
+
+// Restore returns deleted client. entity.ErrNotFound is returned if client
+// isn't deleted. Restore fails if phone number is taken by another client.
+func (r *ClientRepo) Restore(ctx context.Context, client *entity.Client) error {
+	query, args, err := queries.RestoreClient(r.Builder, client).ToSql()
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Restore(): %w", err)
+	}
+
+	res, err := querier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ClientRepo - Restore(): %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("ClientRepo - Restore(): client %d: %w", client.ID, entity.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *ClientRepo) Read(ctx context.Context, client *entity.Client) (*entity.Client, error) {
+	query, args, err := queries.SelectClient(r.Builder, client).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ClientRepo - Read(): %w", err)
+	}
+
+	c, err := queries.ScanClient(querier(ctx, r.db).QueryRow(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("ClientRepo - Read(): client %d: %w", client.ID, entity.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ClientRepo - Read(): %w", err)
+	}
+
+	return c, nil
+}
+
+// ReadPage returns up to limit clients of mailing audience with ID
+// greater than afterID ordered by ID
+func (r *ClientRepo) ReadPage(ctx context.Context, mailing *entity.Mailing, afterID int64, limit int) (
+	entity.Clients, error,
+) {
+	query, args, err := queries.SelectAudience(r.Builder, mailing).
+		Where(squirrel.Gt{"id": afterID}).
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ClientRepo - ReadPage(): %w", err)
+	}
+
+	cs, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ClientRepo - ReadPage(): %w", err)
+	}
+
+	return cs, nil
+}
+
+// Upsert creates clients, clients of phone numbers taken by not deleted
+// ones are updated and their versions are incremented. Clients are
+// inserted by statements of upsertBatch rows in one transaction.
+func (r *ClientRepo) Upsert(ctx context.Context, clients entity.Clients) (int64, error) {
+	var n int64
+
+	err := NewTxManager(r.db).Do(ctx, func(ctx context.Context) error {
+		for len(clients) > 0 {
+			batch := clients[:min(upsertBatch, len(clients))]
+			clients = clients[len(batch):]
+
+			query, args, err := queries.UpsertClients(r.Builder, batch).ToSql()
+			if err != nil {
+				return err
+			}
+
+			res, err := querier(ctx, r.db).Exec(ctx, query, args...)
+			if err != nil {
+				return err
+			}
+
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			n += affected
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ClientRepo - Upsert(): %w", err)
+	}
+
+	return n, nil
+}
+
+// Export calls f for every client of mailing audience ordered by ID.
+// Database has only one connection, so clients are read by keyset pages
+// of exportPage and f isn't called while connection is taken. Export
+// stops at the first error of f.
+func (r *ClientRepo) Export(ctx context.Context, mailing *entity.Mailing, f func(*entity.Client) error) error {
+	var afterID int64
+	for {
+		query, args, err := queries.SelectClients(r.Builder, mailing).
+			Where(squirrel.Gt{"id": afterID}).
+			Limit(exportPage).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("ClientRepo - Export(): %w", err)
+		}
+
+		rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("ClientRepo - Export(): %w", err)
+		}
+
+		var cs entity.Clients
+		for rows.Next() {
+			c, err := queries.ScanClient(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("ClientRepo - Export(): %w", err)
+			}
+			cs = append(cs, c)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("ClientRepo - Export(): %w", err)
+		}
+
+		for _, c := range cs {
+			if err = f(c); err != nil {
+				return fmt.Errorf("ClientRepo - Export(): %w", err)
+			}
+		}
+
+		if len(cs) < exportPage {
+			return nil
+		}
+		afterID = cs[len(cs)-1].ID
+	}
+}
+
+func (r *ClientRepo) query(ctx context.Context, query string, args ...any) (entity.Clients, error) {
+	rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cs entity.Clients
+	for rows.Next() {
+		c, err := queries.ScanRecipient(rows)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+
+	return cs, rows.Err()
+}
@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
+)
+
+type ExportRepo struct {
+	Builder squirrel.StatementBuilderType
+	db      *sql.DB
+}
+
+func NewExport(db *sql.DB) *ExportRepo {
+	return &ExportRepo{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		db:      db,
+	}
+}
+
+func (r *ExportRepo) Create(ctx context.Context, job *entity.ExportJob) error {
+	builder, err := queries.InsertExportJob(r.Builder, job)
+	if err != nil {
+		return fmt.Errorf("ExportRepo - Create(): %w", err)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("ExportRepo - Create(): %w", err)
+	}
+
+	err = querier(ctx, r.db).QueryRow(ctx, query, args...).Scan(&job.ID)
+	if err != nil {
+		return fmt.Errorf("ExportRepo - Create(): %w", err)
+	}
+
+	return nil
+}
+
+// Update sets status, number of rows, file and error of job
+func (r *ExportRepo) Update(ctx context.Context, job *entity.ExportJob) error {
+	query, args, err := queries.UpdateExportJob(r.Builder, job).ToSql()
+	if err != nil {
+		return fmt.Errorf("ExportRepo - Update(): %w", err)
+	}
+
+	res, err := querier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ExportRepo - Update(): %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("ExportRepo - Update(): job %d: %w", job.ID, entity.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *ExportRepo) Read(ctx context.Context, job *entity.ExportJob) (*entity.ExportJob, error) {
+	query, args, err := queries.SelectExportJob(r.Builder, job).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ExportRepo - Read(): %w", err)
+	}
+
+	j, err := queries.ScanExportJob(querier(ctx, r.db).QueryRow(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("ExportRepo - Read(): job %d: %w", job.ID, entity.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ExportRepo - Read(): %w", err)
+	}
+
+	return j, nil
+}
+
+// ReadUnfinished returns pending and running jobs ordered by ID
+func (r *ExportRepo) ReadUnfinished(ctx context.Context) ([]*entity.ExportJob, error) {
+	query, args, err := queries.SelectUnfinishedExportJobs(r.Builder).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ExportRepo - ReadUnfinished(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ExportRepo - ReadUnfinished(): %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entity.ExportJob
+	for rows.Next() {
+		j, err := queries.ScanExportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ExportRepo - ReadUnfinished(): %w", err)
+		}
+		jobs = append(jobs, j)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ExportRepo - ReadUnfinished(): %w", err)
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,153 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
+)
+
+type ImportRepo struct {
+	Builder squirrel.StatementBuilderType
+	db      *sql.DB
+}
+
+func NewImport(db *sql.DB) *ImportRepo {
+	return &ImportRepo{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		db:      db,
+	}
+}
+
+func (r *ImportRepo) Create(ctx context.Context, job *entity.ImportJob) error {
+	query, args, err := queries.InsertImportJob(r.Builder, job).ToSql()
+	if err != nil {
+		return fmt.Errorf("ImportRepo - Create(): %w", err)
+	}
+
+	err = querier(ctx, r.db).QueryRow(ctx, query, args...).Scan(&job.ID)
+	if err != nil {
+		return fmt.Errorf("ImportRepo - Create(): %w", err)
+	}
+
+	return nil
+}
+
+// Update sets status, counters and error of job
+func (r *ImportRepo) Update(ctx context.Context, job *entity.ImportJob) error {
+	query, args, err := queries.UpdateImportJob(r.Builder, job).ToSql()
+	if err != nil {
+		return fmt.Errorf("ImportRepo - Update(): %w", err)
+	}
+
+	res, err := querier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ImportRepo - Update(): %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("ImportRepo - Update(): job %d: %w", job.ID, entity.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *ImportRepo) Read(ctx context.Context, job *entity.ImportJob) (*entity.ImportJob, error) {
+	query, args, err := queries.SelectImportJob(r.Builder, job).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ImportRepo - Read(): %w", err)
+	}
+
+	j, err := queries.ScanImportJob(querier(ctx, r.db).QueryRow(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("ImportRepo - Read(): job %d: %w", job.ID, entity.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ImportRepo - Read(): %w", err)
+	}
+
+	return j, nil
+}
+
+// CreateErrors inserts invalid rows by statements of upsertBatch rows,
+// IDs of errors aren't set
+func (r *ImportRepo) CreateErrors(ctx context.Context, errs []*entity.ImportRowError) error {
+	for len(errs) > 0 {
+		batch := errs[:min(upsertBatch, len(errs))]
+		errs = errs[len(batch):]
+
+		query, args, err := queries.InsertImportErrors(r.Builder, batch).ToSql()
+		if err != nil {
+			return fmt.Errorf("ImportRepo - CreateErrors(): %w", err)
+		}
+
+		if _, err = querier(ctx, r.db).Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("ImportRepo - CreateErrors(): %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReadErrors returns up to limit errors of job with ID greater than afterID ordered by ID
+func (r *ImportRepo) ReadErrors(ctx context.Context, job *entity.ImportJob, afterID int64, limit int) (
+	[]*entity.ImportRowError, error,
+) {
+	query, args, err := queries.SelectImportErrors(r.Builder, job, afterID, limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ImportRepo - ReadErrors(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ImportRepo - ReadErrors(): %w", err)
+	}
+	defer rows.Close()
+
+	var errs []*entity.ImportRowError
+	for rows.Next() {
+		e, err := queries.ScanImportError(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ImportRepo - ReadErrors(): %w", err)
+		}
+		errs = append(errs, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ImportRepo - ReadErrors(): %w", err)
+	}
+
+	return errs, nil
+}
+
+// ReadUnfinished returns pending and running jobs ordered by ID
+func (r *ImportRepo) ReadUnfinished(ctx context.Context) ([]*entity.ImportJob, error) {
+	query, args, err := queries.SelectUnfinishedImportJobs(r.Builder).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ImportRepo - ReadUnfinished(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ImportRepo - ReadUnfinished(): %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entity.ImportJob
+	for rows.Next() {
+		j, err := queries.ScanImportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ImportRepo - ReadUnfinished(): %w", err)
+		}
+		jobs = append(jobs, j)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ImportRepo - ReadUnfinished(): %w", err)
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// LeaseRepo grants ownership of mailing chunk between workers. Database is
+// used by one process, so leases are held in memory of repository and
+// workers must share it. Chunk 0 is fan-out of mailing.
+type LeaseRepo struct {
+	locks *locks
+}
+
+func NewLease() *LeaseRepo {
+	return &LeaseRepo{newLocks()}
+}
+
+// Acquire tries to lock mailing chunk without waiting. If chunk is locked
+// by another worker ok is false. Release must be called to unlock it.
+func (r *LeaseRepo) Acquire(_ context.Context, mailing *entity.Mailing, chunk int) (
+	release func(), ok bool, err error,
+) {
+	release, ok = r.locks.tryLock(fmt.Sprintf("%d:%d", mailing.ID, chunk))
+
+	return release, ok, nil
+}
+
+// locks are named locks of one process
+type locks struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func newLocks() *locks {
+	return &locks{held: make(map[string]bool)}
+}
+
+// tryLock takes lock of key without waiting, release unlocks it
+func (l *locks) tryLock(key string) (release func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[key] {
+		return nil, false
+	}
+	l.held[key] = true
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		delete(l.held, key)
+	}, true
+}
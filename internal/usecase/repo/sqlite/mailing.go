@@ -0,0 +1,353 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
+)
+
+type MailingRepo struct {
+	Builder squirrel.StatementBuilderType
+	db      *sql.DB
+	tx      *TxManager
+}
+
+func NewMailing(db *sql.DB) *MailingRepo {
+	return &MailingRepo{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		db:      db,
+		tx:      NewTxManager(db),
+	}
+}
+
+func (r *MailingRepo) Create(ctx context.Context, mailing *entity.Mailing) error {
+	query, args, err := queries.InsertMailing(r.Builder, mailing).ToSql()
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Create(): %w", err)
+	}
+
+	err = querier(ctx, r.db).QueryRow(ctx, query, args...).Scan(&mailing.ID, &mailing.Version)
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Create(): %w", err)
+	}
+
+	return nil
+}
+
+// Update changes mailing of the same version and increments its version.
+// entity.ErrConflict is returned if version differs or mailing is deleted.
+func (r *MailingRepo) Update(ctx context.Context, mailing *entity.Mailing) error {
+	query, args, err := queries.UpdateMailing(r.Builder, mailing).ToSql()
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Update(): %w", err)
+	}
+
+	res, err := querier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Update(): %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("MailingRepo - Update(): mailing %d: %w", mailing.ID, entity.ErrConflict)
+	}
+	mailing.Version++
+
+	return nil
+}
+
+// Delete marks mailing deleted, its messages are kept.
+// Version is checked like by Update.
+func (r *MailingRepo) Delete(ctx context.Context, mailing *entity.Mailing) error {
+	query, args, err := queries.DeleteMailing(r.Builder, mailing, now()).ToSql()
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Delete(): %w", err)
+	}
+
+	res, err := querier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Delete(): %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("MailingRepo - Delete(): mailing %d: %w", mailing.ID, entity.ErrConflict)
+	}
+	mailing.Version++
+
+	return nil
+}
+
+// Restore returns deleted or archived mailing with its messages.
+// entity.ErrNotFound is returned if mailing is neither deleted nor archived.
+func (r *MailingRepo) Restore(ctx context.Context, mailing *entity.Mailing) error {
+	err := r.tx.Do(ctx, func(ctx context.Context) error {
+		query, args, err := queries.UndeleteMailing(r.Builder, mailing).ToSql()
+		if err != nil {
+			return err
+		}
+
+		n, err := r.exec(ctx, query, args...)
+		if err != nil || n > 0 {
+			return err
+		}
+
+		// Mailing is inserted before its messages because of foreign key.
+		// Restored mailing isn't deleted, so deleted_at isn't copied.
+		mailings := queries.MailingColumns[:len(queries.MailingColumns)-1]
+		n, err = r.copy(ctx, queries.TableMailingArchive, queries.TableMailing, mailings,
+			squirrel.Eq{"id": mailing.ID})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("mailing %d: %w", mailing.ID, entity.ErrNotFound)
+		}
+
+		_, err = r.copy(ctx, queries.TableMessageArchive, queries.TableMessage, queries.ArchiveMessageColumns,
+			squirrel.Eq{"mailing_id": mailing.ID})
+		if err != nil {
+			return err
+		}
+
+		// Archived messages are deleted by cascade
+		return r.delete(ctx, queries.TableMailingArchive, squirrel.Eq{"id": mailing.ID})
+	})
+	if err != nil {
+		return fmt.Errorf("MailingRepo - Restore(): %w", err)
+	}
+
+	return nil
+}
+
+// Archive moves up to limit mailings finished before time with their
+// messages into archive tables. It returns IDs of archived mailings.
+func (r *MailingRepo) Archive(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	var ids []int64
+
+	err := r.tx.Do(ctx, func(ctx context.Context) error {
+		query, args, err := r.Builder.
+			Select("id").
+			From(queries.TableMailing).
+			Where(squirrel.LtOrEq{"datetime_end": before}).
+			OrderBy("id").
+			Limit(uint64(limit)).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var id int64
+			if err = rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil || len(ids) == 0 {
+			return err
+		}
+
+		// Mailings are copied before their messages and deleted after
+		// them because of foreign keys
+		_, err = r.copy(ctx, queries.TableMailing, queries.TableMailingArchive, queries.MailingColumns,
+			squirrel.Eq{"id": ids})
+		if err != nil {
+			return err
+		}
+
+		_, err = r.copy(ctx, queries.TableMessage, queries.TableMessageArchive, queries.ArchiveMessageColumns,
+			squirrel.Eq{"mailing_id": ids})
+		if err != nil {
+			return err
+		}
+		err = r.delete(ctx, queries.TableMessage, squirrel.Eq{"mailing_id": ids})
+		if err != nil {
+			return err
+		}
+
+		// Chunks and stats of mailing are deleted by cascade
+		return r.delete(ctx, queries.TableMailing, squirrel.Eq{"id": ids})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("MailingRepo - Archive(): %w", err)
+	}
+
+	return ids, nil
+}
+
+// copy inserts rows of condition from one table to another, it returns
+// number of copied rows
+func (r *MailingRepo) copy(ctx context.Context, from, to string, columns []string, cond squirrel.Sqlizer) (
+	int64, error,
+) {
+	query, args, err := r.Builder.
+		Insert(to).
+		Columns(columns...).
+		Select(r.Builder.Select(columns...).From(from).Where(cond)).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	return r.exec(ctx, query, args...)
+}
+
+func (r *MailingRepo) delete(ctx context.Context, table string, cond squirrel.Sqlizer) error {
+	query, args, err := r.Builder.Delete(table).Where(cond).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.exec(ctx, query, args...)
+
+	return err
+}
+
+// exec runs statement and returns number of changed rows
+func (r *MailingRepo) exec(ctx context.Context, query string, args ...any) (int64, error) {
+	res, err := querier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// ReadWithMessages returns stats of mailing that isn't deleted from mailing_stats
+// rollup, counters are zero if mailing doesn't have messages
+func (r *MailingRepo) ReadWithMessages(ctx context.Context, mailing *entity.Mailing) (*entity.MailingStats, error) {
+	query, args, err := queries.SelectStats(r.Builder, mailing).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("MailingRepo - ReadWithMessages(): %w", err)
+	}
+
+	m, err := queries.ScanStats(querier(ctx, r.db).QueryRow(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("MailingRepo - ReadWithMessages(): mailing %d: %w", mailing.ID, entity.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("MailingRepo - ReadWithMessages(): %w", err)
+	}
+
+	return m, nil
+}
+
+// ReadStatsPage returns stats of page of mailings in one query and cursor
+// of the next page. Cursor is empty on the last page.
+func (r *MailingRepo) ReadStatsPage(ctx context.Context, filter *entity.MailingFilter) (
+	[]*entity.MailingStats, string, error,
+) {
+	builder, err := queries.SelectStatsPage(r.Builder, filter, now())
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		stats []*entity.MailingStats
+		sorts []time.Time
+	)
+	for rows.Next() {
+		var m entity.Mailing
+		s, err := queries.ScanStats(rows, &m.DateTimeStart, &m.DateTimeEnd)
+		if err != nil {
+			return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+		}
+		stats = append(stats, s)
+		sorts = append(sorts, m.SortTime(filter.Sort))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadStatsPage(): %w", err)
+	}
+
+	if len(stats) <= filter.Limit {
+		return stats, "", nil
+	}
+
+	stats = stats[:filter.Limit]
+	last := len(stats) - 1
+
+	return stats, filter.Next(sorts[last], stats[last].MailingID), nil
+}
+
+// Read returns mailing that isn't deleted
+func (r *MailingRepo) Read(ctx context.Context, mailing *entity.Mailing) (*entity.Mailing, error) {
+	query, args, err := queries.SelectMailing(r.Builder, mailing).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("MailingRepo - Read(): %w", err)
+	}
+
+	m, err := queries.ScanMailing(querier(ctx, r.db).QueryRow(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("MailingRepo - Read(): mailing %d: %w", mailing.ID, entity.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("MailingRepo - Read(): %w", err)
+	}
+
+	return m, nil
+}
+
+// ReadPage returns page of mailings by filter and cursor of the next page.
+// Cursor is empty on the last page.
+func (r *MailingRepo) ReadPage(ctx context.Context, filter *entity.MailingFilter) (
+	entity.Mailings, string, error,
+) {
+	builder, err := queries.SelectMailingPage(r.Builder, filter, now())
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
+	}
+	defer rows.Close()
+
+	var ms entity.Mailings
+	for rows.Next() {
+		m, err := queries.ScanMailing(rows)
+		if err != nil {
+			return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
+		}
+		ms = append(ms, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("MailingRepo - ReadPage(): %w", err)
+	}
+
+	if len(ms) <= filter.Limit {
+		return ms, "", nil
+	}
+
+	ms = ms[:filter.Limit]
+	last := ms[len(ms)-1]
+
+	return ms, filter.Next(last.SortTime(filter.Sort), last.ID), nil
+}
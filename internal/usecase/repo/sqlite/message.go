@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
+)
+
+type MessageRepo struct {
+	Builder squirrel.StatementBuilderType
+	db      *sql.DB
+}
+
+// NewMessage - MessageRepo constructor
+func NewMessage(db *sql.DB) *MessageRepo {
+	return &MessageRepo{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		db:      db,
+	}
+}
+
+// Create -.
+func (r *MessageRepo) Create(ctx context.Context, message *entity.Message) error {
+	query, args, err := queries.InsertMessage(r.Builder, message, now()).ToSql()
+	if err != nil {
+		return fmt.Errorf("MessageRepo - Create(): %w", err)
+	}
+
+	_, err = querier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("MessageRepo - Create(): %w", err)
+	}
+
+	return nil
+}
+
+// ReadByMailing returns page of messages of mailing by filter and cursor
+// of the next page. Cursor is empty on the last page.
+func (r *MessageRepo) ReadByMailing(ctx context.Context, filter *entity.MessageFilter) (
+	entity.Messages, string, error,
+) {
+	builder, err := queries.SelectMessagePage(r.Builder, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+	}
+	defer rows.Close()
+
+	var ms entity.Messages
+	for rows.Next() {
+		m, err := queries.ScanMessage(rows)
+		if err != nil {
+			return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+		}
+		ms = append(ms, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("MessageRepo - ReadByMailing(): %w", err)
+	}
+
+	if len(ms) <= filter.Limit {
+		return ms, "", nil
+	}
+
+	ms = ms[:filter.Limit]
+	last := ms[len(ms)-1]
+
+	return ms, filter.Next(last.SortTime(filter.Sort), last.ID), nil
+}
+
+// Read -.
+func (r *MessageRepo) Read(ctx context.Context, message *entity.Message) (*entity.Message, error) {
+	query, args, err := queries.SelectMessage(r.Builder, message).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("MessageRepo - Read(): %w", err)
+	}
+
+	m, err := queries.ScanMessage(querier(ctx, r.db).QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("MessageRepo - Read(): %w", err)
+	}
+
+	return m, nil
+}
+
+// Export calls f for every message of filter ordered by ID, page of
+// filter isn't used. Messages are read by pages like ClientRepo.Export.
+func (r *MessageRepo) Export(ctx context.Context, filter *entity.MessageFilter, f func(*entity.Message) error) error {
+	var afterID int64
+	for {
+		query, args, err := queries.SelectMessages(r.Builder, filter).
+			Where(squirrel.Gt{"id": afterID}).
+			OrderBy("id").
+			Limit(exportPage).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("MessageRepo - Export(): %w", err)
+		}
+
+		rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("MessageRepo - Export(): %w", err)
+		}
+
+		var ms entity.Messages
+		for rows.Next() {
+			m, err := queries.ScanMessage(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("MessageRepo - Export(): %w", err)
+			}
+			ms = append(ms, m)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("MessageRepo - Export(): %w", err)
+		}
+
+		for _, m := range ms {
+			if err = f(m); err != nil {
+				return fmt.Errorf("MessageRepo - Export(): %w", err)
+			}
+		}
+
+		if len(ms) < exportPage {
+			return nil
+		}
+		afterID = ms[len(ms)-1].ID
+	}
+}
+
+// ReadSeries returns counters of messages grouped by buckets of filter
+// time zone. Empty buckets are omitted. SQLite doesn't have time zones,
+// so messages are counted by buckets here.
+func (r *MessageRepo) ReadSeries(ctx context.Context, filter *entity.SeriesFilter) (
+	[]*entity.SeriesPoint, error,
+) {
+	builder := r.Builder.
+		Select("date_time_creation", "delivery_status", "deferred").
+		From(queries.TableMessage).
+		Where(queries.ActiveMailing).
+		Where(squirrel.GtOrEq{"date_time_creation": filter.From}).
+		Where(squirrel.Lt{"date_time_creation": filter.To})
+
+	if filter.MailingID != 0 {
+		builder = builder.Where(squirrel.Eq{"mailing_id": filter.MailingID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("MessageRepo - ReadSeries(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("MessageRepo - ReadSeries(): %w", err)
+	}
+	defer rows.Close()
+
+	byBucket := make(map[int64]*entity.SeriesPoint)
+	for rows.Next() {
+		var (
+			created        time.Time
+			sent, deferred bool
+		)
+		if err = rows.Scan(&created, &sent, &deferred); err != nil {
+			return nil, fmt.Errorf("MessageRepo - ReadSeries(): %w", err)
+		}
+
+		start := filter.BucketStart(created)
+		p, ok := byBucket[start.Unix()]
+		if !ok {
+			p = &entity.SeriesPoint{Time: start}
+			byBucket[start.Unix()] = p
+		}
+
+		switch {
+		case sent:
+			p.Sent++
+		case deferred:
+			p.Deferred++
+		default:
+			p.Failed++
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("MessageRepo - ReadSeries(): %w", err)
+	}
+
+	points := make([]*entity.SeriesPoint, 0, len(byBucket))
+	for _, p := range byBucket {
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+
+	return points, nil
+}
@@ -0,0 +1,236 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
+)
+
+const (
+	// tablePartition is months of retention, messages aren't partitioned
+	tablePartition = "message_partition"
+	// partitionPrefix is prefix of monthly partitions like in PostgreSQL
+	partitionPrefix = queries.TableMessage + "_p"
+)
+
+// PartitionRepo manages monthly partitions of messages. SQLite doesn't
+// have partitions, partition is messages created in its month.
+type PartitionRepo struct {
+	Builder squirrel.StatementBuilderType
+	db      *sql.DB
+	locks   *locks
+}
+
+func NewPartition(db *sql.DB) *PartitionRepo {
+	return &PartitionRepo{
+		Builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		db:      db,
+		locks:   newLocks(),
+	}
+}
+
+// Create creates partitions for months number of months starting from month of from.
+// Existing partitions aren't changed.
+func (r *PartitionRepo) Create(ctx context.Context, from time.Time, months int) error {
+	builder := r.Builder.
+		Insert(tablePartition).
+		Columns("month").
+		Suffix("ON CONFLICT (month) DO NOTHING")
+
+	start := entity.MonthStart(from)
+	for i := 0; i < months; i++ {
+		builder = builder.Values(start.AddDate(0, i, 0))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("PartitionRepo - Create(): %w", err)
+	}
+
+	_, err = querier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("PartitionRepo - Create(): %w", err)
+	}
+
+	return nil
+}
+
+// List returns monthly partitions ordered by month
+func (r *PartitionRepo) List(ctx context.Context) (entity.Partitions, error) {
+	query, args, err := r.Builder.
+		Select("month").
+		From(tablePartition).
+		OrderBy("month").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PartitionRepo - List(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PartitionRepo - List(): %w", err)
+	}
+	defer rows.Close()
+
+	var ps entity.Partitions
+	for rows.Next() {
+		var month time.Time
+		if err = rows.Scan(&month); err != nil {
+			return nil, fmt.Errorf("PartitionRepo - List(): %w", err)
+		}
+		ps = append(ps, &entity.Partition{Name: partitionPrefix + month.Format("200601"), Month: month})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("PartitionRepo - List(): %w", err)
+	}
+
+	return ps, nil
+}
+
+// exportColumns are columns of message table in PostgreSQL order
+var exportColumns = []string{
+	"id", "date_time_creation", "delivery_status", "mailing_id", "client_id", "try", "deferred",
+}
+
+// Export writes messages of partition to w as CSV with header like COPY does
+func (r *PartitionRepo) Export(ctx context.Context, p *entity.Partition, w io.Writer) error {
+	query, args, err := r.Builder.
+		Select(exportColumns...).
+		From(queries.TableMessage).
+		Where(inMonth(p.Month)).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("PartitionRepo - Export(): %w", err)
+	}
+
+	rows, err := querier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("PartitionRepo - Export(): %w", err)
+	}
+	defer rows.Close()
+
+	cw := csv.NewWriter(w)
+	if err = cw.Write(exportColumns); err != nil {
+		return fmt.Errorf("PartitionRepo - Export(): %w", err)
+	}
+	for rows.Next() {
+		var (
+			id, try             int64
+			created             time.Time
+			status, deferred    bool
+			mailingID, clientID sql.NullInt64
+		)
+		err = rows.Scan(&id, &created, &status, &mailingID, &clientID, &try, &deferred)
+		if err != nil {
+			return fmt.Errorf("PartitionRepo - Export(): %w", err)
+		}
+
+		err = cw.Write([]string{
+			strconv.FormatInt(id, 10),
+			timestamp(&created),
+			boolean(status),
+			integer(mailingID),
+			integer(clientID),
+			strconv.FormatInt(try, 10),
+			boolean(deferred),
+		})
+		if err != nil {
+			return fmt.Errorf("PartitionRepo - Export(): %w", err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("PartitionRepo - Export(): %w", err)
+	}
+
+	cw.Flush()
+	if err = cw.Error(); err != nil {
+		return fmt.Errorf("PartitionRepo - Export(): %w", err)
+	}
+
+	return nil
+}
+
+// Drop drops partition with its messages. Rollups of messages aren't changed.
+func (r *PartitionRepo) Drop(ctx context.Context, p *entity.Partition) error {
+	err := NewTxManager(r.db).Do(ctx, func(ctx context.Context) error {
+		query, args, err := r.Builder.Delete(queries.TableMessage).Where(inMonth(p.Month)).ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err = querier(ctx, r.db).Exec(ctx, query, args...); err != nil {
+			return err
+		}
+
+		query, args, err = r.Builder.
+			Delete(tablePartition).
+			Where(squirrel.Eq{"month": entity.MonthStart(p.Month)}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+		_, err = querier(ctx, r.db).Exec(ctx, query, args...)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("PartitionRepo - Drop(): %w", err)
+	}
+
+	return nil
+}
+
+// Lock tries to take lock of retention without waiting, so only one
+// job enforces it at a time. Release must be called to unlock it.
+func (r *PartitionRepo) Lock(context.Context) (release func(), ok bool, err error) {
+	release, ok = r.locks.tryLock("retention")
+
+	return release, ok, nil
+}
+
+// inMonth returns condition of messages created in month
+func inMonth(month time.Time) squirrel.Sqlizer {
+	start := entity.MonthStart(month)
+
+	return squirrel.And{
+		squirrel.GtOrEq{"date_time_creation": start},
+		squirrel.Lt{"date_time_creation": start.AddDate(0, 1, 0)},
+	}
+}
+
+// timestamp formats time like PostgreSQL TIMESTAMP, NULL is empty
+func timestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format("2006-01-02 15:04:05.999999")
+}
+
+// boolean formats b like PostgreSQL BOOLEAN
+func boolean(b bool) string {
+	if b {
+		return "t"
+	}
+
+	return "f"
+}
+
+// integer formats i like PostgreSQL BIGINT, NULL is empty
+func integer(i sql.NullInt64) string {
+	if !i.Valid {
+		return ""
+	}
+
+	return strconv.FormatInt(i.Int64, 10)
+}
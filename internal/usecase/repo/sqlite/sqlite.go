@@ -0,0 +1,163 @@
+// Package sqlite implements repositories over SQLite database file. It's
+// storage of embedded deployments, database is used by one process.
+// Statements are shared with PostgreSQL repositories, see package queries.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/queries"
+	"gitlab.com/fluxx1on_group/event_message_service/migrations"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/migrate"
+	_ "modernc.org/sqlite"
+)
+
+// Open opens database file of path and migrates its schema. Directory of
+// file is created if it doesn't exist. Database has one connection,
+// so statements are serialized and transaction holds it until it's finished.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("sqlite - Open(): %w", err)
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("sqlite - Open(): %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	ms, err := migrate.Load(migrations.SQLite, migrations.SQLiteDir)
+	if err == nil {
+		err = migrate.NewSQLite(db, ms).Up(ctx)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite - Open(): %w", err)
+	}
+
+	return db, nil
+}
+
+// timeFormat is format of TIMESTAMP columns, it's parsed by driver.
+// Times are UTC, so they're ordered as text.
+const timeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// now returns current time as statement argument
+func now() squirrel.Sqlizer {
+	return squirrel.Expr("?", time.Now())
+}
+
+// Querier runs statements on database or in transaction
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn runs statements on querier. Times are written and read like
+// PostgreSQL TIMESTAMP: in UTC with microseconds.
+type conn struct {
+	q Querier
+}
+
+// querier returns conn of transaction of ctx or of db if there is no
+// transaction. Repositories run every statement on it.
+func querier(ctx context.Context, db *sql.DB) conn {
+	if tx, ok := ctx.Value(txKey{}).(*txState); ok {
+		return conn{tx.tx}
+	}
+
+	return conn{db}
+}
+
+func (c conn) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return c.q.ExecContext(ctx, query, bind(args)...)
+}
+
+func (c conn) Query(ctx context.Context, query string, args ...any) (*rows, error) {
+	rs, err := c.q.QueryContext(ctx, query, bind(args)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rows{rs}, nil
+}
+
+func (c conn) QueryRow(ctx context.Context, query string, args ...any) queries.Row {
+	return row{c.q.QueryRowContext(ctx, query, bind(args)...)}
+}
+
+type rows struct {
+	*sql.Rows
+}
+
+func (r *rows) Scan(dest ...any) error {
+	if err := r.Rows.Scan(dest...); err != nil {
+		return err
+	}
+	utc(dest)
+
+	return nil
+}
+
+type row struct {
+	*sql.Row
+}
+
+func (r row) Scan(dest ...any) error {
+	if err := r.Row.Scan(dest...); err != nil {
+		return err
+	}
+	utc(dest)
+
+	return nil
+}
+
+// bind formats times of args in timeFormat
+func bind(args []any) []any {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			args[i] = stamp(v)
+		case *time.Time:
+			if v != nil {
+				args[i] = stamp(*v)
+			}
+		}
+	}
+
+	return args
+}
+
+// stamp formats t like TIMESTAMP column
+func stamp(t time.Time) string {
+	return t.UTC().Truncate(time.Microsecond).Format(timeFormat)
+}
+
+// utc moves scanned times to UTC, driver parses them with offset of local zone
+func utc(dest []any) {
+	for _, d := range dest {
+		switch v := d.(type) {
+		case *time.Time:
+			*v = v.UTC()
+		case **time.Time:
+			if *v != nil {
+				t := (*v).UTC()
+				*v = &t
+			}
+		}
+	}
+}
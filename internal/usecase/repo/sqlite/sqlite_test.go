@@ -0,0 +1,33 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/repotest"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase/repo/sqlite"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repos {
+		db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		return &repotest.Repos{
+			Client:    sqlite.NewClient(db),
+			Mailing:   sqlite.NewMailing(db),
+			Message:   sqlite.NewMessage(db),
+			Chunk:     sqlite.NewChunk(db),
+			Partition: sqlite.NewPartition(db),
+			Lease:     sqlite.NewLease(),
+			Audit:     sqlite.NewAudit(db),
+			Import:    sqlite.NewImport(db),
+			Export:    sqlite.NewExport(db),
+			Tx:        sqlite.NewTxManager(db),
+		}
+	})
+}
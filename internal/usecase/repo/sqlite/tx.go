@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type txKey struct{}
+
+// txState is transaction of ctx, depth is number of savepoints of nested Do
+type txState struct {
+	tx    *sql.Tx
+	depth int
+}
+
+// TxManager runs functions in transactions. Transaction is carried in
+// ctx, so all the repositories called with ctx of function use it.
+// Database has one connection, so repositories must be called with ctx
+// of function, otherwise they wait for the transaction to finish.
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db}
+}
+
+// Do runs f in transaction, it's committed if f succeeds and rolled back
+// otherwise. Nested Do runs f in savepoint of outer transaction, so its
+// failure doesn't abort outer one.
+func (m *TxManager) Do(ctx context.Context, f func(ctx context.Context) error) error {
+	if outer, ok := ctx.Value(txKey{}).(*txState); ok {
+		return m.savepoint(ctx, outer, f)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("TxManager - Do() - Begin(): %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = f(context.WithValue(ctx, txKey{}, &txState{tx: tx})); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("TxManager - Do() - Rollback(): %w", rbErr))
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("TxManager - Do() - Commit(): %w", err)
+	}
+
+	return nil
+}
+
+// savepoint runs f in savepoint of outer transaction
+func (m *TxManager) savepoint(ctx context.Context, outer *txState, f func(ctx context.Context) error) error {
+	inner := &txState{tx: outer.tx, depth: outer.depth + 1}
+	name := fmt.Sprintf("sp_%d", inner.depth)
+
+	// Savepoint is rolled back even if ctx is cancelled
+	rollback := func() error {
+		_, err := outer.tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO "+name+"; RELEASE "+name)
+		return err
+	}
+
+	if _, err := outer.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("TxManager - Do() - Savepoint(): %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = rollback()
+			panic(p)
+		}
+	}()
+
+	if err := f(context.WithValue(ctx, txKey{}, inner)); err != nil {
+		if rbErr := rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("TxManager - Do() - Rollback(): %w", rbErr))
+		}
+		return err
+	}
+
+	if _, err := outer.tx.ExecContext(ctx, "RELEASE "+name); err != nil {
+		return fmt.Errorf("TxManager - Do() - Release(): %w", err)
+	}
+
+	return nil
+}
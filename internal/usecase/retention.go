@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+)
+
+// RetentionUseCase keeps monthly partitions of messages: partitions are
+// created ahead of time and partitions older than keep months are dropped.
+// Messages are exported to exportDir before dropping if it's set.
+//
+// Mailing stats are kept in rollup, so they don't change after dropping.
+type RetentionUseCase struct {
+	repo      PartitionRepo
+	keep      int
+	ahead     int
+	exportDir string
+
+	now func() time.Time
+}
+
+// NewRetention - keep is number of months including the current one,
+// zero keep disables dropping. ahead is number of months created in advance.
+func NewRetention(repo PartitionRepo, keep, ahead int, exportDir string) *RetentionUseCase {
+	return &RetentionUseCase{
+		repo:      repo,
+		keep:      keep,
+		ahead:     ahead,
+		exportDir: exportDir,
+		now:       time.Now,
+	}
+}
+
+// SetClock replaces time source, it's used by tests
+func (u *RetentionUseCase) SetClock(now func() time.Time) {
+	u.now = now
+}
+
+// Run enforces retention at once and then every interval until ctx is done
+func (u *RetentionUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		dropped, err := u.Enforce(ctx)
+		if err != nil {
+			slog.Error("Retention failed", slog.String("ErrorMsg", err.Error()))
+		} else if len(dropped) > 0 {
+			slog.Info("Message partitions dropped", slog.Any("Partitions", dropped))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Enforce creates partitions of the current and ahead months and drops
+// expired ones. It returns names of dropped partitions. Enforce is
+// skipped if another instance enforces retention.
+func (u *RetentionUseCase) Enforce(ctx context.Context) ([]string, error) {
+	release, ok, err := u.repo.Lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("RetentionUseCase - Enforce(): %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	defer release()
+
+	month := entity.MonthStart(u.now())
+
+	err = u.repo.Create(ctx, month, u.ahead+1)
+	if err != nil {
+		return nil, fmt.Errorf("RetentionUseCase - Enforce(): %w", err)
+	}
+
+	if u.keep <= 0 {
+		return nil, nil
+	}
+
+	partitions, err := u.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("RetentionUseCase - Enforce(): %w", err)
+	}
+
+	var (
+		dropped []string
+		expired = month.AddDate(0, -(u.keep - 1), 0)
+	)
+	for _, p := range partitions {
+		if !p.Month.Before(expired) {
+			break
+		}
+
+		if u.exportDir != "" {
+			if err = u.export(ctx, p); err != nil {
+				return dropped, fmt.Errorf("RetentionUseCase - Enforce() - export(): %w", err)
+			}
+		}
+
+		if err = u.repo.Drop(ctx, p); err != nil {
+			return dropped, fmt.Errorf("RetentionUseCase - Enforce(): %w", err)
+		}
+		dropped = append(dropped, p.Name)
+	}
+
+	return dropped, nil
+}
+
+// export writes partition to "<exportDir>/<name>.csv". File is renamed
+// after it's written, so partial export isn't taken for complete one.
+func (u *RetentionUseCase) export(ctx context.Context, p *entity.Partition) error {
+	path := filepath.Join(u.exportDir, p.Name+".csv")
+
+	f, err := os.CreateTemp(u.exportDir, p.Name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	err = u.repo.Export(ctx, p, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,72 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/entity"
+	"gitlab.com/fluxx1on_group/event_message_service/internal/usecase"
+)
+
+func TestRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockPartitionRepo(ctrl)
+
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	month := func(m time.Month) *entity.Partition {
+		start := time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC)
+		return &entity.Partition{Name: "message_p" + start.Format("200601"), Month: start}
+	}
+	partitions := entity.Partitions{month(1), month(2), month(3), month(4), month(5)}
+
+	// test 1: partitions older than 3 months are exported and dropped
+	{
+		dir := t.TempDir()
+		u := usecase.NewRetention(repo, 3, 2, dir)
+		u.SetClock(func() time.Time { return now })
+
+		repo.EXPECT().Lock(gomock.Any()).Return(func() {}, true, nil)
+		repo.EXPECT().Create(gomock.Any(), time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), 3).Return(nil)
+		repo.EXPECT().List(gomock.Any()).Return(partitions, nil)
+		repo.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+			DoAndReturn(func(_ context.Context, p *entity.Partition, w io.Writer) error {
+				_, err := io.WriteString(w, "id\n")
+				return err
+			})
+		repo.EXPECT().Drop(gomock.Any(), partitions[0]).Return(nil)
+		repo.EXPECT().Drop(gomock.Any(), partitions[1]).Return(nil)
+
+		dropped, err := u.Enforce(context.Background())
+		assert.Equal(t, err, nil)
+		assert.Equal(t, dropped, []string{"message_p202401", "message_p202402"})
+
+		data, err := os.ReadFile(filepath.Join(dir, "message_p202401.csv"))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, string(data), "id\n")
+	}
+
+	// test 2: nothing is dropped with zero keep or if another instance holds lock
+	{
+		u := usecase.NewRetention(repo, 0, 1, "")
+		u.SetClock(func() time.Time { return now })
+
+		repo.EXPECT().Lock(gomock.Any()).Return(func() {}, true, nil)
+		repo.EXPECT().Create(gomock.Any(), gomock.Any(), 2).Return(nil)
+
+		dropped, err := u.Enforce(context.Background())
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(dropped), 0)
+
+		repo.EXPECT().Lock(gomock.Any()).Return(nil, false, nil)
+
+		dropped, err = u.Enforce(context.Background())
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(dropped), 0)
+	}
+}
@@ -1,8 +0,0 @@
-DROP TABLE IF EXISTS mailing CASCADE;
-
-DROP TABLE IF EXISTS client CASCADE;
-
-DROP TABLE IF EXISTS message;
-
-DROP TYPE IF EXISTS client_tag;
-DROP TYPE IF EXISTS filter_attr;
\ No newline at end of file
@@ -0,0 +1,18 @@
+// Package migrations embeds versioned schema migrations, see pkg/migrate.
+package migrations
+
+import "embed"
+
+// Postgres holds migrations of PostgreSQL schema in "postgres" dir
+//
+//go:embed postgres/*.sql
+var Postgres embed.FS
+
+const PostgresDir = "postgres"
+
+// SQLite holds migrations of SQLite schema in "sqlite" dir
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS
+
+const SQLiteDir = "sqlite"
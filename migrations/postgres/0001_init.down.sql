@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS message;
+
+DROP TABLE IF EXISTS client;
+
+DROP TABLE IF EXISTS mailing;
+
+DROP TYPE IF EXISTS client_tag;
+DROP TYPE IF EXISTS filter_attr;
@@ -1,6 +1,13 @@
-CREATE TYPE client_tag AS ENUM ('silver', 'gold', 'vip');
+-- Types may exist in database created by db.sql before migrations
+DO $$ BEGIN
+    CREATE TYPE client_tag AS ENUM ('silver', 'gold', 'vip');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
 
-CREATE TYPE filter_attr AS ENUM('tag', 'code');
+DO $$ BEGIN
+    CREATE TYPE filter_attr AS ENUM('tag', 'code');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
 
 CREATE TABLE IF NOT EXISTS mailing (
     id SERIAL PRIMARY KEY,
@@ -29,4 +36,3 @@ CREATE TABLE IF NOT EXISTS message (
     mailing_id BIGINT REFERENCES mailing(id),
     client_id BIGINT REFERENCES client(id)
 );
-
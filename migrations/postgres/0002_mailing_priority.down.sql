@@ -0,0 +1,3 @@
+ALTER TABLE mailing DROP COLUMN IF EXISTS priority;
+
+DROP TYPE IF EXISTS mailing_priority;
@@ -0,0 +1,3 @@
+CREATE TYPE mailing_priority AS ENUM('transactional', 'bulk');
+
+ALTER TABLE mailing ADD COLUMN priority mailing_priority NOT NULL DEFAULT 'bulk';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS mailing_fanout;
+
+DROP TABLE IF EXISTS mailing_chunk;
+
+DROP TYPE IF EXISTS chunk_status;
@@ -0,0 +1,19 @@
+CREATE TYPE chunk_status AS ENUM('created', 'published', 'done');
+
+-- Audience of mailing is split into chunks of clients: after_id < id <= last_id
+CREATE TABLE IF NOT EXISTS mailing_chunk (
+    mailing_id BIGINT REFERENCES mailing(id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL,
+    after_id BIGINT NOT NULL,
+    last_id BIGINT NOT NULL,
+    status chunk_status NOT NULL DEFAULT 'created',
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (mailing_id, seq)
+);
+
+-- Fan-out of mailing is finished when all its chunks are created
+CREATE TABLE IF NOT EXISTS mailing_fanout (
+    mailing_id BIGINT PRIMARY KEY REFERENCES mailing(id) ON DELETE CASCADE,
+    chunks INTEGER NOT NULL,
+    finished_at TIMESTAMP NOT NULL DEFAULT now()
+);
@@ -0,0 +1,2 @@
+-- Number of sending attempt, messages are written by every attempt
+ALTER TABLE message ADD COLUMN try INTEGER NOT NULL DEFAULT 0;
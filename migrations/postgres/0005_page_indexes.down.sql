@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS message_mailing_id_creation_id_idx;
+DROP INDEX IF EXISTS message_mailing_id_id_idx;
+
+DROP INDEX IF EXISTS mailing_datetime_end_id_idx;
+DROP INDEX IF EXISTS mailing_datetime_start_id_idx;
@@ -0,0 +1,6 @@
+-- Keyset pagination of list endpoints: (sort column, id)
+CREATE INDEX IF NOT EXISTS mailing_datetime_start_id_idx ON mailing (datetime_start, id);
+CREATE INDEX IF NOT EXISTS mailing_datetime_end_id_idx ON mailing (datetime_end, id);
+
+CREATE INDEX IF NOT EXISTS message_mailing_id_id_idx ON message (mailing_id, id);
+CREATE INDEX IF NOT EXISTS message_mailing_id_creation_id_idx ON message (mailing_id, date_time_creation, id);
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS message_stats_add ON message;
+
+DROP FUNCTION IF EXISTS mailing_stats_add();
+
+DROP TABLE IF EXISTS mailing_stats;
@@ -0,0 +1,49 @@
+-- Rollup of message counters by mailing, it's maintained by trigger on message
+CREATE TABLE IF NOT EXISTS mailing_stats (
+    mailing_id BIGINT PRIMARY KEY REFERENCES mailing(id) ON DELETE CASCADE,
+    succeeded BIGINT NOT NULL DEFAULT 0,
+    failed BIGINT NOT NULL DEFAULT 0,
+    first_at TIMESTAMP NOT NULL,
+    last_at TIMESTAMP NOT NULL
+);
+
+-- Rows inserted by statement are grouped once, so bulk inserts touch
+-- every rollup row one time. Rows are locked in mailing_id order.
+CREATE OR REPLACE FUNCTION mailing_stats_add() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO mailing_stats AS s (mailing_id, succeeded, failed, first_at, last_at)
+    SELECT mailing_id,
+        COUNT(*) FILTER (WHERE delivery_status),
+        COUNT(*) FILTER (WHERE NOT delivery_status),
+        MIN(date_time_creation),
+        MAX(date_time_creation)
+    FROM inserted
+    WHERE mailing_id IS NOT NULL
+    GROUP BY mailing_id
+    ORDER BY mailing_id
+    ON CONFLICT (mailing_id) DO UPDATE SET
+        succeeded = s.succeeded + EXCLUDED.succeeded,
+        failed = s.failed + EXCLUDED.failed,
+        first_at = LEAST(s.first_at, EXCLUDED.first_at),
+        last_at = GREATEST(s.last_at, EXCLUDED.last_at);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER message_stats_add AFTER INSERT ON message
+    REFERENCING NEW TABLE AS inserted
+    FOR EACH STATEMENT EXECUTE FUNCTION mailing_stats_add();
+
+-- Existing messages are counted once: CREATE TRIGGER locks message
+-- against inserts until migration is committed
+
+INSERT INTO mailing_stats (mailing_id, succeeded, failed, first_at, last_at)
+SELECT mailing_id,
+    COUNT(*) FILTER (WHERE delivery_status),
+    COUNT(*) FILTER (WHERE NOT delivery_status),
+    MIN(date_time_creation),
+    MAX(date_time_creation)
+FROM message
+WHERE mailing_id IS NOT NULL
+GROUP BY mailing_id;
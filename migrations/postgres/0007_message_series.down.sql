@@ -0,0 +1,26 @@
+CREATE OR REPLACE FUNCTION mailing_stats_add() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO mailing_stats AS s (mailing_id, succeeded, failed, first_at, last_at)
+    SELECT mailing_id,
+        COUNT(*) FILTER (WHERE delivery_status),
+        COUNT(*) FILTER (WHERE NOT delivery_status),
+        MIN(date_time_creation),
+        MAX(date_time_creation)
+    FROM inserted
+    WHERE mailing_id IS NOT NULL
+    GROUP BY mailing_id
+    ORDER BY mailing_id
+    ON CONFLICT (mailing_id) DO UPDATE SET
+        succeeded = s.succeeded + EXCLUDED.succeeded,
+        failed = s.failed + EXCLUDED.failed,
+        first_at = LEAST(s.first_at, EXCLUDED.first_at),
+        last_at = GREATEST(s.last_at, EXCLUDED.last_at);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS message_creation_idx;
+
+ALTER TABLE message DROP COLUMN IF EXISTS delivered_at;
+ALTER TABLE message DROP COLUMN IF EXISTS deferred;
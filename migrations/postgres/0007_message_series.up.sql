@@ -0,0 +1,30 @@
+-- Clients outside of their time window are written as deferred messages,
+-- delivered_at is reserved for provider delivery reports
+ALTER TABLE message ADD COLUMN deferred BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE message ADD COLUMN delivered_at TIMESTAMP;
+
+-- Time series of all the mailings are bucketed by creation time
+CREATE INDEX IF NOT EXISTS message_creation_idx ON message (date_time_creation);
+
+-- Deferred messages aren't failed
+CREATE OR REPLACE FUNCTION mailing_stats_add() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO mailing_stats AS s (mailing_id, succeeded, failed, first_at, last_at)
+    SELECT mailing_id,
+        COUNT(*) FILTER (WHERE delivery_status),
+        COUNT(*) FILTER (WHERE NOT delivery_status AND NOT deferred),
+        MIN(date_time_creation),
+        MAX(date_time_creation)
+    FROM inserted
+    WHERE mailing_id IS NOT NULL
+    GROUP BY mailing_id
+    ORDER BY mailing_id
+    ON CONFLICT (mailing_id) DO UPDATE SET
+        succeeded = s.succeeded + EXCLUDED.succeeded,
+        failed = s.failed + EXCLUDED.failed,
+        first_at = LEAST(s.first_at, EXCLUDED.first_at),
+        last_at = GREATEST(s.last_at, EXCLUDED.last_at);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
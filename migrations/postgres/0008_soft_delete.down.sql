@@ -0,0 +1,11 @@
+-- Archived mailings are dropped, deleted rows become visible again
+
+DROP TABLE IF EXISTS message_archive;
+
+DROP TABLE IF EXISTS mailing_archive;
+
+DROP INDEX IF EXISTS client_phone_number_key;
+ALTER TABLE client ADD CONSTRAINT client_phone_number_key UNIQUE (phone_number);
+
+ALTER TABLE client DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE mailing DROP COLUMN IF EXISTS deleted_at;
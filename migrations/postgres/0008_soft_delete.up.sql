@@ -0,0 +1,35 @@
+ALTER TABLE mailing ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE client ADD COLUMN deleted_at TIMESTAMP;
+
+-- Phone number of deleted client could be taken by new one
+ALTER TABLE client DROP CONSTRAINT IF EXISTS client_phone_number_key;
+CREATE UNIQUE INDEX IF NOT EXISTS client_phone_number_key ON client (phone_number) WHERE deleted_at IS NULL;
+
+-- Finished mailings are moved to archive with their messages
+CREATE TABLE IF NOT EXISTS mailing_archive (
+    id BIGINT PRIMARY KEY,
+    message_text TEXT NOT NULL,
+    mobile_operator_code INTEGER NOT NULL,
+    tag client_tag NOT NULL,
+    filter_choice filter_attr NOT NULL,
+    datetime_start TIMESTAMP NOT NULL,
+    datetime_end TIMESTAMP NOT NULL,
+    interval_start TIMESTAMP NOT NULL,
+    interval_end TIMESTAMP NOT NULL,
+    priority mailing_priority NOT NULL,
+    deleted_at TIMESTAMP,
+    archived_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS message_archive (
+    id BIGINT PRIMARY KEY,
+    date_time_creation TIMESTAMP NOT NULL,
+    try INTEGER NOT NULL,
+    delivery_status BOOLEAN NOT NULL,
+    deferred BOOLEAN NOT NULL,
+    delivered_at TIMESTAMP,
+    mailing_id BIGINT REFERENCES mailing_archive(id) ON DELETE CASCADE,
+    client_id BIGINT REFERENCES client(id)
+);
+
+CREATE INDEX IF NOT EXISTS message_archive_mailing_id_idx ON message_archive (mailing_id);
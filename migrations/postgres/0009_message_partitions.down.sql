@@ -0,0 +1,36 @@
+-- Messages of dropped partitions aren't returned
+ALTER TABLE message RENAME TO message_partitioned;
+ALTER TABLE message_partitioned RENAME CONSTRAINT message_pkey TO message_partitioned_pkey;
+DROP INDEX IF EXISTS message_mailing_id_id_idx;
+DROP INDEX IF EXISTS message_mailing_id_creation_id_idx;
+DROP INDEX IF EXISTS message_creation_idx;
+
+CREATE TABLE message (
+    id BIGINT PRIMARY KEY DEFAULT nextval('message_id_seq'),
+    date_time_creation TIMESTAMP NOT NULL DEFAULT now(),
+    delivery_status BOOLEAN NOT NULL,
+    mailing_id BIGINT REFERENCES mailing(id),
+    client_id BIGINT REFERENCES client(id),
+    try INTEGER NOT NULL DEFAULT 0,
+    deferred BOOLEAN NOT NULL DEFAULT false,
+    delivered_at TIMESTAMP
+);
+
+ALTER SEQUENCE message_id_seq OWNED BY message.id;
+
+INSERT INTO message (id, date_time_creation, delivery_status, mailing_id, client_id, try, deferred, delivered_at)
+SELECT id, date_time_creation, delivery_status, mailing_id, client_id, try, deferred, delivered_at
+FROM message_partitioned;
+
+-- Partitions are dropped with partitioned table
+DROP TABLE message_partitioned;
+
+DROP FUNCTION IF EXISTS message_partition_create(date);
+
+CREATE INDEX IF NOT EXISTS message_mailing_id_id_idx ON message (mailing_id, id);
+CREATE INDEX IF NOT EXISTS message_mailing_id_creation_id_idx ON message (mailing_id, date_time_creation, id);
+CREATE INDEX IF NOT EXISTS message_creation_idx ON message (date_time_creation);
+
+CREATE TRIGGER message_stats_add AFTER INSERT ON message
+    REFERENCING NEW TABLE AS inserted
+    FOR EACH STATEMENT EXECUTE FUNCTION mailing_stats_add();
@@ -0,0 +1,59 @@
+-- Monthly partitions of message are named message_pYYYYMM. Partitions
+-- are created ahead by retention job, rows out of them go to message_default.
+CREATE OR REPLACE FUNCTION message_partition_create(month date) RETURNS text AS $$
+DECLARE
+    start date := date_trunc('month', month);
+    name text := 'message_p' || to_char(start, 'YYYYMM');
+BEGIN
+    EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF message FOR VALUES FROM (%L) TO (%L)',
+        name, start, (start + interval '1 month')::date);
+    RETURN name;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Names of indexes and primary key are taken by partitioned table
+ALTER TABLE message RENAME TO message_legacy;
+ALTER TABLE message_legacy RENAME CONSTRAINT message_pkey TO message_legacy_pkey;
+DROP TRIGGER IF EXISTS message_stats_add ON message_legacy;
+DROP INDEX IF EXISTS message_mailing_id_id_idx;
+DROP INDEX IF EXISTS message_mailing_id_creation_id_idx;
+DROP INDEX IF EXISTS message_creation_idx;
+
+-- Partition key is a part of primary key
+CREATE TABLE message (
+    id BIGINT NOT NULL DEFAULT nextval('message_id_seq'),
+    date_time_creation TIMESTAMP NOT NULL DEFAULT now(),
+    delivery_status BOOLEAN NOT NULL,
+    mailing_id BIGINT REFERENCES mailing(id),
+    client_id BIGINT REFERENCES client(id),
+    try INTEGER NOT NULL DEFAULT 0,
+    deferred BOOLEAN NOT NULL DEFAULT false,
+    delivered_at TIMESTAMP,
+    PRIMARY KEY (id, date_time_creation)
+) PARTITION BY RANGE (date_time_creation);
+
+ALTER SEQUENCE message_id_seq AS BIGINT OWNED BY message.id;
+
+CREATE TABLE IF NOT EXISTS message_default PARTITION OF message DEFAULT;
+
+SELECT message_partition_create(month::date)
+FROM generate_series(
+    date_trunc('month', COALESCE((SELECT MIN(date_time_creation) FROM message_legacy), now())),
+    date_trunc('month', now()) + interval '2 month',
+    interval '1 month'
+) AS month;
+
+-- Messages are already counted in mailing_stats, so they're copied before trigger
+INSERT INTO message (id, date_time_creation, delivery_status, mailing_id, client_id, try, deferred, delivered_at)
+SELECT id, date_time_creation, delivery_status, mailing_id, client_id, try, deferred, delivered_at
+FROM message_legacy;
+
+DROP TABLE message_legacy;
+
+CREATE INDEX IF NOT EXISTS message_mailing_id_id_idx ON message (mailing_id, id);
+CREATE INDEX IF NOT EXISTS message_mailing_id_creation_id_idx ON message (mailing_id, date_time_creation, id);
+CREATE INDEX IF NOT EXISTS message_creation_idx ON message (date_time_creation);
+
+CREATE TRIGGER message_stats_add AFTER INSERT ON message
+    REFERENCING NEW TABLE AS inserted
+    FOR EACH STATEMENT EXECUTE FUNCTION mailing_stats_add();
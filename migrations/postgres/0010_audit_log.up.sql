@@ -0,0 +1,19 @@
+CREATE TYPE audit_entity AS ENUM('client', 'mailing');
+CREATE TYPE audit_action AS ENUM('create', 'update', 'delete', 'restore');
+
+-- Changes of clients and mailings, diff is object of changed fields
+-- {"field": {"before": ..., "after": ...}}
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    actor TEXT NOT NULL,
+    action audit_action NOT NULL,
+    entity audit_entity NOT NULL,
+    entity_id BIGINT NOT NULL,
+    diff JSONB NOT NULL,
+    request_id TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_id_idx ON audit_log (entity, entity_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor, id);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_id_idx ON audit_log (created_at, id);
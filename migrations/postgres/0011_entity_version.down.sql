@@ -0,0 +1,3 @@
+ALTER TABLE mailing_archive DROP COLUMN IF EXISTS version;
+ALTER TABLE client DROP COLUMN IF EXISTS version;
+ALTER TABLE mailing DROP COLUMN IF EXISTS version;
@@ -0,0 +1,4 @@
+-- Version of optimistic concurrency, it's incremented by every change
+ALTER TABLE mailing ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE client ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE mailing_archive ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
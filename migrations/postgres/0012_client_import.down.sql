@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS import_error;
+DROP TABLE IF EXISTS import_job;
+
+DROP TYPE IF EXISTS import_status;
+DROP TYPE IF EXISTS import_format;
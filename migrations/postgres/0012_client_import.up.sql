@@ -0,0 +1,27 @@
+CREATE TYPE import_format AS ENUM('csv', 'ndjson');
+CREATE TYPE import_status AS ENUM('pending', 'running', 'done', 'failed');
+
+-- Async imports of clients from uploaded files
+CREATE TABLE IF NOT EXISTS import_job (
+    id BIGSERIAL PRIMARY KEY,
+    format import_format NOT NULL,
+    status import_status NOT NULL,
+    total BIGINT NOT NULL DEFAULT 0,
+    imported BIGINT NOT NULL DEFAULT 0,
+    failed BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    finished_at TIMESTAMP
+);
+
+-- Invalid rows of imports, they're reported in order of id
+CREATE TABLE IF NOT EXISTS import_error (
+    id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL REFERENCES import_job(id) ON DELETE CASCADE,
+    line BIGINT NOT NULL,
+    field TEXT NOT NULL,
+    value TEXT NOT NULL,
+    message TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS import_error_job_id_idx ON import_error (job_id, id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS export_job;
+
+DROP TYPE IF EXISTS export_status;
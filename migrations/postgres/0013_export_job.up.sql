@@ -0,0 +1,14 @@
+CREATE TYPE export_status AS ENUM('pending', 'running', 'done', 'failed');
+
+-- Async exports of clients and messages to files of export directory,
+-- request is JSON of entity.ExportRequest
+CREATE TABLE IF NOT EXISTS export_job (
+    id BIGSERIAL PRIMARY KEY,
+    request JSONB NOT NULL,
+    status export_status NOT NULL,
+    rows BIGINT NOT NULL DEFAULT 0,
+    file TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    finished_at TIMESTAMP
+);
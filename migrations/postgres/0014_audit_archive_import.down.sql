@@ -0,0 +1,6 @@
+ALTER TABLE import_job
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS actor;
+
+-- Values can't be dropped from enum types, so audit_entity and audit_action
+-- keep them
@@ -0,0 +1,10 @@
+-- Archived mailings and upserted batches of import jobs are audited.
+-- Added values aren't used in transaction of migration.
+ALTER TYPE audit_entity ADD VALUE IF NOT EXISTS 'import';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'archive';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'upsert';
+
+-- Actor and request which started import job
+ALTER TABLE import_job
+    ADD COLUMN IF NOT EXISTS actor TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS request_id TEXT NOT NULL DEFAULT '';
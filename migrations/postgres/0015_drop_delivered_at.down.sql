@@ -0,0 +1,2 @@
+ALTER TABLE message ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP;
+ALTER TABLE message_archive ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS message_archive;
+DROP TABLE IF EXISTS mailing_archive;
+DROP TABLE IF EXISTS mailing_fanout;
+DROP TABLE IF EXISTS mailing_chunk;
+DROP TABLE IF EXISTS mailing_stats;
+DROP TABLE IF EXISTS message_partition;
+DROP TABLE IF EXISTS message;
+DROP TABLE IF EXISTS client;
+DROP TABLE IF EXISTS mailing;
@@ -0,0 +1,128 @@
+-- Schema of SQLite storage is the latest PostgreSQL schema. Enums are
+-- check constraints. Times are written by storage as UTC text
+-- "YYYY-MM-DD HH:MM:SS.fffffffff+00:00", so they're compared as strings.
+
+CREATE TABLE IF NOT EXISTS mailing (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    message_text TEXT NOT NULL,
+    mobile_operator_code INTEGER NOT NULL CHECK (typeof(mobile_operator_code) = 'integer'),
+    tag TEXT NOT NULL CHECK (tag IN ('silver', 'gold', 'vip')),
+    filter_choice TEXT NOT NULL CHECK (filter_choice IN ('tag', 'code')),
+    datetime_start TIMESTAMP NOT NULL,
+    datetime_end TIMESTAMP NOT NULL,
+    interval_start TIMESTAMP NOT NULL,
+    interval_end TIMESTAMP NOT NULL,
+    priority TEXT NOT NULL DEFAULT 'bulk' CHECK (priority IN ('transactional', 'bulk')),
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS mailing_datetime_start_id_idx ON mailing (datetime_start, id);
+CREATE INDEX IF NOT EXISTS mailing_datetime_end_id_idx ON mailing (datetime_end, id);
+
+CREATE TABLE IF NOT EXISTS client (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    phone_number INTEGER NOT NULL,
+    mobile_operator_code INTEGER NOT NULL,
+    tag TEXT NOT NULL CHECK (tag IN ('silver', 'gold', 'vip')),
+    time_zone INTEGER NOT NULL,
+    deleted_at TIMESTAMP
+);
+
+-- Phone number of deleted client could be taken by new one
+CREATE UNIQUE INDEX IF NOT EXISTS client_phone_number_key ON client (phone_number) WHERE deleted_at IS NULL;
+
+-- Messages aren't partitioned, months of retention are kept in message_partition
+CREATE TABLE IF NOT EXISTS message (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    date_time_creation TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    delivery_status BOOLEAN NOT NULL CHECK (delivery_status IN (0, 1)),
+    mailing_id INTEGER REFERENCES mailing(id),
+    client_id INTEGER REFERENCES client(id),
+    try INTEGER NOT NULL DEFAULT 0,
+    deferred BOOLEAN NOT NULL DEFAULT 0 CHECK (deferred IN (0, 1)),
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS message_mailing_id_id_idx ON message (mailing_id, id);
+CREATE INDEX IF NOT EXISTS message_mailing_id_creation_id_idx ON message (mailing_id, date_time_creation, id);
+CREATE INDEX IF NOT EXISTS message_creation_idx ON message (date_time_creation);
+
+-- First day of month of retention, "YYYY-MM-01 00:00:00+00:00"
+CREATE TABLE IF NOT EXISTS message_partition (
+    month TIMESTAMP PRIMARY KEY
+);
+
+-- Rollup of message counters by mailing, it's maintained by trigger on message
+CREATE TABLE IF NOT EXISTS mailing_stats (
+    mailing_id INTEGER PRIMARY KEY REFERENCES mailing(id) ON DELETE CASCADE,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    first_at TIMESTAMP NOT NULL,
+    last_at TIMESTAMP NOT NULL
+);
+
+-- Deferred messages aren't failed
+CREATE TRIGGER IF NOT EXISTS message_stats_add AFTER INSERT ON message
+WHEN NEW.mailing_id IS NOT NULL
+BEGIN
+    INSERT INTO mailing_stats (mailing_id, succeeded, failed, first_at, last_at)
+    VALUES (
+        NEW.mailing_id,
+        NEW.delivery_status,
+        NOT NEW.delivery_status AND NOT NEW.deferred,
+        NEW.date_time_creation,
+        NEW.date_time_creation
+    )
+    ON CONFLICT (mailing_id) DO UPDATE SET
+        succeeded = succeeded + excluded.succeeded,
+        failed = failed + excluded.failed,
+        first_at = MIN(first_at, excluded.first_at),
+        last_at = MAX(last_at, excluded.last_at);
+END;
+
+-- Audience of mailing is split into chunks of clients: after_id < id <= last_id
+CREATE TABLE IF NOT EXISTS mailing_chunk (
+    mailing_id INTEGER REFERENCES mailing(id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL,
+    after_id INTEGER NOT NULL,
+    last_id INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'created' CHECK (status IN ('created', 'published', 'done')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    PRIMARY KEY (mailing_id, seq)
+);
+
+-- Fan-out of mailing is finished when all its chunks are created
+CREATE TABLE IF NOT EXISTS mailing_fanout (
+    mailing_id INTEGER PRIMARY KEY REFERENCES mailing(id) ON DELETE CASCADE,
+    chunks INTEGER NOT NULL,
+    finished_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+-- Finished mailings are moved to archive with their messages
+CREATE TABLE IF NOT EXISTS mailing_archive (
+    id INTEGER PRIMARY KEY,
+    message_text TEXT NOT NULL,
+    mobile_operator_code INTEGER NOT NULL,
+    tag TEXT NOT NULL CHECK (tag IN ('silver', 'gold', 'vip')),
+    filter_choice TEXT NOT NULL CHECK (filter_choice IN ('tag', 'code')),
+    datetime_start TIMESTAMP NOT NULL,
+    datetime_end TIMESTAMP NOT NULL,
+    interval_start TIMESTAMP NOT NULL,
+    interval_end TIMESTAMP NOT NULL,
+    priority TEXT NOT NULL CHECK (priority IN ('transactional', 'bulk')),
+    deleted_at TIMESTAMP,
+    archived_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS message_archive (
+    id INTEGER PRIMARY KEY,
+    date_time_creation TIMESTAMP NOT NULL,
+    try INTEGER NOT NULL,
+    delivery_status BOOLEAN NOT NULL,
+    deferred BOOLEAN NOT NULL,
+    delivered_at TIMESTAMP,
+    mailing_id INTEGER REFERENCES mailing_archive(id) ON DELETE CASCADE,
+    client_id INTEGER REFERENCES client(id)
+);
+
+CREATE INDEX IF NOT EXISTS message_archive_mailing_id_idx ON message_archive (mailing_id);
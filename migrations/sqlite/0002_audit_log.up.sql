@@ -0,0 +1,16 @@
+-- Changes of clients and mailings, diff is JSON object of changed fields
+-- {"field": {"before": ..., "after": ...}}
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TIMESTAMP NOT NULL,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore')),
+    entity TEXT NOT NULL CHECK (entity IN ('client', 'mailing')),
+    entity_id INTEGER NOT NULL,
+    diff TEXT NOT NULL CHECK (json_valid(diff)),
+    request_id TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_id_idx ON audit_log (entity, entity_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor, id);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_id_idx ON audit_log (created_at, id);
@@ -0,0 +1,3 @@
+ALTER TABLE mailing_archive DROP COLUMN version;
+ALTER TABLE client DROP COLUMN version;
+ALTER TABLE mailing DROP COLUMN version;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS import_error;
+DROP TABLE IF EXISTS import_job;
@@ -0,0 +1,24 @@
+-- Async imports of clients from uploaded files
+CREATE TABLE IF NOT EXISTS import_job (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    format TEXT NOT NULL CHECK (format IN ('csv', 'ndjson')),
+    status TEXT NOT NULL CHECK (status IN ('pending', 'running', 'done', 'failed')),
+    total INTEGER NOT NULL DEFAULT 0,
+    imported INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP
+);
+
+-- Invalid rows of imports, they're reported in order of id
+CREATE TABLE IF NOT EXISTS import_error (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER NOT NULL REFERENCES import_job(id) ON DELETE CASCADE,
+    line INTEGER NOT NULL,
+    field TEXT NOT NULL,
+    value TEXT NOT NULL,
+    message TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS import_error_job_id_idx ON import_error (job_id, id);
@@ -0,0 +1,12 @@
+-- Async exports of clients and messages to files of export directory,
+-- request is JSON of entity.ExportRequest
+CREATE TABLE IF NOT EXISTS export_job (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    request TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'running', 'done', 'failed')),
+    rows INTEGER NOT NULL DEFAULT 0,
+    file TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP
+);
@@ -0,0 +1,22 @@
+ALTER TABLE import_job DROP COLUMN request_id;
+ALTER TABLE import_job DROP COLUMN actor;
+
+CREATE TABLE audit_log_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TIMESTAMP NOT NULL,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore')),
+    entity TEXT NOT NULL CHECK (entity IN ('client', 'mailing')),
+    entity_id INTEGER NOT NULL,
+    diff TEXT NOT NULL CHECK (json_valid(diff)),
+    request_id TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO audit_log_old SELECT id, created_at, actor, action, entity, entity_id, diff, request_id FROM audit_log
+    WHERE entity <> 'import' AND action NOT IN ('archive', 'upsert');
+DROP TABLE audit_log;
+ALTER TABLE audit_log_old RENAME TO audit_log;
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_id_idx ON audit_log (entity, entity_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor, id);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_id_idx ON audit_log (created_at, id);
@@ -0,0 +1,24 @@
+-- Archived mailings and upserted batches of import jobs are audited.
+-- CHECK constraints can't be altered, so audit_log is rebuilt.
+CREATE TABLE audit_log_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TIMESTAMP NOT NULL,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'archive', 'upsert')),
+    entity TEXT NOT NULL CHECK (entity IN ('client', 'mailing', 'import')),
+    entity_id INTEGER NOT NULL,
+    diff TEXT NOT NULL CHECK (json_valid(diff)),
+    request_id TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO audit_log_new SELECT id, created_at, actor, action, entity, entity_id, diff, request_id FROM audit_log;
+DROP TABLE audit_log;
+ALTER TABLE audit_log_new RENAME TO audit_log;
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_id_idx ON audit_log (entity, entity_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor, id);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_id_idx ON audit_log (created_at, id);
+
+-- Actor and request which started import job
+ALTER TABLE import_job ADD COLUMN actor TEXT NOT NULL DEFAULT '';
+ALTER TABLE import_job ADD COLUMN request_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE message ADD COLUMN delivered_at TIMESTAMP;
+ALTER TABLE message_archive ADD COLUMN delivered_at TIMESTAMP;
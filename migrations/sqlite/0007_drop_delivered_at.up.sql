@@ -0,0 +1,4 @@
+-- delivered_at was reserved for provider delivery reports. Reports aren't
+-- received, so the column is never written.
+ALTER TABLE message DROP COLUMN delivered_at;
+ALTER TABLE message_archive DROP COLUMN delivered_at;
@@ -0,0 +1,63 @@
+// Package broker abstracts message broker used by producers and managers,
+// so the pipeline works both with NATS JetStream and in memory.
+package broker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrClosed = errors.New("subscription is closed")
+	ErrAcked  = errors.New("message is already acknowledged")
+)
+
+// Header - message headers, keys are case sensitive
+type Header map[string][]string
+
+func (h Header) Get(key string) string {
+	if v := h[key]; len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+func (h Header) Set(key, value string) {
+	h[key] = []string{value}
+}
+
+// Msg is consumed message. It stays pending until it's acknowledged
+// with Ack or Term, or returned with Nak or NakWithDelay.
+type Msg interface {
+	Subject() string
+	Data() []byte
+	Header() Header
+
+	Ack() error
+	Nak() error
+	NakWithDelay(delay time.Duration) error
+	Term() error
+	// InProgress prolongs pending state of the message
+	InProgress() error
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, subj string, data []byte, header Header) error
+}
+
+// Subscription pulls messages of subject. Subscriptions with the same
+// group share messages, so every message is fetched by one of them.
+type Subscription interface {
+	// Fetch waits for at most wait duration and returns up to batch messages.
+	// Empty result without error means there were no messages.
+	Fetch(ctx context.Context, batch int, wait time.Duration) ([]Msg, error)
+	// Close stops subscription. Pending messages aren't acknowledged.
+	Close() error
+}
+
+type Broker interface {
+	Publisher
+	Subscribe(subj, group string) (Subscription, error)
+}
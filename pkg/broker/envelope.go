@@ -0,0 +1,145 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nuid"
+)
+
+// Envelope headers. Keys are in canonical form, so they're the same
+// after passing through NATS.
+const (
+	TypeHeader    = "Msg-Type"
+	VersionHeader = "Msg-Version"
+	// IDHeader is used by JetStream to drop duplicates
+	IDHeader        = "Nats-Msg-Id"
+	CreatedAtHeader = "Created-At"
+	// TraceHeader is W3C trace context
+	TraceHeader   = "Traceparent"
+	TenantHeader  = "Tenant-Id"
+	AttemptHeader = "Attempt"
+)
+
+// Envelope is metadata of message carried in headers, so payload
+// stays the same for consumers that don't know about envelope.
+//
+// Message without envelope is parsed as Envelope with zero Version
+// and empty Type, consumer defines its type by subject.
+type Envelope struct {
+	ID        string
+	Type      string
+	Version   int
+	CreatedAt time.Time
+	Trace     string
+	Tenant    string
+	// Attempt is number of publishing of the same payload, starting from 1
+	Attempt int
+}
+
+// NewEnvelope returns envelope of new message. Trace and tenant are taken from ctx.
+func NewEnvelope(ctx context.Context, typ string, version int) Envelope {
+	return Envelope{
+		ID:        nuid.Next(),
+		Type:      typ,
+		Version:   version,
+		CreatedAt: time.Now().UTC(),
+		Trace:     TraceFrom(ctx),
+		Tenant:    TenantFrom(ctx),
+		Attempt:   1,
+	}
+}
+
+// ParseEnvelope reads envelope from headers
+func ParseEnvelope(h Header) (Envelope, error) {
+	var (
+		env Envelope
+		err error
+	)
+
+	env.Type = h.Get(TypeHeader)
+	if env.Type == "" {
+		return env, nil
+	}
+
+	env.ID = h.Get(IDHeader)
+	env.Trace = h.Get(TraceHeader)
+	env.Tenant = h.Get(TenantHeader)
+
+	if env.Version, err = strconv.Atoi(h.Get(VersionHeader)); err != nil {
+		return env, fmt.Errorf("broker - ParseEnvelope() - %s: %w", VersionHeader, err)
+	}
+	if v := h.Get(AttemptHeader); v != "" {
+		if env.Attempt, err = strconv.Atoi(v); err != nil {
+			return env, fmt.Errorf("broker - ParseEnvelope() - %s: %w", AttemptHeader, err)
+		}
+	}
+	if v := h.Get(CreatedAtHeader); v != "" {
+		if env.CreatedAt, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return env, fmt.Errorf("broker - ParseEnvelope() - %s: %w", CreatedAtHeader, err)
+		}
+	}
+
+	return env, nil
+}
+
+// Header returns headers of envelope, empty fields are omitted
+func (e Envelope) Header() Header {
+	h := Header{}
+	h.Set(TypeHeader, e.Type)
+	h.Set(VersionHeader, strconv.Itoa(e.Version))
+	h.Set(AttemptHeader, strconv.Itoa(e.Attempt))
+
+	if e.ID != "" {
+		h.Set(IDHeader, e.ID)
+	}
+	if !e.CreatedAt.IsZero() {
+		h.Set(CreatedAtHeader, e.CreatedAt.Format(time.RFC3339Nano))
+	}
+	if e.Trace != "" {
+		h.Set(TraceHeader, e.Trace)
+	}
+	if e.Tenant != "" {
+		h.Set(TenantHeader, e.Tenant)
+	}
+
+	return h
+}
+
+// Context returns ctx with trace and tenant of envelope, so they're
+// propagated to messages published while handling.
+func (e Envelope) Context(ctx context.Context) context.Context {
+	if e.Trace != "" {
+		ctx = WithTrace(ctx, e.Trace)
+	}
+	if e.Tenant != "" {
+		ctx = WithTenant(ctx, e.Tenant)
+	}
+
+	return ctx
+}
+
+type (
+	traceKey  struct{}
+	tenantKey struct{}
+)
+
+func WithTrace(ctx context.Context, trace string) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+func TraceFrom(ctx context.Context) string {
+	trace, _ := ctx.Value(traceKey{}).(string)
+	return trace
+}
+
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+func TenantFrom(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
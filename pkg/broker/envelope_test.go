@@ -0,0 +1,51 @@
+package broker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+)
+
+func TestEnvelope(t *testing.T) {
+	// test 1: envelope is passed through headers with context values
+	{
+		ctx := broker.WithTenant(broker.WithTrace(context.Background(), "00-trace-span-01"), "acme")
+		env := broker.NewEnvelope(ctx, "mailing", 2)
+		env.Attempt = 3
+
+		got, err := broker.ParseEnvelope(env.Header())
+		assert.Equal(t, err, nil)
+		assert.Equal(t, got.ID, env.ID)
+		assert.Equal(t, got.Type, "mailing")
+		assert.Equal(t, got.Version, 2)
+		assert.Equal(t, got.Attempt, 3)
+		assert.Equal(t, got.CreatedAt.Equal(env.CreatedAt), true)
+
+		ctx = got.Context(context.Background())
+		assert.Equal(t, broker.TraceFrom(ctx), "00-trace-span-01")
+		assert.Equal(t, broker.TenantFrom(ctx), "acme")
+	}
+	// test 2: message without envelope
+	{
+		env, err := broker.ParseEnvelope(nil)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, env.Type, "")
+		assert.Equal(t, env.Version, 0)
+	}
+	// test 3: malformed headers
+	{
+		h := broker.Header{}
+		h.Set(broker.TypeHeader, "mailing")
+		h.Set(broker.VersionHeader, "v1")
+		_, err := broker.ParseEnvelope(h)
+		assert.NotEqual(t, err, nil)
+
+		h.Set(broker.VersionHeader, "1")
+		h.Set(broker.CreatedAtHeader, time.Now().String())
+		_, err = broker.ParseEnvelope(h)
+		assert.NotEqual(t, err, nil)
+	}
+}
@@ -0,0 +1,241 @@
+package broker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is deterministic in-memory Broker for tests.
+//
+// Messages of subject are fetched in order of publishing by all the
+// subscriptions of the same group. Returned or delayed messages are put
+// at the end of queue. Messages stay pending until acknowledged;
+// pending messages of closed subscription are returned to queue.
+type Memory struct {
+	mu     sync.Mutex
+	queues map[string]*memQueue
+
+	// now is clock of delayed redelivery, it's replaceable in tests
+	now func() time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		queues: make(map[string]*memQueue),
+		now:    time.Now,
+	}
+}
+
+// SetClock replaces clock used for delayed redelivery
+func (b *Memory) SetClock(now func() time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.now = now
+}
+
+type memQueue struct {
+	subj    string
+	group   string
+	ready   []*memMsg
+	pending map[*memMsg]*memSubscription
+
+	// notify is closed and replaced when message is queued
+	notify chan struct{}
+}
+
+type memMsg struct {
+	broker *Memory
+	queue  *memQueue
+
+	subj        string
+	data        []byte
+	header      Header
+	availableAt time.Time
+}
+
+type memSubscription struct {
+	broker *Memory
+	queue  *memQueue
+	closed bool
+}
+
+// Publish delivers message to all the groups subscribed to subj.
+// Message isn't stored if there are no subscriptions.
+func (b *Memory) Publish(_ context.Context, subj string, data []byte, header Header) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, q := range b.queues {
+		if q.subj != subj {
+			continue
+		}
+
+		h := make(Header, len(header))
+		for k, v := range header {
+			h[k] = append([]string(nil), v...)
+		}
+
+		b.push(q, &memMsg{
+			broker:      b,
+			queue:       q,
+			subj:        subj,
+			data:        append([]byte(nil), data...),
+			header:      h,
+			availableAt: b.now(),
+		})
+	}
+
+	return nil
+}
+
+func (b *Memory) Subscribe(subj, group string) (Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := group + " " + subj
+	q, ok := b.queues[key]
+	if !ok {
+		q = &memQueue{
+			subj:    subj,
+			group:   group,
+			pending: make(map[*memMsg]*memSubscription),
+			notify:  make(chan struct{}),
+		}
+		b.queues[key] = q
+	}
+
+	return &memSubscription{broker: b, queue: q}, nil
+}
+
+// Pending returns count of queued and pending messages of subject in group
+func (b *Memory) Pending(subj, group string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q, ok := b.queues[group+" "+subj]
+	if !ok {
+		return 0
+	}
+
+	return len(q.ready) + len(q.pending)
+}
+
+func (b *Memory) push(q *memQueue, m *memMsg) {
+	q.ready = append(q.ready, m)
+
+	close(q.notify)
+	q.notify = make(chan struct{})
+}
+
+func (s *memSubscription) Fetch(ctx context.Context, batch int, wait time.Duration) ([]Msg, error) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		s.broker.mu.Lock()
+		if s.closed {
+			s.broker.mu.Unlock()
+			return nil, ErrClosed
+		}
+
+		msgs := s.take(batch)
+		notify := s.queue.notify
+		s.broker.mu.Unlock()
+
+		if len(msgs) > 0 {
+			return msgs, nil
+		}
+
+		select {
+		case <-notify:
+		case <-timer.C:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// take moves available messages from queue to pending
+func (s *memSubscription) take(batch int) []Msg {
+	var (
+		msgs []Msg
+		left []*memMsg
+		now  = s.broker.now()
+	)
+	for _, m := range s.queue.ready {
+		if len(msgs) < batch && !m.availableAt.After(now) {
+			s.queue.pending[m] = s
+			msgs = append(msgs, m)
+		} else {
+			left = append(left, m)
+		}
+	}
+	s.queue.ready = left
+
+	return msgs
+}
+
+func (s *memSubscription) Close() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.closed = true
+	for m, owner := range s.queue.pending {
+		if owner == s {
+			delete(s.queue.pending, m)
+			m.availableAt = s.broker.now()
+			s.broker.push(s.queue, m)
+		}
+	}
+
+	return nil
+}
+
+func (m *memMsg) Subject() string { return m.subj }
+func (m *memMsg) Data() []byte    { return m.data }
+func (m *memMsg) Header() Header  { return m.header }
+
+func (m *memMsg) Ack() error  { return m.finish() }
+func (m *memMsg) Term() error { return m.finish() }
+func (m *memMsg) Nak() error  { return m.NakWithDelay(0) }
+
+func (m *memMsg) NakWithDelay(delay time.Duration) error {
+	m.broker.mu.Lock()
+	defer m.broker.mu.Unlock()
+
+	if _, ok := m.queue.pending[m]; !ok {
+		return ErrAcked
+	}
+	delete(m.queue.pending, m)
+
+	m.availableAt = m.broker.now().Add(delay)
+	m.broker.push(m.queue, m)
+
+	return nil
+}
+
+func (m *memMsg) InProgress() error {
+	m.broker.mu.Lock()
+	defer m.broker.mu.Unlock()
+
+	if _, ok := m.queue.pending[m]; !ok {
+		return ErrAcked
+	}
+
+	return nil
+}
+
+func (m *memMsg) finish() error {
+	m.broker.mu.Lock()
+	defer m.broker.mu.Unlock()
+
+	if _, ok := m.queue.pending[m]; !ok {
+		return ErrAcked
+	}
+	delete(m.queue.pending, m)
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package broker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+)
+
+const (
+	subj  = "mailing.general"
+	group = "workers"
+)
+
+func fetch(t *testing.T, sub broker.Subscription, batch int) ([]broker.Msg, string) {
+	msgs, err := sub.Fetch(context.Background(), batch, 10*time.Millisecond)
+	assert.Equal(t, err, nil)
+
+	var data string
+	for _, msg := range msgs {
+		data += string(msg.Data())
+	}
+
+	return msgs, data
+}
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+
+	// test 1: messages are fetched in order and shared by group
+	{
+		b := broker.NewMemory()
+		first, _ := b.Subscribe(subj, group)
+		second, _ := b.Subscribe(subj, group)
+		other, _ := b.Subscribe(subj, "audit")
+
+		h := broker.Header{}
+		h.Set("Priority", "bulk")
+		for _, d := range []string{"a", "b", "c"} {
+			_ = b.Publish(ctx, subj, []byte(d), h)
+		}
+
+		msgs, data := fetch(t, first, 2)
+		assert.Equal(t, data, "ab")
+		assert.Equal(t, msgs[0].Header().Get("Priority"), "bulk")
+		_, data = fetch(t, second, 2)
+		assert.Equal(t, data, "c")
+		_, data = fetch(t, other, 5)
+		assert.Equal(t, data, "abc")
+
+		assert.Equal(t, msgs[0].Ack(), nil)
+		assert.Equal(t, msgs[0].Ack(), broker.ErrAcked)
+		assert.Equal(t, b.Pending(subj, group), 2)
+	}
+	// test 2: Nak redelivers message, NakWithDelay waits for the clock
+	{
+		now := time.Now()
+		b := broker.NewMemory()
+		b.SetClock(func() time.Time { return now })
+		sub, _ := b.Subscribe(subj, group)
+		_ = b.Publish(ctx, subj, []byte("a"), nil)
+		_ = b.Publish(ctx, subj, []byte("b"), nil)
+
+		msgs, _ := fetch(t, sub, 2)
+		assert.Equal(t, msgs[0].NakWithDelay(time.Minute), nil)
+		assert.Equal(t, msgs[1].Nak(), nil)
+
+		_, data := fetch(t, sub, 2)
+		assert.Equal(t, data, "b")
+
+		now = now.Add(2 * time.Minute)
+		_, data = fetch(t, sub, 2)
+		assert.Equal(t, data, "a")
+	}
+	// test 3: pending messages of closed subscription are redelivered
+	{
+		b := broker.NewMemory()
+		first, _ := b.Subscribe(subj, group)
+		second, _ := b.Subscribe(subj, group)
+		_ = b.Publish(ctx, subj, []byte("a"), nil)
+
+		_, data := fetch(t, first, 1)
+		assert.Equal(t, data, "a")
+		assert.Equal(t, first.Close(), nil)
+
+		_, err := first.Fetch(ctx, 1, time.Millisecond)
+		assert.Equal(t, err, broker.ErrClosed)
+		_, data = fetch(t, second, 1)
+		assert.Equal(t, data, "a")
+	}
+}
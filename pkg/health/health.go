@@ -0,0 +1,132 @@
+// Package health checks dependencies of service for readiness probe.
+//
+// Checks are run concurrently, every check is bounded by timeout of
+// checker. Service is ready if all the critical checks pass, optional
+// checks are only reported.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTimeout is error of check that didn't return within timeout
+var ErrTimeout = errors.New("check timed out")
+
+// Statuses of check and of report
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check returns nil if dependency is healthy. It must return when ctx is done.
+type Check func(ctx context.Context) error
+
+// Result of check, latency is in milliseconds
+type Result struct {
+	Status   string  `json:"status"`
+	Latency  float64 `json:"latency_ms"`
+	Optional bool    `json:"optional,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// Report is results of checks by their names. Status is down if any
+// critical check fails.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether all the critical checks passed
+func (r *Report) Ready() bool {
+	return r.Status == StatusUp
+}
+
+type check struct {
+	name     string
+	f        Check
+	optional bool
+}
+
+type Checker struct {
+	timeout time.Duration
+	checks  []check
+}
+
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers critical check, service isn't ready while it fails
+func (c *Checker) Add(name string, f Check) {
+	c.checks = append(c.checks, check{name: name, f: f})
+}
+
+// AddOptional registers check which is reported but doesn't affect readiness
+func (c *Checker) AddOptional(name string, f Check) {
+	c.checks = append(c.checks, check{name: name, f: f, optional: true})
+}
+
+// Check runs all the checks. Check that doesn't return within timeout
+// fails with ErrTimeout and it's left running.
+func (c *Checker) Check(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := &Report{
+		Status: StatusUp,
+		Checks: make(map[string]Result, len(c.checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, ch := range c.checks {
+		wg.Add(1)
+		go func(ch check) {
+			defer wg.Done()
+
+			res := run(ctx, ch.f)
+			res.Optional = ch.optional
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[ch.name] = res
+			if res.Status == StatusDown && !ch.optional {
+				report.Status = StatusDown
+			}
+		}(ch)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run runs check until ctx is done and measures its latency
+func run(ctx context.Context, f Check) Result {
+	start := time.Now()
+
+	done := make(chan error, 1)
+	go func() { done <- f(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ErrTimeout
+	}
+
+	res := Result{
+		Status:  StatusUp,
+		Latency: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+
+	return res
+}
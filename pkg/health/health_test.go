@@ -0,0 +1,59 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/health"
+)
+
+func TestChecker(t *testing.T) {
+	ctx := context.Background()
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+	hang := func(context.Context) error { select {} }
+
+	// test 1: all the checks pass
+	{
+		c := health.New(time.Second)
+		c.Add("database", up)
+		c.Add("nats", up)
+
+		r := c.Check(ctx)
+		assert.Equal(t, r.Ready(), true)
+		assert.Equal(t, len(r.Checks), 2)
+		assert.Equal(t, r.Checks["nats"].Status, health.StatusUp)
+	}
+
+	// test 2: failed optional check doesn't affect readiness, critical one does
+	{
+		c := health.New(time.Second)
+		c.Add("database", up)
+		c.AddOptional("provider", down)
+
+		r := c.Check(ctx)
+		assert.Equal(t, r.Ready(), true)
+		assert.Equal(t, r.Checks["provider"].Status, health.StatusDown)
+		assert.Equal(t, r.Checks["provider"].Optional, true)
+		assert.Equal(t, r.Checks["provider"].Error, "connection refused")
+
+		c.Add("nats", down)
+		assert.Equal(t, c.Check(ctx).Ready(), false)
+	}
+
+	// test 3: check is bounded by timeout even if it ignores ctx
+	{
+		c := health.New(10 * time.Millisecond)
+		c.Add("scheduler", hang)
+
+		start := time.Now()
+		r := c.Check(ctx)
+		assert.Equal(t, time.Since(start) < time.Second, true)
+		assert.Equal(t, r.Ready(), false)
+		assert.Equal(t, r.Checks["scheduler"].Error, health.ErrTimeout.Error())
+		assert.Equal(t, r.Checks["scheduler"].Latency >= 10, true)
+	}
+}
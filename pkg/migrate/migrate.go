@@ -0,0 +1,350 @@
+// Package migrate applies versioned SQL migrations to PostgreSQL or SQLite.
+//
+// Migrations are files "<version>_<name>.up.sql" and "<version>_<name>.down.sql",
+// e.g. "0001_init.up.sql". Version of schema is stored in schema_migrations table.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// _lockKey is key of advisory lock that serializes migrators
+const _lockKey int64 = 7_241_001
+
+var (
+	ErrOutdated     = errors.New("schema is outdated")
+	ErrNoMigration  = errors.New("migration not found")
+	ErrNoDownScript = errors.New("down migration not found")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load reads migrations from dir of fsys ordered by version
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrate - Load(): %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("migrate - Load(): %w", err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migrate - Load(): version %d has two names: %s, %s",
+				version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrate - Load(): version %d doesn't have up migration", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Status of schema. Pending migrations aren't applied yet.
+type Status struct {
+	Version int
+	Latest  int
+	Pending []Migration
+}
+
+// driver runs migrations on database
+type driver interface {
+	// lock serializes migrators and creates version table if it doesn't exist
+	lock(ctx context.Context) (unlock func(), err error)
+	// version is 0 if version table doesn't exist
+	version(ctx context.Context) (int, error)
+	// apply runs sql and sets version in one transaction, empty sql only sets version
+	apply(ctx context.Context, sql string, version int) error
+}
+
+// Migrator applies migrations with single connection. Every migration runs
+// in transaction, migrators of other processes wait for lock of database.
+type Migrator struct {
+	db         driver
+	migrations []Migration
+}
+
+// New returns migrator of PostgreSQL, migrators are serialized by advisory lock
+func New(conn *pgx.Conn, migrations []Migration) *Migrator {
+	return &Migrator{&pgDriver{conn}, migrations}
+}
+
+// Up applies all the pending migrations
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.Goto(ctx, latest(m.migrations))
+}
+
+// Down reverts n last applied migrations
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	return m.locked(ctx, func() error {
+		current, err := m.db.version(ctx)
+		if err != nil {
+			return err
+		}
+
+		target := 0
+		applied := 0
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if m.migrations[i].Version > current {
+				continue
+			}
+			if applied == n {
+				target = m.migrations[i].Version
+				break
+			}
+			applied++
+		}
+
+		return m.migrate(ctx, current, target)
+	})
+}
+
+// Goto migrates schema up or down to version. Version 0 is empty schema.
+func (m *Migrator) Goto(ctx context.Context, target int) error {
+	if target != 0 && find(m.migrations, target) == nil {
+		return fmt.Errorf("migrate - Goto(): %d: %w", target, ErrNoMigration)
+	}
+
+	return m.locked(ctx, func() error {
+		current, err := m.db.version(ctx)
+		if err != nil {
+			return err
+		}
+
+		return m.migrate(ctx, current, target)
+	})
+}
+
+// Force sets version without applying migrations. It's used to adopt
+// database created without migrator or to recover after manual fix.
+func (m *Migrator) Force(ctx context.Context, target int) error {
+	if target != 0 && find(m.migrations, target) == nil {
+		return fmt.Errorf("migrate - Force(): %d: %w", target, ErrNoMigration)
+	}
+
+	return m.locked(ctx, func() error {
+		if err := m.db.apply(ctx, "", target); err != nil {
+			return fmt.Errorf("migrate - Force(): %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Status reads version of schema. It doesn't take lock and doesn't create
+// version table, so it's read-only.
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	current, err := m.db.version(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate - Status(): %w", err)
+	}
+
+	s := &Status{Version: current, Latest: latest(m.migrations)}
+	for _, mig := range m.migrations {
+		if mig.Version > current {
+			s.Pending = append(s.Pending, mig)
+		}
+	}
+
+	return s, nil
+}
+
+// migrate applies up migrations in (current, target] or down
+// migrations in (target, current] in reverse order
+func (m *Migrator) migrate(ctx context.Context, current, target int) error {
+	if target >= current {
+		for _, mig := range m.migrations {
+			if mig.Version > current && mig.Version <= target {
+				if err := m.db.apply(ctx, mig.Up, mig.Version); err != nil {
+					return fmt.Errorf("migrate - up %d_%s: %w", mig.Version, mig.Name, err)
+				}
+			}
+		}
+
+		return nil
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if mig.Version <= target || mig.Version > current {
+			continue
+		}
+		if mig.Down == "" {
+			return fmt.Errorf("migrate - down %d_%s: %w", mig.Version, mig.Name, ErrNoDownScript)
+		}
+
+		prev := 0
+		if i > 0 {
+			prev = m.migrations[i-1].Version
+		}
+		if err := m.db.apply(ctx, mig.Down, prev); err != nil {
+			return fmt.Errorf("migrate - down %d_%s: %w", mig.Version, mig.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// locked runs f under lock of database
+func (m *Migrator) locked(ctx context.Context, f func() error) error {
+	unlock, err := m.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return f()
+}
+
+// pgDriver runs migrations on PostgreSQL connection
+type pgDriver struct {
+	conn *pgx.Conn
+}
+
+func (d *pgDriver) lock(ctx context.Context) (func(), error) {
+	if _, err := d.conn.Exec(ctx, "SELECT pg_advisory_lock($1)", _lockKey); err != nil {
+		return nil, fmt.Errorf("migrate - lock: %w", err)
+	}
+	unlock := func() {
+		d.conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", _lockKey)
+	}
+
+	_, err := d.conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT now()
+		);
+		INSERT INTO schema_migrations (version)
+		SELECT 0 WHERE NOT EXISTS (SELECT 1 FROM schema_migrations);`)
+	if err != nil {
+		unlock()
+		return nil, fmt.Errorf("migrate - schema_migrations: %w", err)
+	}
+
+	return unlock, nil
+}
+
+func (d *pgDriver) version(ctx context.Context) (int, error) {
+	return version(ctx, d.conn)
+}
+
+func (d *pgDriver) apply(ctx context.Context, sql string, version int) error {
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if sql != "" {
+		if _, err = tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE schema_migrations SET version = $1, applied_at = now()", version)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Querier is connection or pool used to check schema
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Check returns ErrOutdated if schema version is older than the latest migration
+func Check(ctx context.Context, q Querier, migrations []Migration) error {
+	current, err := version(ctx, q)
+	if err != nil {
+		return fmt.Errorf("migrate - Check(): %w", err)
+	}
+
+	if want := latest(migrations); current < want {
+		return fmt.Errorf("migrate - Check(): version %d, latest %d: %w", current, want, ErrOutdated)
+	}
+
+	return nil
+}
+
+// version returns applied version, it's 0 if schema_migrations doesn't exist
+func version(ctx context.Context, q Querier) (int, error) {
+	var exists bool
+	err := q.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	var v int
+	err = q.QueryRow(ctx, "SELECT version FROM schema_migrations LIMIT 1").Scan(&v)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+
+	return v, err
+}
+
+func latest(migrations []Migration) int {
+	if len(migrations) == 0 {
+		return 0
+	}
+
+	return migrations[len(migrations)-1].Version
+}
+
+func find(migrations []Migration, version int) *Migration {
+	for i := range migrations {
+		if migrations[i].Version == version {
+			return &migrations[i]
+		}
+	}
+
+	return nil
+}
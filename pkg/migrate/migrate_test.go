@@ -0,0 +1,122 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-playground/assert/v2"
+	"gitlab.com/fluxx1on_group/event_message_service/migrations"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/migrate"
+	_ "modernc.org/sqlite"
+)
+
+func TestLoad(t *testing.T) {
+	// test 1: migrations are ordered by version, unknown files are skipped
+	{
+		fsys := fstest.MapFS{
+			"sql/0010_b.up.sql":   {Data: []byte("B")},
+			"sql/0002_a.up.sql":   {Data: []byte("A")},
+			"sql/0002_a.down.sql": {Data: []byte("-A")},
+			"sql/README.md":       {Data: []byte("docs")},
+		}
+
+		ms, err := migrate.Load(fsys, "sql")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(ms), 2)
+		assert.Equal(t, ms[0], migrate.Migration{Version: 2, Name: "a", Up: "A", Down: "-A"})
+		assert.Equal(t, ms[1], migrate.Migration{Version: 10, Name: "b", Up: "B"})
+	}
+
+	// test 2: up script is required
+	{
+		fsys := fstest.MapFS{"sql/0001_a.down.sql": {Data: []byte("-A")}}
+
+		_, err := migrate.Load(fsys, "sql")
+		assert.NotEqual(t, err, nil)
+	}
+
+	// test 3: scripts of the same version have the same name
+	{
+		fsys := fstest.MapFS{
+			"sql/0001_a.up.sql":   {Data: []byte("A")},
+			"sql/0001_b.down.sql": {Data: []byte("-B")},
+		}
+
+		_, err := migrate.Load(fsys, "sql")
+		assert.NotEqual(t, err, nil)
+	}
+
+	// test 4: embedded migrations are consistent and revertible
+	{
+		for fsys, dir := range map[fs.FS]string{
+			migrations.Postgres: migrations.PostgresDir,
+			migrations.SQLite:   migrations.SQLiteDir,
+		} {
+			ms, err := migrate.Load(fsys, dir)
+			assert.Equal(t, err, nil)
+			assert.NotEqual(t, len(ms), 0)
+			for i, m := range ms {
+				assert.Equal(t, m.Version, i+1)
+				assert.NotEqual(t, m.Down, "")
+			}
+		}
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	assert.Equal(t, err, nil)
+	defer db.Close()
+
+	ctx := context.Background()
+	ms, err := migrate.Load(migrations.SQLite, migrations.SQLiteDir)
+	assert.Equal(t, err, nil)
+	m := migrate.NewSQLite(db, ms)
+
+	// test 1: status of empty database is read without creating version table,
+	// all the migrations are applied to it
+	{
+		s, err := m.Status(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, s.Version, 0)
+		assert.Equal(t, len(s.Pending), len(ms))
+
+		var tables int
+		err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'").Scan(&tables)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, tables, 0)
+
+		assert.Equal(t, m.Up(ctx), nil)
+
+		s, err = m.Status(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, s.Version, s.Latest)
+		assert.Equal(t, len(s.Pending), 0)
+	}
+
+	// test 2: down migrations revert schema, so it's applied again
+	{
+		assert.Equal(t, m.Goto(ctx, 0), nil)
+
+		var tables int
+		err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'mailing'").Scan(&tables)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, tables, 0)
+
+		assert.Equal(t, m.Up(ctx), nil)
+	}
+
+	// test 3: forced version isn't migrated
+	{
+		assert.Equal(t, m.Force(ctx, 0), nil)
+
+		s, err := m.Status(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, s.Version, 0)
+		assert.Equal(t, len(s.Pending), len(ms))
+	}
+}
@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// NewSQLite returns migrator of SQLite database. Database file is used by
+// one process, so migrators aren't serialized between processes.
+func NewSQLite(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{&sqliteDriver{db}, migrations}
+}
+
+// sqliteDriver runs migrations on SQLite database
+type sqliteDriver struct {
+	db *sql.DB
+}
+
+func (d *sqliteDriver) lock(ctx context.Context) (func(), error) {
+	_, err := d.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		INSERT INTO schema_migrations (version)
+		SELECT 0 WHERE NOT EXISTS (SELECT 1 FROM schema_migrations);`)
+	if err != nil {
+		return nil, fmt.Errorf("migrate - schema_migrations: %w", err)
+	}
+
+	return func() {}, nil
+}
+
+// version returns applied version, it's 0 if schema_migrations doesn't exist
+func (d *sqliteDriver) version(ctx context.Context) (int, error) {
+	var exists bool
+	err := d.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')",
+	).Scan(&exists)
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	var v int
+	err = d.db.QueryRowContext(ctx, "SELECT version FROM schema_migrations LIMIT 1").Scan(&v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	return v, err
+}
+
+func (d *sqliteDriver) apply(ctx context.Context, script string, version int) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if script != "" {
+		if _, err = tx.ExecContext(ctx, script); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE schema_migrations SET version = ?, applied_at = CURRENT_TIMESTAMP", version)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,127 @@
+package nats_server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+)
+
+// _publishWait bounds waiting for publish acknowledgement if ctx
+// doesn't have deadline
+const _publishWait = 5 * time.Second
+
+// Broker is broker.Broker over JetStream. Subjects must be persisted
+// by Stream, every group is a durable consumer of Stream.
+type Broker struct {
+	conn *Connection
+
+	stream  string
+	ackWait time.Duration
+}
+
+func NewBroker(conn *Connection, stream string, ackWait time.Duration) *Broker {
+	return &Broker{conn, stream, ackWait}
+}
+
+// Publish returns when message is stored by JetStream. Subject could be
+// persisted by any stream, message isn't published if there's no one.
+func (b *Broker) Publish(ctx context.Context, subj string, data []byte, header broker.Header) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, _publishWait)
+		defer cancel()
+	}
+
+	_, err := b.conn.JS.PublishMsg(&nats.Msg{
+		Subject: subj,
+		Data:    data,
+		Header:  nats.Header(header),
+	}, nats.Context(ctx))
+
+	return err
+}
+
+// Subscribe binds pull subscription to durable consumer shared by
+// instances. Consumer isn't deleted when subscription is closed.
+func (b *Broker) Subscribe(subj, group string) (broker.Subscription, error) {
+	durable := durableName(subj, group)
+
+	err := b.conn.EnsureConsumer(b.stream, &nats.ConsumerConfig{
+		Durable:       durable,
+		FilterSubject: subj,
+		AckPolicy:     nats.AckExplicitPolicy,
+		AckWait:       b.ackWait,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := b.conn.JS.PullSubscribe(subj, durable, nats.Bind(b.stream, durable))
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription{sub}, nil
+}
+
+// durableName returns consumer name of group. Dots aren't allowed in names.
+func durableName(subj, group string) string {
+	return group + "_" + strings.ReplaceAll(subj, ".", "_")
+}
+
+type subscription struct {
+	sub *nats.Subscription
+}
+
+func (s *subscription) Fetch(ctx context.Context, batch int, wait time.Duration) ([]broker.Msg, error) {
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	msgs, err := s.sub.Fetch(batch, nats.Context(ctx))
+	if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		err = nil
+	}
+	if errors.Is(err, nats.ErrBadSubscription) {
+		err = broker.ErrClosed
+	}
+
+	res := make([]broker.Msg, 0, len(msgs))
+	for _, msg := range msgs {
+		res = append(res, &message{msg})
+	}
+
+	return res, err
+}
+
+func (s *subscription) Close() error {
+	return s.sub.Unsubscribe()
+}
+
+type message struct {
+	msg *nats.Msg
+}
+
+func (m *message) Subject() string       { return m.msg.Subject }
+func (m *message) Data() []byte          { return m.msg.Data }
+func (m *message) Header() broker.Header { return broker.Header(m.msg.Header) }
+
+func (m *message) Ack() error        { return ackErr(m.msg.Ack()) }
+func (m *message) Nak() error        { return ackErr(m.msg.Nak()) }
+func (m *message) Term() error       { return ackErr(m.msg.Term()) }
+func (m *message) InProgress() error { return ackErr(m.msg.InProgress()) }
+
+func (m *message) NakWithDelay(delay time.Duration) error {
+	return ackErr(m.msg.NakWithDelay(delay))
+}
+
+func ackErr(err error) error {
+	if errors.Is(err, nats.ErrMsgAlreadyAckd) {
+		return broker.ErrAcked
+	}
+
+	return err
+}
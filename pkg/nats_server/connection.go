@@ -1,43 +1,347 @@
 package nats_server
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"math/rand"
+	"os"
 	"time"
 
 	"github.com/nats-io/nats.go"
 )
 
+const (
+	_defaultConnectAttempts  = 10
+	_defaultConnectWait      = 2 * time.Second
+	_defaultReconnectWait    = 1 * time.Second
+	_defaultMaxReconnectWait = 30 * time.Second
+)
+
+// ErrNotConnected is returned by Check if connection isn't established
+var ErrNotConnected = errors.New("nats: not connected")
+
 // Config - config to connect with nats.
+//
+// If Embedded server is set connection is made in-process, URL, Auth
+// and TLS are ignored.
 type Config struct {
-	URL string
+	URL      string
+	Embedded *Embedded
+
+	Auth Auth
+	TLS  TLS
+
+	// ConnectAttempts bounds connection attempts at startup,
+	// ConnectWait is delay between them
+	ConnectAttempts int
+	ConnectWait     time.Duration
+
+	// Lost connection is reconnected until it's closed. ReconnectWait is
+	// delay of the first attempt, it's doubled by every failed attempt
+	// up to MaxReconnectWait.
+	ReconnectWait    time.Duration
+	MaxReconnectWait time.Duration
+}
+
+// Auth - only one of methods is used: user and password, token,
+// NKey seed file or credentials file (JWT and NKey seed).
+type Auth struct {
+	User     string
+	Password string
+
+	Token string
+
+	NKeySeedFile    string
+	CredentialsFile string
+}
+
+// TLS - CAFile verifies server certificate if it isn't trusted by system.
+// CertFile and KeyFile are client certificate for mutual TLS.
+type TLS struct {
+	Enabled  bool
+	CAFile   string
+	CertFile string
+	KeyFile  string
 }
 
 // Connection - nats connection that using JetStream basically
 type Connection struct {
 	*nats.Conn
+	JS nats.JetStreamContext
+
+	// closed is released by ClosedHandler when connection is fully closed
+	closed chan struct{}
 }
 
-func OpenConnection(cfg Config) *Connection {
+// OpenConnection connects to NATS. Connection is retried ConnectAttempts
+// times at startup, after that lost connection is reconnected by client
+// with backoff, so it's never given up.
+func OpenConnection(cfg Config) (*Connection, error) {
+	closed := make(chan struct{})
+
 	options := nats.GetDefaultOptions()
 	options.Url = cfg.URL
 	options.AllowReconnect = true
 	options.Timeout = 5 * time.Second
-	options.MaxReconnect = 10
-	options.RetryOnFailedConnect = true
+	options.MaxReconnect = -1
+	options.CustomReconnectDelayCB = reconnectDelay(cfg.ReconnectWait, cfg.MaxReconnectWait)
+	options.DisconnectedErrCB = disconnected
+	options.ReconnectedCB = reconnected
+	options.ClosedCB = func(conn *nats.Conn) {
+		connectionClosed(conn)
+		close(closed)
+	}
+
+	if cfg.Embedded != nil {
+		options.InProcessServer = cfg.Embedded.Server
+	} else {
+		opts, err := security(cfg.Auth, cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("nats_server - OpenConnection(): %w", err)
+		}
+		for _, opt := range opts {
+			if err = opt(&options); err != nil {
+				return nil, fmt.Errorf("nats_server - OpenConnection(): %w", err)
+			}
+		}
+	}
+
+	conn, err := connect(&options, cfg.ConnectAttempts, cfg.ConnectWait)
+	if err != nil {
+		return nil, fmt.Errorf("nats_server - OpenConnection(): %w", err)
+	}
+	ConnectionEvents.WithLabelValues("connected").Inc()
+	ConnectionUp.Set(1)
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("nats_server - OpenConnection() - JetStream(): %w", err)
+	}
+
+	server := &Connection{
+		Conn:   conn,
+		JS:     js,
+		closed: closed,
+	}
+
+	return server, nil
+}
+
+// connect makes bounded number of connection attempts
+func connect(options *nats.Options, attempts int, wait time.Duration) (*nats.Conn, error) {
+	if attempts <= 0 {
+		attempts = _defaultConnectAttempts
+	}
+	if wait <= 0 {
+		wait = _defaultConnectWait
+	}
+
+	var err error
+	for i := 1; ; i++ {
+		var conn *nats.Conn
+		conn, err = options.Connect()
+		if err == nil {
+			return conn, nil
+		}
+
+		// Auth and TLS errors aren't fixed by retry
+		if i == attempts || errors.Is(err, nats.ErrAuthorization) ||
+			errors.Is(err, nats.ErrSecureConnRequired) || errors.Is(err, nats.ErrSecureConnWanted) {
+			break
+		}
+
+		slog.Warn("NATS connection attempt failed",
+			slog.String("URL", options.Url),
+			slog.Int("Attempt", i),
+			slog.String("ErrorMsg", err.Error()))
+		time.Sleep(wait)
+	}
+
+	return nil, fmt.Errorf("can't connect to %s: %w", options.Url, err)
+}
+
+// reconnectDelay returns exponential backoff of reconnection attempts,
+// jitter spreads reconnections of instances after outage
+func reconnectDelay(wait, maxWait time.Duration) nats.ReconnectDelayHandler {
+	if wait <= 0 {
+		wait = _defaultReconnectWait
+	}
+	if maxWait < wait {
+		maxWait = max(wait, _defaultMaxReconnectWait)
+	}
+
+	return func(attempts int) time.Duration {
+		delay := maxWait
+		if attempts < 32 {
+			if d := wait << max(attempts-1, 0); d > 0 && d < maxWait {
+				delay = d
+			}
+		}
 
-	conn, err := options.Connect()
+		return delay + time.Duration(rand.Int63n(int64(delay)/4+1))
+	}
+}
+
+// security returns options of auth and TLS
+func security(auth Auth, tls TLS) ([]nats.Option, error) {
+	var (
+		opts    []nats.Option
+		methods int
+	)
+
+	if auth.User != "" || auth.Password != "" {
+		opts = append(opts, nats.UserInfo(auth.User, auth.Password))
+		methods++
+	}
+	if auth.Token != "" {
+		opts = append(opts, nats.Token(auth.Token))
+		methods++
+	}
+	if auth.NKeySeedFile != "" {
+		opt, err := nats.NkeyOptionFromSeed(auth.NKeySeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("nkey seed: %w", err)
+		}
+		opts = append(opts, opt)
+		methods++
+	}
+	if auth.CredentialsFile != "" {
+		if _, err := os.Stat(auth.CredentialsFile); err != nil {
+			return nil, fmt.Errorf("credentials: %w", err)
+		}
+		opts = append(opts, nats.UserCredentials(auth.CredentialsFile))
+		methods++
+	}
+	if methods > 1 {
+		return nil, errors.New("only one auth method is allowed: user, token, nkey or credentials")
+	}
+
+	if tls.Enabled {
+		opts = append(opts, nats.Secure())
+		if tls.CAFile != "" {
+			opts = append(opts, nats.RootCAs(tls.CAFile))
+		}
+		if tls.CertFile != "" || tls.KeyFile != "" {
+			opts = append(opts, nats.ClientCert(tls.CertFile, tls.KeyFile))
+		}
+	}
+
+	return opts, nil
+}
+
+func disconnected(conn *nats.Conn, err error) {
+	ConnectionEvents.WithLabelValues("disconnected").Inc()
+	ConnectionUp.Set(0)
+
+	attrs := []any{slog.String("URL", conn.ConnectedUrl())}
 	if err != nil {
-		panic("nats connection refused")
+		attrs = append(attrs, slog.String("ErrorMsg", err.Error()))
+	}
+	slog.Warn("NATS disconnected", attrs...)
+}
+
+func reconnected(conn *nats.Conn) {
+	ConnectionEvents.WithLabelValues("reconnected").Inc()
+	ConnectionUp.Set(1)
+
+	slog.Info("NATS reconnected", slog.String("URL", conn.ConnectedUrl()))
+}
+
+func connectionClosed(conn *nats.Conn) {
+	ConnectionEvents.WithLabelValues("closed").Inc()
+	ConnectionUp.Set(0)
+
+	attrs := []any{}
+	if err := conn.LastError(); err != nil {
+		attrs = append(attrs, slog.String("ErrorMsg", err.Error()))
+	}
+	slog.Info("NATS connection closed", attrs...)
+}
+
+// EnsureStream creates stream that persists subjects or updates
+// subjects of existing one. Messages are kept until they are acknowledged,
+// so unacked work survives restart of any worker.
+func (c *Connection) EnsureStream(name string, subjects ...string) error {
+	return c.ensureStream(&nats.StreamConfig{
+		Name:      name,
+		Subjects:  subjects,
+		Retention: nats.WorkQueuePolicy,
+		Storage:   nats.FileStorage,
+	})
+}
+
+// EnsureLogStream creates stream of subjects read by many consumers or
+// updates existing one. Messages aren't removed by acknowledgement,
+// they're kept for maxAge, zero keeps them forever.
+func (c *Connection) EnsureLogStream(name string, maxAge time.Duration, subjects ...string) error {
+	return c.ensureStream(&nats.StreamConfig{
+		Name:      name,
+		Subjects:  subjects,
+		Retention: nats.LimitsPolicy,
+		Storage:   nats.FileStorage,
+		MaxAge:    maxAge,
+	})
+}
+
+func (c *Connection) ensureStream(cfg *nats.StreamConfig) error {
+	_, err := c.JS.StreamInfo(cfg.Name)
+	switch {
+	case errors.Is(err, nats.ErrStreamNotFound):
+		_, err = c.JS.AddStream(cfg)
+	case err == nil:
+		_, err = c.JS.UpdateStream(cfg)
+	}
+
+	return err
+}
+
+// EnsureConsumer creates durable consumer or updates config of existing one.
+// Consumer isn't bound to any subscription, so it outlives every instance.
+func (c *Connection) EnsureConsumer(stream string, cfg *nats.ConsumerConfig) error {
+	_, err := c.JS.UpdateConsumer(stream, cfg)
+	if errors.Is(err, nats.ErrConsumerNotFound) {
+		_, err = c.JS.AddConsumer(stream, cfg)
+	}
+
+	return err
+}
+
+// Check returns ErrNotConnected if connection is lost or closed,
+// reconnecting connection isn't healthy either
+func (c *Connection) Check(context.Context) error {
+	if status := c.Conn.Status(); status != nats.CONNECTED {
+		return fmt.Errorf("%w: %s", ErrNotConnected, status)
 	}
 
-	server := &Connection{conn}
+	return nil
+}
 
-	return server
+// CheckStream checks that JetStream is available and stream exists
+func (c *Connection) CheckStream(ctx context.Context, name string) error {
+	_, err := c.JS.StreamInfo(name, nats.Context(ctx))
+	return err
 }
 
-func (c *Connection) Close(timeout time.Duration) {
-	time.Sleep(timeout)
+// Close drains connection: pending publications are flushed and
+// subscriptions are unsubscribed. If ctx is done before connection
+// was drained, it will be closed immediately.
+func (c *Connection) Close(ctx context.Context) error {
+	if err := c.Conn.Drain(); err != nil {
+		c.Conn.Close()
+		slog.Warn("NATS drain failed", slog.String("ErrorMsg", err.Error()))
+		return err
+	}
 
-	c.Conn.Close()
-	slog.Info("NATS disconnected with timeout")
+	select {
+	case <-c.closed:
+		slog.Info("NATS drained and disconnected")
+		return nil
+	case <-ctx.Done():
+		c.Conn.Close()
+		slog.Warn("NATS disconnected before drain completion")
+		return ctx.Err()
+	}
 }
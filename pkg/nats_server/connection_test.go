@@ -0,0 +1,100 @@
+package nats_server_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	natsd "github.com/nats-io/nats-server/v2/server"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server"
+)
+
+func TestOpenConnection(t *testing.T) {
+	ns, err := natsd.NewServer(&natsd.Options{
+		Host:          "127.0.0.1",
+		Port:          -1,
+		Authorization: "secret",
+		NoSigs:        true,
+	})
+	assert.Equal(t, err, nil)
+	go ns.Start()
+	defer ns.Shutdown()
+	assert.Equal(t, ns.ReadyForConnections(5*time.Second), true)
+
+	cfg := nats_server.Config{
+		URL:             ns.ClientURL(),
+		ConnectAttempts: 2,
+		ConnectWait:     10 * time.Millisecond,
+	}
+
+	// test 1: authorization error isn't retried
+	{
+		_, err := nats_server.OpenConnection(cfg)
+		assert.NotEqual(t, err, nil)
+	}
+	// test 2: token auth
+	{
+		cfg := cfg
+		cfg.Auth.Token = "secret"
+
+		conn, err := nats_server.OpenConnection(cfg)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, conn.Close(context.Background()), nil)
+	}
+	// test 3: more than one auth method
+	{
+		cfg := cfg
+		cfg.Auth.Token = "secret"
+		cfg.Auth.User = "user"
+
+		_, err := nats_server.OpenConnection(cfg)
+		assert.NotEqual(t, err, nil)
+	}
+	// test 4: unreachable server
+	{
+		cfg := cfg
+		cfg.URL = "nats://127.0.0.1:1"
+
+		_, err := nats_server.OpenConnection(cfg)
+		assert.NotEqual(t, err, nil)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	opts := &natsd.Options{Host: "127.0.0.1", Port: -1, NoSigs: true}
+	ns, err := natsd.NewServer(opts)
+	assert.Equal(t, err, nil)
+	go ns.Start()
+	assert.Equal(t, ns.ReadyForConnections(5*time.Second), true)
+
+	conn, err := nats_server.OpenConnection(nats_server.Config{
+		URL:              ns.ClientURL(),
+		ReconnectWait:    time.Millisecond,
+		MaxReconnectWait: 5 * time.Millisecond,
+	})
+	assert.Equal(t, err, nil)
+	defer conn.Close(context.Background())
+
+	// test 1: connection isn't given up after many failed reconnections
+	{
+		opts.Port = ns.Addr().(*net.TCPAddr).Port
+		ns.Shutdown()
+		time.Sleep(200 * time.Millisecond)
+		assert.NotEqual(t, conn.Check(context.Background()), nil)
+		assert.Equal(t, conn.IsClosed(), false)
+
+		ns, err = natsd.NewServer(opts)
+		assert.Equal(t, err, nil)
+		go ns.Start()
+		defer ns.Shutdown()
+		assert.Equal(t, ns.ReadyForConnections(5*time.Second), true)
+
+		deadline := time.Now().Add(5 * time.Second)
+		for conn.Check(context.Background()) != nil && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, conn.Check(context.Background()), nil)
+	}
+}
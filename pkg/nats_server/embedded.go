@@ -0,0 +1,64 @@
+package nats_server
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	natsd "github.com/nats-io/nats-server/v2/server"
+)
+
+const _embeddedReadyTimeout = 10 * time.Second
+
+// EmbeddedConfig - config of NATS server started inside the process.
+//
+// JetStream data is stored in StoreDir, so streams and unacked messages
+// are kept between restarts. If Port is 0 server doesn't listen network
+// and only in-process connections are allowed.
+type EmbeddedConfig struct {
+	ServerName string
+	StoreDir   string
+	Host       string
+	Port       int
+}
+
+// Embedded - NATS server with JetStream running in-process
+type Embedded struct {
+	*natsd.Server
+}
+
+func StartEmbedded(cfg EmbeddedConfig) (*Embedded, error) {
+	opts := &natsd.Options{
+		ServerName: cfg.ServerName,
+		JetStream:  true,
+		StoreDir:   cfg.StoreDir,
+		Host:       cfg.Host,
+		Port:       cfg.Port,
+		DontListen: cfg.Port == 0,
+	}
+
+	ns, err := natsd.NewServer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	go ns.Start()
+
+	if !ns.ReadyForConnections(_embeddedReadyTimeout) {
+		ns.Shutdown()
+		return nil, errors.New("embedded nats server isn't ready for connections")
+	}
+
+	slog.Info("Embedded NATS server started",
+		slog.String("StoreDir", cfg.StoreDir),
+		slog.Bool("Listen", !opts.DontListen))
+
+	return &Embedded{ns}, nil
+}
+
+// Close waits for server shutdown. Connections must be closed before.
+func (e *Embedded) Close() {
+	e.Server.Shutdown()
+	e.Server.WaitForShutdown()
+	slog.Info("Embedded NATS server stopped")
+}
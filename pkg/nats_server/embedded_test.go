@@ -0,0 +1,103 @@
+package nats_server_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server"
+)
+
+func TestEmbeddedPersistence(t *testing.T) {
+	cfg := nats_server.EmbeddedConfig{
+		ServerName: "test",
+		StoreDir:   t.TempDir(),
+	}
+
+	// test 1: message is stored by stream
+	{
+		ns, err := nats_server.StartEmbedded(cfg)
+		assert.Equal(t, err, nil)
+
+		conn, err := nats_server.OpenConnection(nats_server.Config{Embedded: ns})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, conn.EnsureStream("TEST", "test.subject"), nil)
+
+		_, err = conn.JS.Publish("test.subject", []byte("data"))
+		assert.Equal(t, err, nil)
+
+		assert.Equal(t, conn.Close(context.Background()), nil)
+		ns.Close()
+	}
+	// test 2: stream and message are restored after restart
+	{
+		ns, err := nats_server.StartEmbedded(cfg)
+		assert.Equal(t, err, nil)
+		defer ns.Close()
+
+		conn, err := nats_server.OpenConnection(nats_server.Config{Embedded: ns})
+		assert.Equal(t, err, nil)
+		defer conn.Close(context.Background())
+
+		info, err := conn.JS.StreamInfo("TEST")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, info.State.Msgs, uint64(1))
+	}
+}
+
+func TestConnectionCheck(t *testing.T) {
+	ctx := context.Background()
+
+	ns, err := nats_server.StartEmbedded(nats_server.EmbeddedConfig{ServerName: "test", StoreDir: t.TempDir()})
+	assert.Equal(t, err, nil)
+	defer ns.Close()
+
+	conn, err := nats_server.OpenConnection(nats_server.Config{Embedded: ns})
+	assert.Equal(t, err, nil)
+
+	// test 1: connection is healthy, stream is checked by JetStream
+	{
+		assert.Equal(t, conn.Check(ctx), nil)
+		assert.NotEqual(t, conn.CheckStream(ctx, "TEST"), nil)
+
+		assert.Equal(t, conn.EnsureStream("TEST", "test.subject"), nil)
+		assert.Equal(t, conn.CheckStream(ctx, "TEST"), nil)
+	}
+	// test 2: closed connection isn't healthy
+	{
+		assert.Equal(t, conn.Close(ctx), nil)
+		assert.Equal(t, errors.Is(conn.Check(ctx), nats_server.ErrNotConnected), true)
+	}
+}
+
+func TestBrokerPublish(t *testing.T) {
+	ctx := context.Background()
+
+	ns, err := nats_server.StartEmbedded(nats_server.EmbeddedConfig{ServerName: "test", StoreDir: t.TempDir()})
+	assert.Equal(t, err, nil)
+	defer ns.Close()
+
+	conn, err := nats_server.OpenConnection(nats_server.Config{Embedded: ns})
+	assert.Equal(t, err, nil)
+	defer conn.Close(ctx)
+
+	assert.Equal(t, conn.EnsureStream("TEST", "test.subject"), nil)
+	assert.Equal(t, conn.EnsureLogStream("EVENTS", time.Hour, "events.>"), nil)
+	b := nats_server.NewBroker(conn, "TEST", time.Minute)
+
+	// test 1: message is stored by stream of its subject
+	{
+		assert.Equal(t, b.Publish(ctx, "test.subject", []byte("data"), nil), nil)
+		assert.Equal(t, b.Publish(ctx, "events.client.created", []byte("data"), nil), nil)
+
+		info, err := conn.JS.StreamInfo("EVENTS")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, info.State.Msgs, uint64(1))
+	}
+	// test 2: message of subject which isn't persisted isn't lost silently
+	{
+		assert.NotEqual(t, b.Publish(ctx, "other.subject", []byte("data"), nil), nil)
+	}
+}
@@ -0,0 +1,25 @@
+package nats_server
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	ConnectionEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "nats_connection_events_total",
+			Help: "NATS connection lifecycle events",
+		},
+		[]string{"event"},
+	)
+
+	ConnectionUp = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "nats_connection_up",
+			Help: "1 if NATS connection is established",
+		},
+	)
+)
+
+// Collectors returns connection metrics to register
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{ConnectionEvents, ConnectionUp}
+}
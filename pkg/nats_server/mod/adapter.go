@@ -1,17 +1,62 @@
 package mod
 
 import (
-	"github.com/nats-io/nats.go"
+	"context"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
 )
 
 type Manager interface {
 	Subscribe()
+	// Alive reports whether scheduling loop of Subscribe runs
+	Alive() bool
+
+	// Shutdown stops accepting new messages and waits for in-flight handlers.
+	// When ctx is done handlers context is cancelled and they get grace
+	// period to checkpoint their work.
+	Shutdown(ctx context.Context, grace time.Duration) error
 }
 
 type ManagerType string
 
+// Settings of consumption shared by all the managers.
+//
+// Instances with the same Queue group share messages, so every message
+// is delivered to only one of them. Handled messages are kept in progress
+// every AckWait/2 to not be redelivered to another instance.
+type Settings struct {
+	Queue   string
+	AckWait time.Duration
+
+	// Limits overrides DefaultLimit for subject
+	Limits       map[string]Limit
+	DefaultLimit Limit
+
+	// Transactional and Starvation are used by PriorityManager only
+	Transactional string
+	Starvation    Starvation
+}
+
+// Limit bounds concurrency of subject consumption. Workers handle messages
+// concurrently and Queue messages more are fetched in advance.
+// Nothing is pulled from broker while Workers + Queue messages are held.
+type Limit struct {
+	Workers int
+	Queue   int
+}
+
+func (s Settings) limit(subj string) Limit {
+	if l, ok := s.Limits[subj]; ok && l.Workers > 0 {
+		return l
+	}
+
+	return s.DefaultLimit
+}
+
 const (
-	TaskType ManagerType = "TaskManager"
+	TaskType     ManagerType = "TaskManager"
+	PriorityType ManagerType = "PriorityManager"
 )
 
 // TaskManager
@@ -21,6 +66,7 @@ type HandlerGroup struct {
 	Clean MsgTermHandler
 }
 
-type MsgTimeHandler func(*nats.Msg) (bool, Task)
+// MsgTimeHandler receives context that is cancelled on forced shutdown.
+type MsgTimeHandler func(context.Context, broker.Msg) (bool, Task)
 
-type MsgTermHandler nats.MsgHandler
+type MsgTermHandler func(broker.Msg)
@@ -0,0 +1,35 @@
+package mod
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	QueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nats_queue_depth",
+			Help: "Fetched messages waiting for a free worker",
+		},
+		[]string{"subject"},
+	)
+
+	ActiveWorkers = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nats_active_workers",
+			Help: "Workers handling a message",
+		},
+		[]string{"subject"},
+	)
+
+	ProcessingDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "nats_processing_duration_seconds",
+			Help:    "Duration of message handling",
+			Buckets: prometheus.ExponentialBuckets(0.01, 4, 10),
+		},
+		[]string{"subject"},
+	)
+)
+
+// Collectors returns managers metrics to register
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{QueueDepth, ActiveWorkers, ProcessingDuration}
+}
@@ -0,0 +1,150 @@
+package mod
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+)
+
+// PriorityHeader is message header that defines message priority.
+// Messages with Settings.Transactional value of header are served
+// first, the others including messages without header are bulk ones.
+const PriorityHeader = "Priority"
+
+// Starvation bounds delay of bulk messages while transactional ones are served.
+//
+// Bulk message is taken after Ratio transactional ones in a row
+// or if it waits longer than MaxWait.
+type Starvation struct {
+	Ratio   int
+	MaxWait time.Duration
+}
+
+// PriorityManager is TaskManager that serves transactional messages
+// ahead of bulk ones. It uses the same router, so handlers are unaware of it.
+type PriorityManager struct {
+	*TaskManager
+}
+
+func NewPriorityManager(
+	b broker.Broker,
+	router map[string]HandlerGroup,
+	stop chan struct{},
+	settings Settings,
+) *PriorityManager {
+	m := NewTaskManager(b, router, stop, settings)
+	m.newQueue = func(size int) msgQueue {
+		return newPriorityQueue(size, settings.Transactional, settings.Starvation)
+	}
+
+	return &PriorityManager{m}
+}
+
+type queuedMsg struct {
+	msg broker.Msg
+	at  time.Time
+}
+
+// priorityQueue is msgQueue with transactional and bulk queues.
+//
+// ready holds a token for every queued message, so Pop is able
+// to wait for it together with stop channel.
+type priorityQueue struct {
+	mu     sync.Mutex
+	high   []queuedMsg
+	low    []queuedMsg
+	streak int
+
+	ready         chan struct{}
+	transactional string
+	starvation    Starvation
+	now           func() time.Time
+}
+
+func newPriorityQueue(size int, transactional string, starvation Starvation) *priorityQueue {
+	return &priorityQueue{
+		ready:         make(chan struct{}, size),
+		transactional: transactional,
+		starvation:    starvation,
+		now:           time.Now,
+	}
+}
+
+func (q *priorityQueue) isTransactional(msg broker.Msg) bool {
+	return q.transactional != "" && strings.EqualFold(msg.Header().Get(PriorityHeader), q.transactional)
+}
+
+func (q *priorityQueue) Push(msg broker.Msg) {
+	q.mu.Lock()
+	item := queuedMsg{msg, q.now()}
+	if q.isTransactional(msg) {
+		q.high = append(q.high, item)
+	} else {
+		q.low = append(q.low, item)
+	}
+	q.mu.Unlock()
+
+	q.ready <- struct{}{}
+}
+
+func (q *priorityQueue) Pop(stop <-chan struct{}) (broker.Msg, bool) {
+	select {
+	case <-q.ready:
+	case <-stop:
+		return nil, false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Queue could be drained while Pop was waiting for the lock
+	if len(q.high) == 0 && len(q.low) == 0 {
+		return nil, false
+	}
+
+	var item queuedMsg
+	if q.takeBulk() {
+		item, q.low = q.low[0], q.low[1:]
+		q.streak = 0
+	} else {
+		item, q.high = q.high[0], q.high[1:]
+		q.streak++
+	}
+
+	return item.msg, true
+}
+
+// takeBulk reports whether bulk message is served next.
+func (q *priorityQueue) takeBulk() bool {
+	switch {
+	case len(q.low) == 0:
+		return false
+	case len(q.high) == 0:
+		return true
+	case q.starvation.Ratio > 0 && q.streak >= q.starvation.Ratio:
+		return true
+	case q.starvation.MaxWait > 0 && q.now().Sub(q.low[0].at) >= q.starvation.MaxWait:
+		return true
+	}
+
+	return false
+}
+
+func (q *priorityQueue) Drain() []broker.Msg {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var msgs []broker.Msg
+	for _, item := range append(q.high, q.low...) {
+		select {
+		case <-q.ready:
+		default:
+		}
+		msgs = append(msgs, item.msg)
+	}
+	q.high, q.low = nil, nil
+
+	return msgs
+}
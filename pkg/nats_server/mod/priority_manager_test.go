@@ -0,0 +1,90 @@
+package mod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+)
+
+type testMsg struct {
+	broker.Msg
+
+	data   []byte
+	header broker.Header
+}
+
+func (m *testMsg) Data() []byte          { return m.data }
+func (m *testMsg) Header() broker.Header { return m.header }
+
+// Values of PriorityHeader, transactional is set to queue
+const (
+	transactional = "transactional"
+	bulk          = "bulk"
+)
+
+func newMsg(data string, priority string) broker.Msg {
+	msg := &testMsg{data: []byte(data), header: broker.Header{}}
+	if priority != "" {
+		msg.header.Set(PriorityHeader, priority)
+	}
+
+	return msg
+}
+
+func popAll(t *testing.T, q *priorityQueue, n int) string {
+	var order string
+	for i := 0; i < n; i++ {
+		msg, ok := q.Pop(nil)
+		assert.Equal(t, ok, true)
+		order += string(msg.Data())
+	}
+
+	return order
+}
+
+func TestPriorityQueue(t *testing.T) {
+	// test 1: transactional messages are served first
+	{
+		q := newPriorityQueue(8, transactional, Starvation{})
+		q.Push(newMsg("a", bulk))
+		q.Push(newMsg("b", ""))
+		q.Push(newMsg("C", transactional))
+		q.Push(newMsg("D", transactional))
+
+		assert.Equal(t, popAll(t, q, 4), "CDab")
+	}
+	// test 2: bulk message is served after Ratio transactional ones
+	{
+		q := newPriorityQueue(8, transactional, Starvation{Ratio: 2})
+		q.Push(newMsg("a", bulk))
+		for _, d := range []string{"B", "C", "D", "E"} {
+			q.Push(newMsg(d, transactional))
+		}
+
+		assert.Equal(t, popAll(t, q, 5), "BCaDE")
+	}
+	// test 3: bulk message waiting longer than MaxWait is served
+	{
+		now := time.Now()
+		q := newPriorityQueue(8, transactional, Starvation{MaxWait: time.Minute})
+		q.now = func() time.Time { return now }
+		q.Push(newMsg("a", bulk))
+		q.Push(newMsg("B", transactional))
+		q.Push(newMsg("C", transactional))
+
+		assert.Equal(t, popAll(t, q, 1), "B")
+		now = now.Add(2 * time.Minute)
+		assert.Equal(t, popAll(t, q, 2), "aC")
+	}
+	// test 4: stopped queue
+	{
+		q := newPriorityQueue(8, transactional, Starvation{})
+		stop := make(chan struct{})
+		close(stop)
+
+		_, ok := q.Pop(stop)
+		assert.Equal(t, ok, false)
+	}
+}
@@ -1,16 +1,26 @@
 package mod
 
 import (
+	"context"
+	"errors"
 	"log/slog"
-	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/nats-io/nats.go"
-	server "gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
 )
 
+var ErrShutdownTimeout = errors.New("in-flight handlers weren't finished in time")
+
+// _fetchWait is maximum time of waiting for messages in one pull request
+const _fetchWait = 2 * time.Second
+
+// _rate is period of scheduling loop of delayed tasks
+const _rate = 20 * time.Second
+
 type Task struct {
-	Msg   *nats.Msg
+	Msg   broker.Msg
 	start time.Time
 	end   time.Time
 }
@@ -30,105 +40,382 @@ func (t Task) In() int {
 	return -1
 }
 
-func (t Task) SetIn(t_start time.Time, t_end time.Time) {
+func (t *Task) SetIn(t_start time.Time, t_end time.Time) {
 	t.start = t_start
 	t.end = t_end
 }
 
+// lane is consumption pipeline of one subject.
+//
+// slots holds a token for every fetched and not yet handled message,
+// so its capacity is the bound of messages pulled from broker.
+type lane struct {
+	subj    string
+	sub     broker.Subscription
+	handler HandlerGroup
+
+	queue   msgQueue
+	slots   chan struct{}
+	workers int
+}
+
+// msgQueue buffers fetched messages before workers take them.
+// Push never blocks, since lane slots bound messages count.
+type msgQueue interface {
+	Push(broker.Msg)
+	// Pop waits for the next message. It returns false if stop is closed.
+	Pop(stop <-chan struct{}) (broker.Msg, bool)
+	// Drain removes all the messages left.
+	Drain() []broker.Msg
+}
+
+// fifo is msgQueue that serves messages in order of fetching
+type fifo chan broker.Msg
+
+func newFifo(size int) msgQueue {
+	return make(fifo, size)
+}
+
+func (q fifo) Push(msg broker.Msg) {
+	q <- msg
+}
+
+func (q fifo) Pop(stop <-chan struct{}) (broker.Msg, bool) {
+	select {
+	case msg := <-q:
+		return msg, true
+	case <-stop:
+		return nil, false
+	}
+}
+
+func (q fifo) Drain() []broker.Msg {
+	var msgs []broker.Msg
+	for len(q) > 0 {
+		msgs = append(msgs, <-q)
+	}
+
+	return msgs
+}
+
 // TaskManager consume messages with required time interval.
 // Manager wait for Task.start timing and after run MsgHandler function.
+//
+// Every subject is pulled from broker by its own lane with bounded number of workers.
 type TaskManager struct {
-	conn *server.Connection
-
-	// gChan consume nats messages and send it to MsgTimeHadnler function.
-	// cChan consume expired messages and Terminate these with MsgTermHadnler function.
-	gChan chan *nats.Msg
-	cChan chan *nats.Msg
+	broker   broker.Broker
+	settings Settings
 
 	router map[string]HandlerGroup
-	tasks  []Task
+	lanes  map[string]*lane
+
+	// newQueue defines order in which workers take fetched messages
+	newQueue func(size int) msgQueue
+
+	mu    sync.Mutex
+	tasks []Task
 
 	// rate is time.Sleep() duration for call Task.In() in cycle one more time
 	rate time.Duration
 	stop chan struct{}
+
+	// tick is unix time in nanoseconds of the last cycle of scheduling loop,
+	// it's zero while loop isn't running
+	tick atomic.Int64
+
+	// ctx is passed to handlers and cancelled on forced shutdown.
+	// running counts fetching and working goroutines.
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running sync.WaitGroup
 }
 
 func NewTaskManager(
-	conn *server.Connection,
+	b broker.Broker,
 	router map[string]HandlerGroup,
 	stop chan struct{},
+	settings Settings,
 ) *TaskManager {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Waiting tasks are kept pending by scheduling loop, so it cycles
+	// at least twice per AckWait
+	rate := _rate
+	if settings.AckWait > 0 && settings.AckWait/2 < rate {
+		rate = settings.AckWait / 2
+	}
 
 	return &TaskManager{
-		conn:   conn,
-		gChan:  make(chan *nats.Msg, len(router)),
-		cChan:  make(chan *nats.Msg, len(router)),
-		router: router,
-		tasks:  make([]Task, 0),
-		rate:   20 * time.Second,
-		stop:   stop,
+		broker:   b,
+		settings: settings,
+		router:   router,
+		lanes:    make(map[string]*lane, len(router)),
+		newQueue: newFifo,
+		tasks:    make([]Task, 0),
+		rate:     rate,
+		stop:     stop,
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 }
 
 func (m *TaskManager) Subscribe() {
-	for subj := range m.router {
-		ch := make(chan *nats.Msg)
-		newSub, err := m.conn.ChanSubscribe(subj, ch)
+	m.mu.Lock()
+	for subj, handler := range m.router {
+		sub, err := m.broker.Subscribe(subj, m.settings.Queue)
 		if err != nil {
 			slog.Error("Subscription failed",
 				slog.String("subj", subj),
 				slog.String("ErrorMsg", err.Error()),
 			)
+			continue
+		}
+
+		limit := m.settings.limit(subj)
+		l := &lane{
+			subj:    subj,
+			sub:     sub,
+			handler: handler,
+			queue:   m.newQueue(limit.Workers + limit.Queue),
+			slots:   make(chan struct{}, limit.Workers+limit.Queue),
+			workers: limit.Workers,
+		}
+		m.lanes[subj] = l
+
+		m.running.Add(1 + l.workers)
+		go m.fetch(l)
+		for i := 0; i < l.workers; i++ {
+			go m.work(l)
 		}
-		defer newSub.Unsubscribe()
-		go func() {
-			for {
-				m.gChan <- <-ch
-			}
-		}()
 	}
+	m.mu.Unlock()
 
-	go m.consume()
+	ticker := time.NewTicker(m.rate)
+	defer ticker.Stop()
+	defer m.tick.Store(0)
 
 	for {
-		var toClean = make([]int, 0)
-		for iter, task := range m.tasks {
-			in := task.In()
-			if in == 0 {
-				m.gChan <- task.Msg
-			} else if in == 1 {
-				toClean = append(toClean, iter)
+		m.tick.Store(time.Now().UnixNano())
+
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+
+		for _, msg := range m.due() {
+			// Lane isn't there if subscription of subject failed
+			l, ok := m.lanes[msg.Subject()]
+			if !ok {
+				_ = msg.Nak()
+				continue
+			}
+			if !m.submit(l, msg) {
+				return
 			}
 		}
-		m.clean(toClean...)
-		time.Sleep(m.rate)
 	}
 }
 
-func (m *TaskManager) consume() {
+// Alive reports whether scheduling loop of delayed tasks runs. Loop that
+// hasn't cycled for two rates is stuck, e.g. on submit to busy lane.
+func (m *TaskManager) Alive() bool {
+	tick := m.tick.Load()
+
+	return tick != 0 && time.Since(time.Unix(0, tick)) < 2*m.rate
+}
+
+// Shutdown waits for in-flight handlers, no new messages are pulled
+// since stop channel is closed. When ctx is done handlers context
+// is cancelled and they have grace period to checkpoint their work.
+// Messages that weren't handled are returned to NATS for other instances.
+//
+// Manager stop channel must be closed before Shutdown call.
+func (m *TaskManager) Shutdown(ctx context.Context, grace time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		m.running.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.Warn("Shutdown deadline exceeded. In-flight handlers cancelled")
+		m.cancel()
+
+		select {
+		case <-done:
+		case <-time.After(grace):
+			err = ErrShutdownTimeout
+		}
+	}
+	m.cancel()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, l := range m.lanes {
+		for _, msg := range l.queue.Drain() {
+			_ = msg.Nak()
+			QueueDepth.WithLabelValues(l.subj).Dec()
+		}
+		if err := l.sub.Close(); err != nil {
+			slog.Warn("Unsubscription failed",
+				slog.String("subj", l.subj),
+				slog.String("ErrorMsg", err.Error()),
+			)
+		}
+	}
+	for _, task := range m.tasks {
+		_ = task.Msg.Nak()
+	}
+	m.tasks = m.tasks[:0]
+
+	return err
+}
+
+// fetch pulls messages while lane has free slots.
+// If all the slots are taken it waits and nothing is pulled.
+func (m *TaskManager) fetch(l *lane) {
+	defer m.running.Done()
+
 	for {
 		select {
+		case l.slots <- struct{}{}:
 		case <-m.stop:
 			return
-		case msg := <-m.gChan:
-			go func() {
-				ok, time := m.router[msg.Subject].Get(msg)
-				if ok != false {
-					m.cChan <- msg
-				} else if time.In() == -1 {
-					m.tasks = append(m.tasks, time)
-				}
-			}()
-		case msg := <-m.cChan:
-			go func() {
-				m.router[msg.Subject].Clean(msg)
-			}()
 		}
+
+		batch := 1
+	acquire:
+		for batch < cap(l.slots) {
+			select {
+			case l.slots <- struct{}{}:
+				batch++
+			default:
+				break acquire
+			}
+		}
+
+		msgs, err := l.sub.Fetch(m.ctx, batch, _fetchWait)
+		for _, msg := range msgs {
+			l.queue.Push(msg)
+			QueueDepth.WithLabelValues(l.subj).Inc()
+		}
+		for i := len(msgs); i < batch; i++ {
+			<-l.slots
+		}
+
+		if err != nil {
+			slog.Error("Fetching failed",
+				slog.String("subj", l.subj),
+				slog.String("ErrorMsg", err.Error()),
+			)
+
+			select {
+			case <-time.After(_fetchWait):
+			case <-m.stop:
+				return
+			}
+		}
+	}
+}
+
+func (m *TaskManager) work(l *lane) {
+	defer m.running.Done()
+
+	for {
+		msg, ok := l.queue.Pop(m.stop)
+		if !ok {
+			return
+		}
+
+		QueueDepth.WithLabelValues(l.subj).Dec()
+		m.handle(l, msg)
+		<-l.slots
 	}
 }
 
-func (m *TaskManager) clean(toClean ...int) {
-	for _, c := range toClean {
-		m.tasks = slices.Delete[[]Task, Task](m.tasks, c, c+1)
+func (m *TaskManager) handle(l *lane, msg broker.Msg) {
+	ActiveWorkers.WithLabelValues(l.subj).Inc()
+	defer ActiveWorkers.WithLabelValues(l.subj).Dec()
+	defer m.heartbeat(msg)()
+
+	start := time.Now()
+
+	ok, task := l.handler.Get(m.ctx, msg)
+	if ok {
+		l.handler.Clean(msg)
+	} else if task.In() == -1 {
+		m.mu.Lock()
+		m.tasks = append(m.tasks, task)
+		m.mu.Unlock()
 	}
+
+	ProcessingDuration.WithLabelValues(l.subj).Observe(time.Since(start).Seconds())
+}
+
+// submit returns delayed message to its lane when lane has free slot.
+// It returns false if manager is stopped.
+func (m *TaskManager) submit(l *lane, msg broker.Msg) bool {
+	select {
+	case l.slots <- struct{}{}:
+	case <-m.stop:
+		return false
+	}
+
+	l.queue.Push(msg)
+	QueueDepth.WithLabelValues(l.subj).Inc()
+
+	return true
+}
+
+// due removes ready to consume and expired tasks from the queue.
+// Expired tasks are terminated, ready tasks messages are returned.
+func (m *TaskManager) due() []broker.Msg {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ready = make([]broker.Msg, 0)
+	var waiting = m.tasks[:0]
+	for _, task := range m.tasks {
+		switch task.In() {
+		case 0:
+			ready = append(ready, task.Msg)
+		case 1:
+			m.router[task.Msg.Subject()].Clean(task.Msg)
+		default:
+			// Waiting task must not be redelivered to another instance
+			_ = task.Msg.InProgress()
+			waiting = append(waiting, task)
+		}
+	}
+	m.tasks = waiting
+
+	return ready
+}
+
+// heartbeat prolongs AckWait of message while it's handled,
+// so long sends aren't redelivered to another instance.
+// Returned function stops heartbeat.
+func (m *TaskManager) heartbeat(msg broker.Msg) func() {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(m.settings.AckWait / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = msg.InProgress()
+			}
+		}
+	}()
+
+	return func() { close(done) }
 }
@@ -0,0 +1,235 @@
+package mod
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
+)
+
+// countingBroker counts messages fetched by all the subscriptions
+type countingBroker struct {
+	*broker.Memory
+	fetched atomic.Int64
+}
+
+func (b *countingBroker) Subscribe(subj, group string) (broker.Subscription, error) {
+	sub, err := b.Memory.Subscribe(subj, group)
+	if err != nil {
+		return nil, err
+	}
+
+	return &countingSubscription{sub, b}, nil
+}
+
+type countingSubscription struct {
+	broker.Subscription
+	b *countingBroker
+}
+
+func (s *countingSubscription) Fetch(ctx context.Context, batch int, wait time.Duration) ([]broker.Msg, error) {
+	msgs, err := s.Subscription.Fetch(ctx, batch, wait)
+	s.b.fetched.Add(int64(len(msgs)))
+
+	return msgs, err
+}
+
+// blockingHandler handles message when it's released and acknowledges it.
+// Every started message is sent to started.
+func blockingHandler(started chan<- broker.Msg, release <-chan struct{}) HandlerGroup {
+	return HandlerGroup{
+		Get: func(ctx context.Context, msg broker.Msg) (bool, Task) {
+			started <- msg
+			<-release
+			return true, Task{}
+		},
+		Clean: func(msg broker.Msg) {
+			_ = msg.Ack()
+		},
+	}
+}
+
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition isn't met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func publish(t *testing.T, b broker.Publisher, subj string, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		assert.Equal(t, b.Publish(context.Background(), subj, []byte("m"), nil), nil)
+	}
+}
+
+func TestTaskManagerRate(t *testing.T) {
+	// test 1: waiting tasks are prolonged twice per short AckWait
+	{
+		m := NewTaskManager(broker.NewMemory(), nil, nil, Settings{AckWait: 10 * time.Second})
+		assert.Equal(t, m.rate, 5*time.Second)
+	}
+
+	// test 2: long AckWait doesn't slow scheduling down
+	{
+		m := NewTaskManager(broker.NewMemory(), nil, nil, Settings{AckWait: time.Hour})
+		assert.Equal(t, m.rate, _rate)
+	}
+}
+
+func TestTaskManagerLanes(t *testing.T) {
+	const (
+		busy = "test.lanes.busy"
+		free = "test.lanes.free"
+	)
+
+	b := &countingBroker{Memory: broker.NewMemory()}
+	busyStarted, freeStarted := make(chan broker.Msg, 8), make(chan broker.Msg, 8)
+	busyRelease, freeRelease := make(chan struct{}), make(chan struct{})
+	close(freeRelease)
+
+	stop := make(chan struct{})
+	m := NewTaskManager(b, map[string]HandlerGroup{
+		busy: blockingHandler(busyStarted, busyRelease),
+		free: blockingHandler(freeStarted, freeRelease),
+	}, stop, Settings{
+		Queue:        "workers",
+		AckWait:      time.Minute,
+		DefaultLimit: Limit{Workers: 2, Queue: 2},
+		Limits:       map[string]Limit{busy: {Workers: 1, Queue: 2}},
+	})
+	go m.Subscribe()
+
+	// Subscriptions are made by Subscribe
+	eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return len(m.lanes) == 2
+	})
+
+	// test 1: messages aren't fetched while workers and queue of lane are full
+	{
+		publish(t, b, busy, 6)
+		<-busyStarted
+
+		eventually(t, func() bool { return b.fetched.Load() == 3 })
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, b.fetched.Load(), int64(3))
+		assert.Equal(t, b.Pending(busy, "workers"), 6)
+	}
+
+	// test 2: gauges show held messages of lane
+	{
+		assert.Equal(t, testutil.ToFloat64(ActiveWorkers.WithLabelValues(busy)), float64(1))
+		assert.Equal(t, testutil.ToFloat64(QueueDepth.WithLabelValues(busy)), float64(2))
+	}
+
+	// test 3: busy lane doesn't hold workers of another lane
+	{
+		publish(t, b, free, 3)
+		for i := 0; i < 3; i++ {
+			<-freeStarted
+		}
+		eventually(t, func() bool { return b.Pending(free, "workers") == 0 })
+		assert.Equal(t, b.Pending(busy, "workers"), 6)
+	}
+
+	// test 4: released lane handles all the messages, gauges are back to zero
+	{
+		close(busyRelease)
+		for i := 1; i < 6; i++ {
+			<-busyStarted
+		}
+		eventually(t, func() bool { return b.Pending(busy, "workers") == 0 })
+		eventually(t, func() bool {
+			return testutil.ToFloat64(ActiveWorkers.WithLabelValues(busy)) == 0
+		})
+		assert.Equal(t, testutil.ToFloat64(QueueDepth.WithLabelValues(busy)), float64(0))
+	}
+
+	close(stop)
+	assert.Equal(t, m.Shutdown(context.Background(), time.Second), nil)
+}
+
+func TestTaskManagerShutdown(t *testing.T) {
+	const subj = "test.shutdown"
+
+	// start runs manager with one in-flight message. Handler acknowledges
+	// message when it's released or checkpoints it by Nak when it's cancelled.
+	start := func(release <-chan struct{}, cancelled chan<- error) (*broker.Memory, *TaskManager, chan struct{}) {
+		b := broker.NewMemory()
+		started := make(chan struct{})
+		stop := make(chan struct{})
+		m := NewTaskManager(b, map[string]HandlerGroup{
+			subj: {
+				Get: func(ctx context.Context, msg broker.Msg) (bool, Task) {
+					close(started)
+					select {
+					case <-release:
+						return true, Task{}
+					case <-ctx.Done():
+						cancelled <- ctx.Err()
+						_ = msg.Nak()
+						return false, Task{}
+					}
+				},
+				Clean: func(msg broker.Msg) {
+					_ = msg.Ack()
+				},
+			},
+		}, stop, Settings{Queue: "workers", AckWait: time.Minute, DefaultLimit: Limit{Workers: 1}})
+		go m.Subscribe()
+
+		eventually(t, func() bool {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			return len(m.lanes) == 1
+		})
+		publish(t, b, subj, 1)
+		<-started
+
+		return b, m, stop
+	}
+
+	// test 1: in-flight handler is finished before deadline, its message is acknowledged
+	{
+		release, cancelled := make(chan struct{}), make(chan error, 1)
+		b, m, stop := start(release, cancelled)
+
+		close(stop)
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			close(release)
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		assert.Equal(t, m.Shutdown(ctx, time.Second), nil)
+		assert.Equal(t, len(cancelled), 0)
+		assert.Equal(t, b.Pending(subj, "workers"), 0)
+	}
+
+	// test 2: handler is cancelled after deadline, its message is returned, not lost
+	{
+		release, cancelled := make(chan struct{}), make(chan error, 1)
+		b, m, stop := start(release, cancelled)
+
+		close(stop)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Equal(t, m.Shutdown(ctx, 5*time.Second), nil)
+		assert.Equal(t, errors.Is(<-cancelled, context.Canceled), true)
+		assert.Equal(t, b.Pending(subj, "workers"), 1)
+	}
+}
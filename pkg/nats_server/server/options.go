@@ -1,6 +1,10 @@
 package server
 
-import "time"
+import (
+	"time"
+
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server/mod"
+)
 
 type Option func(*Server)
 
@@ -9,3 +13,46 @@ func Timeout(timeout time.Duration) Option {
 		s.timeout = timeout
 	}
 }
+
+// Queue sets group name shared by instances to balance messages between them.
+func Queue(queue string) Option {
+	return func(s *Server) {
+		s.settings.Queue = queue
+	}
+}
+
+// AckWait sets duration after which unacked message is redelivered.
+// Non-positive duration is ignored, handled messages are kept in progress
+// every AckWait/2.
+func AckWait(ackWait time.Duration) Option {
+	return func(s *Server) {
+		if ackWait > 0 {
+			s.settings.AckWait = ackWait
+		}
+	}
+}
+
+// Limits sets concurrency bounds: def is used for subjects missed in perSubject.
+func Limits(def mod.Limit, perSubject map[string]mod.Limit) Option {
+	return func(s *Server) {
+		if def.Workers > 0 {
+			s.settings.DefaultLimit = def
+		}
+		s.settings.Limits = perSubject
+	}
+}
+
+// Transactional sets value of mod.PriorityHeader of messages which
+// PriorityManager serves first.
+func Transactional(value string) Option {
+	return func(s *Server) {
+		s.settings.Transactional = value
+	}
+}
+
+// Starvation sets bounds of bulk messages delay for PriorityManager.
+func Starvation(starvation mod.Starvation) Option {
+	return func(s *Server) {
+		s.settings.Starvation = starvation
+	}
+}
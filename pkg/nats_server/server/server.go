@@ -1,44 +1,57 @@
 package server
 
 import (
+	"context"
 	"time"
 
-	server "gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server"
+	"gitlab.com/fluxx1on_group/event_message_service/pkg/broker"
 	"gitlab.com/fluxx1on_group/event_message_service/pkg/nats_server/mod"
 )
 
-const _defaultTimeout = 2 * time.Second
+const (
+	_defaultTimeout = 2 * time.Second
+	_defaultQueue   = "workers"
+	_defaultAckWait = 1 * time.Minute
+	_defaultWorkers = 4
+	_defaultBuffer  = 16
+	_defaultRatio   = 4
+	_defaultMaxWait = 30 * time.Second
+)
 
 type Server struct {
-	conn *server.Connection
-	stop chan struct{}
+	broker broker.Broker
+	stop   chan struct{}
 
 	manager mod.Manager
 
-	timeout time.Duration
+	// timeout is grace period for cancelled handlers to checkpoint their work
+	timeout  time.Duration
+	settings mod.Settings
 }
 
 func New(
-	conn *server.Connection,
+	b broker.Broker,
 	router map[string]mod.HandlerGroup,
 	modtype mod.ManagerType,
 	opts ...Option,
 ) *Server {
 
-	stop := make(chan struct{})
-
-	var manager mod.Manager
-
-	switch modtype {
-	case mod.TaskType:
-		manager = mod.NewTaskManager(conn, router, stop)
-	}
-
 	server := &Server{
-		conn:    conn,
-		stop:    stop,
-		manager: manager,
+		broker:  b,
+		stop:    make(chan struct{}),
 		timeout: _defaultTimeout,
+		settings: mod.Settings{
+			Queue:   _defaultQueue,
+			AckWait: _defaultAckWait,
+			DefaultLimit: mod.Limit{
+				Workers: _defaultWorkers,
+				Queue:   _defaultBuffer,
+			},
+			Starvation: mod.Starvation{
+				Ratio:   _defaultRatio,
+				MaxWait: _defaultMaxWait,
+			},
+		},
 	}
 
 	// Custom options
@@ -46,6 +59,13 @@ func New(
 		opt(server)
 	}
 
+	switch modtype {
+	case mod.PriorityType:
+		server.manager = mod.NewPriorityManager(b, router, server.stop, server.settings)
+	default:
+		server.manager = mod.NewTaskManager(b, router, server.stop, server.settings)
+	}
+
 	return server
 }
 
@@ -53,8 +73,15 @@ func (s *Server) StartWorkers() {
 	s.manager.Subscribe()
 }
 
-func (s *Server) Shutdown() {
+// Alive reports whether scheduler of delayed messages runs
+func (s *Server) Alive() bool {
+	return s.manager.Alive()
+}
+
+// Shutdown stops consumption and waits for in-flight handlers until ctx is done.
+// Broker stays opened, so handlers can publish checkpoints.
+func (s *Server) Shutdown(ctx context.Context) error {
 	close(s.stop)
 
-	s.conn.Close(s.timeout)
+	return s.manager.Shutdown(ctx, s.timeout)
 }
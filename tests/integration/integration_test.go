@@ -18,7 +18,7 @@ const (
 
 const (
 	// Attempts connection
-	healthPath = "http://" + app_host + "/healthz"
+	healthPath = "http://" + app_host + "/readyz"
 	attempts   = 5
 
 	// HTTP REST
@@ -83,13 +83,15 @@ func TestHTTP(t *testing.T) {
 		Description("Client updating succeeded"),
 		Method(http.MethodPatch, basePath+"/client"),
 		Send().Headers("Content-Type").Add("application/json"),
+		Send().Headers("If-Match").Add(`"1"`),
 		Send().Body().String(body),
 		Expect().Status().Equal(http.StatusNoContent),
 	)
 
 	// DELETE
 	body = `{
-		"id": 1
+		"id": 1,
+		"version": 2
 	}`
 	Test(t,
 		Description("Client deletion succeeded"),
@@ -140,13 +142,15 @@ func TestHTTP(t *testing.T) {
 		Description("Mailing updating succeeded"),
 		Method(http.MethodPatch, basePath+"/mailing"),
 		Send().Headers("Content-Type").Add("application/json"),
+		Send().Headers("If-Match").Add(`"1"`),
 		Send().Body().String(body),
 		Expect().Status().Equal(http.StatusNoContent),
 	)
 
 	// DELETE
 	body = `{
-		"id": 1
+		"id": 1,
+		"version": 2
 	}`
 	Test(t,
 		Description("Mailing deletion succeeded"),